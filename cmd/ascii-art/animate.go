@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/vxanthio/ascii-art-color/internal/animate"
+	"github.com/vxanthio/ascii-art-color/internal/renderer"
+	"github.com/vxanthio/ascii-art-color/internal/terminal"
+)
+
+const (
+	// defaultFPS is the frame rate of terminal animations without --fps.
+	defaultFPS = 10
+	// maxFPS is the largest --fps.
+	maxFPS = 60
+)
+
+// runAnimation plays the --animate animation of the text on out, redrawing
+// the block of rows in place, until the animation ends or the program is
+// interrupted. The cursor is hidden while the animation plays and shown again
+// afterwards, also on SIGINT and SIGTERM, which end the animation normally.
+//
+// Parameters:
+//   - out: The file to play the animation on.
+//   - opts: The parsed command-line options.
+func runAnimation(out *os.File, opts cliOptions) {
+	if opts.text == stdinArg {
+		opts.text = readText(opts)
+	}
+
+	window := outputWidth(opts, out)
+	var glyphs []image.Rectangle
+	if opts.animation == animate.EffectMarquee {
+		// The marquee scrolls each line whole through the window.
+		opts.width, opts.widthSet = 0, true
+	} else {
+		glyphs = glyphCells(out, opts)
+	}
+
+	var buf bytes.Buffer
+	render(&buf, out, opts)
+	rows := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	frames := animate.Frames(rows, opts.animation, animate.Options{Glyphs: glyphs, Width: window})
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	fps := opts.fps
+	if fps <= 0 {
+		fps = defaultFPS
+	}
+	if err := play(out, frames, time.Second/time.Duration(fps), opts.animation.Repeats(), interrupt); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		exit(exitCodeOutputError)
+	}
+}
+
+// play draws frames one over the other, one per interval, with the cursor
+// hidden. Each frame moves the cursor back up over the rows of the previous
+// one and rewrites them, clearing what is left of each line.
+//
+// Parameters:
+//   - w: The terminal to draw on.
+//   - frames: The frames, each a list of rows.
+//   - interval: The time between two frames.
+//   - repeat: Whether to start over after the last frame, until stopped,
+//     rather than return.
+//   - stop: A channel that ends the animation when it receives, leaving the
+//     current frame on screen.
+//
+// Returns:
+//   - An error if writing to w fails.
+func play(w io.Writer, frames [][]string, interval time.Duration, repeat bool, stop <-chan os.Signal) (err error) {
+	if len(frames) == 0 {
+		return nil
+	}
+	if _, err := io.WriteString(w, terminal.HideCursor); err != nil {
+		return err
+	}
+	defer func() {
+		if _, showErr := io.WriteString(w, terminal.ShowCursor); err == nil {
+			err = showErr
+		}
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	height := 0
+	for i := 0; ; i++ {
+		if i == len(frames) {
+			i = 0
+		}
+		var b strings.Builder
+		b.WriteString(terminal.CursorUp(height))
+		for _, row := range frames[i] {
+			b.WriteString(row + terminal.ClearToEnd + "\n")
+		}
+		height = len(frames[i])
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+
+		if !repeat && i == len(frames)-1 {
+			return nil
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// glyphCells returns the cells of every character of the text, in reading
+// order, for the typewriter effect of GIF output and for terminal
+// animations.
+//
+// Each line is laid out as the renderer lays it out: wrapped into pieces that
+// fit the output width, each piece a block of rows as high as the banner, and
+// an empty line a single row. A character covers the columns of its glyph,
+// as renderer.Widths measures them, over the rows of its block; characters
+// without columns, such as a zero-width fallback, are skipped.
+//
+// Parameters:
+//   - out: The file the output ends up in.
+//   - opts: The parsed command-line options; opts.text must not be stdinArg.
+//
+// Returns:
+//   - One rectangle of cells per character, in columns and rows.
+func glyphCells(out *os.File, opts cliOptions) []image.Rectangle {
+	banner, renderOpts := loadBanner(opts.banner, opts)
+	applyOutputOptions(&renderOpts, opts, out)
+
+	var cells []image.Rectangle
+	top := 0
+	eachLine(opts.text, opts, func(line string) {
+		if line == "" {
+			top++
+			return
+		}
+		pieces, err := renderer.WrapLine(line, banner, renderOpts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering text: %v\n", err)
+			exit(exitCodeRenderError)
+		}
+		for _, piece := range pieces {
+			widths, err := renderer.Widths(piece, banner, renderOpts)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error rendering text: %v\n", err)
+				exit(exitCodeRenderError)
+			}
+			left := 0
+			for _, width := range widths {
+				if width > 0 {
+					cells = append(cells, image.Rect(left, top, left+width, top+banner.Height))
+				}
+				left += width
+			}
+			top += banner.Height
+		}
+	})
+	return cells
+}
@@ -3,37 +3,237 @@ package main
 import (
 	"errors"
 	"strings"
+
+	"github.com/vxanthio/ascii-art-color/internal/flagparser"
 )
 
-// ParseArgs parses command-line arguments and extracts text and banner name.
+// errTextUsage is the usage error of the plain text [banner] form.
+var errTextUsage = errors.New("usage: go run . \"text\" [banner]")
+
+// errColorUsage is the usage error of the color form and of malformed
+// options, worded as the project specification requires.
+//
+//nolint:staticcheck // ST1005: capitalized per project specification
+var errColorUsage = errors.New("Usage: go run . [OPTION] [STRING]\n\nEX: go run . --color=<color> <substring to be colored> \"something\"")
+
+// positional identifies what a positional argument holds.
+type positional int
+
+const (
+	positionalSubstring positional = iota
+	positionalText
+	positionalBanner
+)
+
+// assignPositionals interprets the positional arguments of the command line.
+//
+// The positional forms are those of the original command line:
+//   - text [banner]
+//   - --color=<color> [substring] text [banner]
+//
+// An argument is expected only for what no option already provides: there is
+// no banner argument with --banner, no substring argument with --substring or
+// when every --color, --gradient and --palette names its own text
+// (COLOR:TEXT), and no text argument with --input or --reverse. When one
+// optional argument is left out and both a substring and a banner are
+// expected, the last argument is the banner if it names one, and the
+// substring's neighbor otherwise: "--color=red hello shadow" renders hello
+// with shadow, while "--color=red ell hello" colors ell in hello.
+//
+// Without a text argument, the text is read from standard input when it is
+// redirected, and the usage error is returned otherwise.
+//
+// Parameters:
+//   - opts: The options to fill in; every coloring option, substring, banner
+//     and input must already be set from the option flags.
+//   - result: The parsed command line.
+//   - stdinPiped: Whether standard input is redirected.
 //
-// The function validates argument count, extracts the text argument, interprets
-// escape sequences (like \n), and determines the banner name (defaulting to "standard"
-// if not provided).
+// Returns:
+//   - An error, including the usage text, if there are too many or too few
+//     arguments.
+func assignPositionals(opts *cliOptions, result *flagparser.Result, stdinPiped bool) error {
+	colorMode := opts.colored()
+	usage := errTextUsage
+	if colorMode {
+		usage = errColorUsage
+	}
+
+	var slots []positional
+	if opts.hasBareRule() && !result.IsSet(substringOption) {
+		slots = append(slots, positionalSubstring)
+	}
+	if opts.input == "" && opts.reverse == "" {
+		slots = append(slots, positionalText)
+	}
+	if !result.IsSet(bannerOption) {
+		slots = append(slots, positionalBanner)
+	}
+
+	args := result.Args
+	if len(args) > len(slots) {
+		if colorMode {
+			return usage
+		}
+		return errors.New("too many arguments\n" + usage.Error())
+	}
+	slots = dropOptionalSlots(slots, args)
+
+	textSet := false
+	for i, slot := range slots {
+		switch slot {
+		case positionalSubstring:
+			opts.substring = args[i]
+		case positionalText:
+			opts.text = strings.ReplaceAll(args[i], "\\n", "\n")
+			textSet = true
+		case positionalBanner:
+			opts.banner, opts.bannerSet = args[i], true
+		}
+	}
+
+	switch {
+	case opts.reverse != "":
+	case opts.input != "":
+		opts.text = stdinArg
+	case !textSet && stdinPiped:
+		opts.text = stdinArg
+	case !textSet:
+		return usage
+	}
+	return nil
+}
+
+// dropOptionalSlots removes the optional substring and banner slots that the
+// given arguments leave unfilled, so that the remaining slots match the
+// arguments one to one.
 //
 // Parameters:
-//   - args: Command-line arguments slice (args[0] is program name).
+//   - slots: The expected positional arguments, in order.
+//   - args: The positional arguments given.
 //
 // Returns:
-//   - text: The text to render (with escape sequences interpreted).
-//   - banner: The banner name to use.
-//   - err: An error if argument validation fails.
-func ParseArgs(args []string) (text string, banner string, err error) {
-	if len(args) < 2 {
-		return "", "", errors.New("usage: go run . \"text\" [banner]")
+//   - The slots to fill, in order; at most len(args) of them.
+func dropOptionalSlots(slots []positional, args []string) []positional {
+	missing := len(slots) - len(args)
+	if missing <= 0 {
+		return slots
+	}
+	if len(args) == 0 {
+		return nil
 	}
 
-	if len(args) > 3 {
-		return "", "", errors.New("too many arguments\nusage: go run . \"text\" [banner]")
+	optional := 0
+	for _, slot := range slots {
+		if slot != positionalText {
+			optional++
+		}
 	}
 
-	text = strings.ReplaceAll(args[1], "\\n", "\n")
+	drop := func(slot positional) bool {
+		if slot == positionalText {
+			return false
+		}
+		if missing >= optional {
+			return true
+		}
+		if isValidBanner(args[len(args)-1]) {
+			return slot == positionalSubstring
+		}
+		return slot == positionalBanner
+	}
 
-	if len(args) == 3 {
-		banner = args[2]
-	} else {
-		banner = defaultBanner
+	kept := make([]positional, 0, len(args))
+	for _, slot := range slots {
+		if !drop(slot) {
+			kept = append(kept, slot)
+		}
 	}
+	return kept
+}
+
+// helpText returns the text printed by --help.
+//
+// Returns:
+//   - The help text, ending with a newline.
+func helpText() string {
+	var b strings.Builder
+	b.WriteString(`Usage: ascii-art [OPTION]... [TEXT] [BANNER]
+       ascii-art --color=COLOR [OPTION]... [SUBSTRING] TEXT [BANNER]
+       ascii-art --color=COLOR:TEXT [--color=COLOR:TEXT]... [OPTION]... TEXT [BANNER]
+       ascii-art --gradient=COLOR,COLOR[,COLOR]...[:TEXT] [OPTION]... [SUBSTRING] TEXT [BANNER]
+       ascii-art --rainbow | --palette=NAME[:TEXT] [OPTION]... [SUBSTRING] TEXT [BANNER]
+       ascii-art --bg=COLOR [--attr=LIST] [OPTION]... [SUBSTRING] TEXT [BANNER]
+
+Render TEXT as ASCII art. A "\n" in TEXT starts a new block of rows. With "-"
+as TEXT, or no TEXT while standard input is redirected, the text is read from
+standard input. BANNER is a built-in banner (standard, shadow or thinkertoy),
+a banner of the ASCII_ART_FONT_PATH directories, or the path of a banner
+file; --list-banners shows the banners it can name.
+
+Options (a long option takes its value after "=", as in --width=40):
+`)
+	b.WriteString(flagparser.Help(cliFlags))
+	b.WriteString(`
+Long options take their value after "=" (--width=40, not --width 40); short
+options take it attached or as the next argument (-w40, -w 40). Options may
+appear anywhere, and "--" ends them. TEXT may start with "-" when the letter
+after it is not a short option, as in ascii-art -5; otherwise put it after
+"--", as in ascii-art -- -hello.
+
+--color can be repeated to color several substrings, as in
+--color=red:ERROR --color=green:OK. Where rules overlap, the one given last
+wins, so a plain --color=COLOR first sets a base color for the whole text.
+
+--gradient blends its colors across the columns of each rendered block, or
+across its rows with --gradient-direction=vertical; the colors are spread
+evenly. --rainbow and --palette give each glyph the next color of a palette,
+or each word or row with --cycle. Palettes come first, then gradients, then
+--color rules, and the later ones win where they overlap.
+
+--bg and --attr add a background and attributes to every coloring rule, as
+in --color=red --bg=navy --attr=bold,underline. On their own, they style the
+SUBSTRING, or the whole text.
+
+--match, --ignore-case and --occurrence apply to the TEXT of every color
+rule: --match=regex --color=red:'v[0-9]+' colors version numbers, and
+--match=word --ignore-case --occurrence=2 colors the second whole-word match
+regardless of case.
+
+Colors are written for the color depth of the terminal: 24-bit colors are
+downgraded to 256 or 16 colors, and output that is not a terminal is not
+colored. NO_COLOR and FORCE_COLOR (0, 1, 2 or 3) are honored; --color-mode
+overrides them all.
+
+--format=html writes the art as an HTML <pre> element with a styled <span>
+for every colored run; --html-document makes it a complete page and
+--html-classes uses CSS classes instead of inline styles. --format=svg writes
+a scalable image sized to the art, with --font-size and a --canvas color.
+--format=png draws the art with a built-in bitmap font, enlarged --scale
+times and surrounded by --padding pixels; write it with --output or redirect
+it, as it is never written to a terminal. --format=gif animates the same
+image with an --effect: typewriter, marquee, rainbow, or blink, which
+blinks the text given --attr=blink.
+
+--animate plays the art in the terminal, redrawn in place --fps times a
+second: typewriter types it once; marquee scrolls it through the output width
+and wave moves its characters up and down until Ctrl-C.
+
+--output writes to a temporary file that replaces PATH once complete; an
+existing PATH is only replaced with --force. Text written there has no colors
+unless --color-mode is given, and without --format the extension of PATH
+(.txt, .html, .svg, .png or .gif) picks the format.
+
+A banner that contains a path separator or ends with .txt is read from that
+file; other names are looked up as NAME.txt in the directories of
+ASCII_ART_FONT_PATH, then among the built-in banners. --list-banners shows
+them all.
+
+--reverse=FILE prints the text of art the program wrote, trying every banner
+--list-banners shows unless a banner is given.
 
-	return text, banner, nil
+Exit status: 0 success, 1 usage error, 2 banner error, 3 rendering error,
+4 color error, 5 input error, 6 output error.
+`)
+	return b.String()
 }
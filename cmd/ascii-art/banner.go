@@ -1,66 +1,330 @@
 package main
 
 import (
-	"embed"
 	"fmt"
+	"io"
 	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/vxanthio/ascii-art-color/internal/banners"
+	"github.com/vxanthio/ascii-art-color/internal/parser"
+	"github.com/vxanthio/ascii-art-color/internal/renderer"
+)
+
+const (
+	// fontPathEnv names the environment variable listing the directories
+	// searched for banner files, separated like PATH, before the built-in
+	// banners.
+	fontPathEnv = "ASCII_ART_FONT_PATH"
+	// bannerExt is the extension of banner files.
+	bannerExt = ".txt"
+	// builtinOrigin describes the built-in banners in --list-banners.
+	builtinOrigin = "built in"
 )
 
-// bannerFS embeds the testdata directory into the compiled binary.
-// This makes the binary fully self-contained and relocatable - it can be run
-// from any directory without requiring testdata files to exist on disk.
-// The embedded files are read-only and frozen at compile time.
+// layeredFS is a read-only file system made of layers searched in order: a
+// name opens the file of the first layer that has it, and a directory lists
+// the entries of every layer, the first layer's entry winning for each name.
+type layeredFS struct {
+	layers []fs.FS
+	// origins describes where the files of each layer come from.
+	origins []string
+}
+
+// Open opens the named file of the first layer that has it.
+func (l layeredFS) Open(name string) (fs.File, error) {
+	i, err := l.locate(name)
+	if err != nil {
+		return nil, err
+	}
+	return l.layers[i].Open(name)
+}
+
+// ReadDir lists the named directory of every layer, sorted by name. Layers
+// without the directory are skipped.
+func (l layeredFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	var entries []fs.DirEntry
+	seen := map[string]bool{}
+	found := false
+	for _, layer := range l.layers {
+		list, err := fs.ReadDir(layer, name)
+		if err != nil {
+			continue
+		}
+		found = true
+		for _, entry := range list {
+			if !seen[entry.Name()] {
+				seen[entry.Name()] = true
+				entries = append(entries, entry)
+			}
+		}
+	}
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return entries, nil
+}
+
+// locate returns the index of the first layer that has the named file.
+func (l layeredFS) locate(name string) (int, error) {
+	if !fs.ValidPath(name) {
+		return 0, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	for i, layer := range l.layers {
+		if _, err := fs.Stat(layer, name); err == nil {
+			return i, nil
+		}
+	}
+	return 0, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// fontSearchPath returns the directories of ASCII_ART_FONT_PATH, in order.
+// Empty entries are skipped and a leading ~ stands for the home directory.
 //
-//go:embed testdata/*.txt
-var bannerFS embed.FS
+// Returns:
+//   - The directories to search for banner files.
+func fontSearchPath() []string {
+	var dirs []string
+	for _, dir := range filepath.SplitList(os.Getenv(fontPathEnv)) {
+		if dir == "" {
+			continue
+		}
+		if dir == "~" || strings.HasPrefix(dir, "~"+string(filepath.Separator)) {
+			if home, err := os.UserHomeDir(); err == nil {
+				dir = filepath.Join(home, dir[1:])
+			}
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
 
-var bannerPaths = map[string]string{
-	"standard":   "testdata/standard.txt",
-	"shadow":     "testdata/shadow.txt",
-	"thinkertoy": "testdata/thinkertoy.txt",
+// bannerLayers returns the file system banner names are resolved in: the
+// directories of ASCII_ART_FONT_PATH, in order, then the built-in banners.
+// Every banner is a file named after it, with the .txt extension, at the
+// root of the file system.
+func bannerLayers() layeredFS {
+	var l layeredFS
+	for _, dir := range fontSearchPath() {
+		l.layers = append(l.layers, os.DirFS(dir))
+		l.origins = append(l.origins, dir)
+	}
+	l.layers = append(l.layers, banners.FS)
+	l.origins = append(l.origins, builtinOrigin)
+	return l
 }
 
 // GetBannerPath converts a banner name to its corresponding file path.
 //
-// The function validates the banner name against a predefined map of valid banners
-// (standard, shadow, thinkertoy) and returns the appropriate file path in the testdata
-// directory.
+// The function looks the banner name up in the directories of
+// ASCII_ART_FONT_PATH and among the built-in banners (standard, shadow,
+// thinkertoy), and returns the path of its file in the file system returned
+// by GetBannerFS.
 //
 // Parameters:
 //   - banner: The banner name to resolve.
 //
 // Returns:
 //   - The file path to the banner file.
-//   - An error if the banner name is invalid.
+//   - An error if no banner has that name.
 func GetBannerPath(banner string) (string, error) {
-	path, exists := bannerPaths[banner]
-	if !exists {
-		return "", fmt.Errorf("invalid banner name: %q\nValid options: standard, shadow, thinkertoy", banner)
+	path := banner + bannerExt
+	if isBannerFile(banner) || !fs.ValidPath(path) {
+		return "", invalidBannerError(banner)
+	}
+	if _, err := fs.Stat(GetBannerFS(), path); err != nil {
+		return "", invalidBannerError(banner)
 	}
-
 	return path, nil
 }
 
-// isValidBanner checks whether a string is a recognized banner name.
+// invalidBannerError returns the error for an unknown banner name, listing
+// the banners that exist.
+func invalidBannerError(banner string) error {
+	var names []string
+	for _, entry := range findBanners() {
+		names = append(names, entry.name)
+	}
+	return fmt.Errorf("invalid banner name: %q\nValid options: %s, or the path of a banner file", banner, strings.Join(names, ", "))
+}
+
+// isBannerFile reports whether a banner is given as the path of a file rather
+// than by name: when it contains a path separator or ends with .txt.
 //
 // Parameters:
-//   - name: The banner name to validate.
+//   - banner: The --banner value or banner argument.
 //
 // Returns:
-//   - true if the banner name is valid (standard, shadow, or thinkertoy), false otherwise.
+//   - true for a file path, false for a banner name.
+func isBannerFile(banner string) bool {
+	return strings.ContainsAny(banner, "/"+string(filepath.Separator)) || strings.HasSuffix(banner, bannerExt)
+}
+
+// isValidBanner checks whether a string names a banner or an existing banner
+// file.
+//
+// Parameters:
+//   - name: The banner name or path to validate.
+//
+// Returns:
+//   - true if name is a known banner or the path of a file, false otherwise.
 func isValidBanner(name string) bool {
-	_, exists := bannerPaths[name]
-	return exists
+	if isBannerFile(name) {
+		info, err := os.Stat(name)
+		return err == nil && info.Mode().IsRegular()
+	}
+	_, err := GetBannerPath(name)
+	return err == nil
 }
 
-// GetBannerFS returns the embedded filesystem containing banner files.
+// GetBannerFS returns the file system that banner names are resolved in.
 //
-// The filesystem is embedded at compile time and contains all banner files
-// from the testdata directory. This allows the binary to be fully relocatable
-// and run from any directory without requiring external data files.
+// It layers the directories of ASCII_ART_FONT_PATH over the built-in banners,
+// which are embedded at compile time, so that the binary runs from any
+// directory without requiring external data files and user banners can add
+// to or replace the built-in ones.
 //
 // Returns:
-//   - An fs.FS interface to the embedded testdata directory.
+//   - An fs.FS holding a <name>.txt file for every banner.
 func GetBannerFS() fs.FS {
-	return bannerFS
+	return bannerLayers()
+}
+
+// bannerEntry is a banner found by findBanners.
+type bannerEntry struct {
+	name string
+	// origin is the path of the banner file, or builtinOrigin.
+	origin string
+}
+
+// findBanners returns every banner that can be named: the built-in banners,
+// in their usual order, followed by the other banners of the search path in
+// alphabetical order. A banner of the search path with the name of a built-in
+// banner replaces it.
+//
+// Returns:
+//   - The banners and where their files come from.
+func findBanners() []bannerEntry {
+	layers := bannerLayers()
+	origin := func(name string) string {
+		i, err := layers.locate(name + bannerExt)
+		if err != nil || layers.origins[i] == builtinOrigin {
+			return builtinOrigin
+		}
+		return filepath.Join(layers.origins[i], name+bannerExt)
+	}
+
+	var found []bannerEntry
+	for _, name := range banners.Names {
+		found = append(found, bannerEntry{name: name, origin: origin(name)})
+	}
+	entries, _ := layers.ReadDir(".")
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), bannerExt)
+		if !ok || name == "" || !entry.Type().IsRegular() || slices.Contains(banners.Names, name) {
+			continue
+		}
+		found = append(found, bannerEntry{name: name, origin: origin(name)})
+	}
+	return found
+}
+
+// writeBannerList writes the --list-banners listing: one banner per line,
+// its name and where its file comes from.
+//
+// Parameters:
+//   - w: The writer to write the listing to.
+//
+// Returns:
+//   - An error if writing fails.
+func writeBannerList(w io.Writer) error {
+	list := findBanners()
+	width := 0
+	for _, banner := range list {
+		width = max(width, len(banner.name))
+	}
+	var b strings.Builder
+	for _, banner := range list {
+		fmt.Fprintf(&b, "%-*s  %s\n", width, banner.name, banner.origin)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// loadBanner loads the glyphs used for rendering together with the layout
+// options to render them with, and exits on failure.
+//
+// When a FIGlet font file was given with --font it is loaded from disk and
+// takes precedence over the banner name, and the font's own layout is used
+// unless --layout overrides it. Otherwise the banner is loaded by
+// loadBannerFile and rendered at full width unless --layout is given.
+//
+// Parameters:
+//   - name: The banner name or file path to use when no font file is given.
+//   - opts: The parsed command-line options.
+//
+// Returns:
+//   - The loaded Banner.
+//   - The renderer options for the selected layout and fallback.
+func loadBanner(name string, opts cliOptions) (parser.Banner, renderer.Options) {
+	if opts.font != "" {
+		font, err := parser.LoadFIGletFont(os.DirFS(filepath.Dir(opts.font)), filepath.Base(opts.font))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading font file: %v\n", err)
+			exit(exitCodeBannerError)
+		}
+		banner := font.RawBanner()
+
+		renderOpts := renderer.Options{
+			Hardblank: font.Header.Hardblank,
+			Fallback:  opts.fallback,
+		}
+		renderOpts.Layout, renderOpts.Rules = renderer.FIGletLayout(font.Header.FullLayout)
+		if opts.layoutSet {
+			renderOpts.Layout = opts.layout
+		}
+		if renderOpts.Rules == 0 {
+			renderOpts.Rules = renderer.AllSmushRules
+		}
+		return banner, renderOpts
+	}
+
+	return loadBannerFile(name), renderer.Options{
+		Layout:   opts.layout,
+		Rules:    renderer.AllSmushRules,
+		Fallback: opts.fallback,
+	}
+}
+
+// loadBannerFile loads the banner a name selects, and exits on failure: from
+// the file it names, when it is a path, or else from the banner file system
+// (see GetBannerFS).
+//
+// Parameters:
+//   - name: The banner name or file path.
+//
+// Returns:
+//   - The loaded Banner.
+func loadBannerFile(name string) parser.Banner {
+	fsys, bannerPath := GetBannerFS(), ""
+	if isBannerFile(name) {
+		fsys, bannerPath = os.DirFS(filepath.Dir(name)), filepath.Base(name)
+	} else {
+		var err error
+		if bannerPath, err = GetBannerPath(name); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			exit(exitCodeUsageError)
+		}
+	}
+
+	banner, err := parser.LoadBanner(fsys, bannerPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading banner file: %v\n", err)
+		exit(exitCodeBannerError)
+	}
+	return banner
 }
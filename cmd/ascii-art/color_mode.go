@@ -3,139 +3,356 @@ package main
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
-	"ascii-art-color/internal/color"
-	"ascii-art-color/internal/coloring"
-	"ascii-art-color/internal/flagparser"
-	"ascii-art-color/internal/parser"
-	"ascii-art-color/internal/renderer"
+	"github.com/vxanthio/ascii-art-color/internal/color"
+	"github.com/vxanthio/ascii-art-color/internal/coloring"
+	"github.com/vxanthio/ascii-art-color/internal/parser"
+	"github.com/vxanthio/ascii-art-color/internal/renderer"
 )
 
-// runColorMode handles execution when the --color flag is detected.
+// runColorMode handles execution when a coloring option is given: --color,
+// --gradient, --palette, --rainbow, --bg or --attr.
 //
-// The function validates color mode arguments, parses the color specification,
-// loads the banner, and renders ASCII art with ANSI color codes applied.
-// It exits with appropriate error codes if validation or rendering fails.
+// The function builds the coloring rules, loads the banner, and renders ASCII
+// art with ANSI styles applied. It exits with appropriate error codes if
+// validation or rendering fails.
 //
 // Parameters:
-//   - args: Command-line arguments including os.Args[0].
-func runColorMode(args []string) {
-	if err := flagparser.ParseArgs(args); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(exitCodeUsageError)
+//   - w: The writer to write the output to.
+//   - out: The file the output ends up in.
+//   - opts: The parsed command-line options.
+func runColorMode(w io.Writer, out *os.File, opts cliOptions) {
+	rules, err := colorRules(opts, outputColorMode(opts, out))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exit(exitCodeColorError)
 	}
 
-	colorSpec, substring, text, bannerName, err := extractColorArgs(args)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(exitCodeUsageError)
+	charMap, renderOpts := loadBanner(opts.banner, opts)
+	applyOutputOptions(&renderOpts, opts, out)
+
+	printer := blockPrinter{w: w, width: renderOpts.Width, align: renderOpts.Align}
+
+	eachLine(opts.text, opts, func(line string) {
+		if line == "" {
+			printer.add(coloredBlock{})
+			return
+		}
+
+		pieces, err := renderer.WrapLine(line, charMap, renderOpts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering text: %v\n", err)
+			exit(exitCodeRenderError)
+		}
+		for _, piece := range pieces {
+			printer.add(renderColored(piece, rules, charMap, renderOpts))
+		}
+	})
+	printer.flush()
+}
+
+// coloredBlock is one rendered and colored line of text, together with what
+// AlignRows needs to position it.
+type coloredBlock struct {
+	line   string
+	rows   []string
+	widths []int
+}
+
+// blockPrinter writes colored blocks as soon as they are rendered. Blocks
+// aligned to the widest block, without an output width, are held back until
+// flush, since the widest block is known only once every line is rendered.
+type blockPrinter struct {
+	w     io.Writer
+	width int
+	align renderer.Align
+	held  []coloredBlock
+}
+
+// add writes block, or holds it back until flush.
+func (p *blockPrinter) add(block coloredBlock) {
+	if p.align != renderer.AlignLeft && p.width <= 0 {
+		p.held = append(p.held, block)
+		return
 	}
+	printBlock(p.w, block, p.width, p.align)
+}
 
-	rgb, err := color.Parse(colorSpec)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(exitCodeColorError)
+// flush writes the held blocks aligned to the widest of them.
+func (p *blockPrinter) flush() {
+	target := 0
+	for _, block := range p.held {
+		if len(block.rows) > 0 {
+			target = max(target, renderer.VisibleWidth(block.rows[0]))
+		}
+	}
+	for _, block := range p.held {
+		printBlock(p.w, block, target, p.align)
 	}
+	p.held = nil
+}
 
-	bannerPath, err := GetBannerPath(bannerName)
+// printBlock writes the rows of block to w, aligned within width columns; a
+// block without rows writes an empty line.
+func printBlock(w io.Writer, block coloredBlock, width int, align renderer.Align) {
+	if len(block.rows) == 0 {
+		fmt.Fprintln(w)
+		return
+	}
+	for _, row := range renderer.AlignRows(block.rows, block.line, block.widths, width, align) {
+		fmt.Fprintln(w, row)
+	}
+}
+
+// renderColored renders a single line of text that already fits the output
+// width and applies the color rules, exiting on failure.
+//
+// Parameters:
+//   - line: A single line of text without newline characters.
+//   - rules: The color rules, from lowest to highest precedence.
+//   - banner: The banner to render with.
+//   - renderOpts: The renderer options.
+//
+// Returns:
+//   - The colored block; it has no rows when line is empty.
+func renderColored(line string, rules []coloring.Rule, banner parser.Banner, renderOpts renderer.Options) coloredBlock {
+	if line == "" {
+		return coloredBlock{}
+	}
+
+	renderOpts.Align = renderer.AlignLeft
+	art, err := renderer.ASCIIWithOptions(line, banner, renderOpts)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(exitCodeUsageError)
+		fmt.Fprintf(os.Stderr, "Error rendering text: %v\n", err)
+		exit(exitCodeRenderError)
 	}
 
-	charMap, err := parser.LoadBanner(GetBannerFS(), bannerPath)
+	widths, err := renderer.Widths(line, banner, renderOpts)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading banner file: %v\n", err)
-		os.Exit(exitCodeBannerError)
+		fmt.Fprintf(os.Stderr, "Error rendering text: %v\n", err)
+		exit(exitCodeRenderError)
 	}
 
-	colorCode := color.ANSI(rgb)
-	lines := strings.Split(text, "\n")
+	artLines := strings.Split(strings.TrimSuffix(art, "\n"), "\n")
+	return coloredBlock{
+		line:   line,
+		rows:   coloring.ApplyRules(artLines, line, rules, widths),
+		widths: widths,
+	}
+}
 
-	for i, line := range lines {
-		if line == "" {
-			if i < len(lines)-1 {
-				fmt.Println()
-			}
-			continue
+// colorRules builds the coloring rules of the command line, from lowest to
+// highest precedence: palettes, then gradients, then --color rules.
+//
+// --bg and --attr add their background and attributes to the style of every
+// rule. Given without any other coloring option, they style the substring
+// argument, or the whole text, on their own.
+//
+// Parameters:
+//   - opts: The parsed command-line options.
+//   - mode: The color depth every style is written for.
+//
+// Returns:
+//   - The rules.
+//   - An error if a color, gradient, palette or regular expression is
+//     invalid.
+func colorRules(opts cliOptions, mode color.Mode) ([]coloring.Rule, error) {
+	base := color.Style{Attrs: opts.attrs, Mode: mode}
+	if opts.background != "" {
+		rgb, err := color.Parse(opts.background)
+		if err != nil {
+			return nil, fmt.Errorf("invalid background: %w", err)
 		}
+		base.Background = &rgb
+	}
 
-		art, err := renderer.ASCII(line, charMap)
+	rules := make([]coloring.Rule, 0, len(opts.palettes)+len(opts.gradients)+len(opts.colors)+1)
+	add := func(substring string, hasSubstring bool, style coloring.Style) (*coloring.Rule, error) {
+		if !hasSubstring {
+			substring = opts.substring
+		}
+		rule, err := newColorRule(substring, style, opts.match)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error rendering text: %v\n", err)
-			os.Exit(exitCodeRenderError)
+			return nil, err
 		}
+		rules = append(rules, rule)
+		return &rules[len(rules)-1], nil
+	}
 
-		artLines := strings.Split(strings.TrimSuffix(art, "\n"), "\n")
-		widths := parser.CharWidths(line, charMap)
-		colored := coloring.ApplyColor(artLines, line, substring, colorCode, widths)
+	for _, value := range opts.palettes {
+		colors, substring, hasSubstring, err := parsePaletteRule(value)
+		if err != nil {
+			return nil, err
+		}
+		rule, err := add(substring, hasSubstring, nil)
+		if err != nil {
+			return nil, err
+		}
+		rule.Palette, rule.Cycle = paletteStyles(colors, base), opts.cycle
+	}
+	for _, value := range opts.gradients {
+		stops, substring, hasSubstring, err := parseGradientRule(value)
+		if err != nil {
+			return nil, err
+		}
+		rule, err := add(substring, hasSubstring, nil)
+		if err != nil {
+			return nil, err
+		}
+		rule.Shader = gradientShader(color.Gradient{Stops: stops, Space: opts.space}, opts.vertical, base)
+	}
+	for _, value := range opts.colors {
+		rgb, substring, hasSubstring, err := parseColorRule(value)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := add(substring, hasSubstring, base.WithForeground(rgb)); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(rules) == 0 {
+		if _, err := add("", false, base); err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
+
+// newColorRule builds the coloring rule for substring.
+//
+// A rule without substring colors the whole text whatever the match options;
+// otherwise substring is matched as the options describe.
+//
+// Parameters:
+//   - substring: The text, or regular expression, to color; empty for the
+//     whole text.
+//   - style: The style of the matched text; nil for palette and gradient
+//     rules, which style it themselves.
+//   - match: The --match, --ignore-case and --occurrence settings.
+//
+// Returns:
+//   - The rule.
+//   - An error if substring is not a valid regular expression in regex mode.
+func newColorRule(substring string, style coloring.Style, match coloring.MatchOptions) (coloring.Rule, error) {
+	rule := coloring.Rule{Substring: substring, Style: style}
+	if substring == "" || match == (coloring.MatchOptions{}) {
+		return rule, nil
+	}
+
+	matcher, err := coloring.NewMatcher(substring, match)
+	if err != nil {
+		return coloring.Rule{}, err
+	}
+	rule.Matcher = matcher
+	return rule, nil
+}
+
+// parseColorRule splits a --color value of the form COLOR or COLOR:TEXT.
+//
+// The value is split at the first colon whose left part is a valid color, so
+// TEXT may itself contain colons. A value without such a colon is a color
+// that applies to the substring argument, or to the whole text.
+//
+// Parameters:
+//   - value: The --color value.
+//
+// Returns:
+//   - rgb: The parsed color.
+//   - substring: The TEXT part; empty without one.
+//   - hasSubstring: Whether the value names its own TEXT.
+//   - err: An error if the color is invalid or TEXT is empty.
+func parseColorRule(value string) (rgb color.RGB, substring string, hasSubstring bool, err error) {
+	spec, substring, hasSubstring, err := splitRule(value, func(spec string) bool {
+		_, err := color.Parse(spec)
+		return err == nil
+	})
+	if err != nil {
+		return color.RGB{}, "", false, err
+	}
 
-		for _, cl := range colored {
-			fmt.Println(cl)
+	rgb, err = color.Parse(spec)
+	if err != nil {
+		// A misspelled COLOR:TEXT has no valid colon to split at; report the
+		// color part, so that a close name can be suggested.
+		if name, _, found := strings.Cut(spec, ":"); found {
+			if _, ok := color.Suggest(name); ok {
+				_, err = color.Parse(name)
+			}
 		}
 	}
+	return rgb, substring, hasSubstring, err
 }
 
-// hasColorFlag checks whether the first user argument uses the --color flag.
+// errColorNames is wrapped by the error of a --color-names file that cannot
+// be loaded, which is a color error rather than a usage error.
+var errColorNames = errors.New("cannot load color names")
+
+// loadColorNames reads a file of color names in the format of X11 rgb.txt
+// and makes them available to every color option.
 //
 // Parameters:
-//   - args: Command-line arguments slice including os.Args[0].
+//   - path: The file to read.
 //
 // Returns:
-//   - true if args[1] starts with "--color", false otherwise.
-func hasColorFlag(args []string) bool {
-	return len(args) > 1 && strings.HasPrefix(args[1], "--color")
+//   - An error wrapping errColorNames if the file cannot be read or is
+//     malformed.
+func loadColorNames(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errColorNames, err)
+	}
+	defer file.Close()
+
+	names, err := color.ReadNames(file)
+	if err != nil {
+		return fmt.Errorf("%w from %s: %w", errColorNames, path, err)
+	}
+	color.AddNames(names)
+	return nil
 }
 
-// extractColorArgs extracts color spec, substring, text, and banner from color-mode arguments.
+// splitRule splits a rule value of the form SPEC or SPEC:TEXT at the first
+// colon whose left part is a valid SPEC, so TEXT may itself contain colons.
 //
-// The function expects args[1] to be the --color=<value> flag. The remaining arguments
-// are interpreted as follows:
-//   - 3 args: prog --color=X text (no substring, default banner)
-//   - 4 args: prog --color=X text banner (if last arg is valid banner name)
-//   - 4 args: prog --color=X substring text (otherwise, default banner)
-//   - 5 args: prog --color=X substring text banner
+// Parameters:
+//   - value: The option value.
+//   - valid: Reports whether its argument is a valid SPEC.
+//
+// Returns:
+//   - spec: The SPEC part; all of value without a TEXT part.
+//   - substring: The TEXT part; empty without one.
+//   - hasSubstring: Whether the value names its own TEXT.
+//   - err: An error if TEXT is empty.
+func splitRule(value string, valid func(spec string) bool) (spec, substring string, hasSubstring bool, err error) {
+	for i, ch := range value {
+		if ch != ':' || !valid(value[:i]) {
+			continue
+		}
+		if value[i+1:] == "" {
+			return "", "", false, fmt.Errorf("missing text after %q in color rule %q", value[:i+1], value)
+		}
+		return value[:i], value[i+1:], true, nil
+	}
+	return value, "", false, nil
+}
+
+// hasBareColor reports whether any --color value applies to the substring
+// argument rather than naming its own text.
 //
 // Parameters:
-//   - args: Command-line arguments including program name.
+//   - colors: The --color values.
 //
 // Returns:
-//   - colorSpec: The color value from the --color= flag.
-//   - substring: The substring to color (empty if not provided).
-//   - text: The text to render (with escape sequences interpreted).
-//   - banner: The banner name to use.
-//   - err: An error if extraction fails.
-func extractColorArgs(args []string) (colorSpec, substring, text, banner string, err error) {
-	_, colorSpec, _ = strings.Cut(args[1], "=")
-
-	remaining := args[2:]
-
-	switch len(remaining) {
-	case 0:
-		return "", "", "", "", errors.New("missing text argument")
-	case 1:
-		text = remaining[0]
-		banner = defaultBanner
-	case 2:
-		if isValidBanner(remaining[1]) {
-			text = remaining[0]
-			banner = remaining[1]
-		} else {
-			substring = remaining[0]
-			text = remaining[1]
-			banner = defaultBanner
-		}
-	case 3:
-		substring = remaining[0]
-		text = remaining[1]
-		banner = remaining[2]
-	default:
-		return "", "", "", "", errors.New("too many arguments")
-	}
-
-	text = strings.ReplaceAll(text, "\\n", "\n")
-
-	return colorSpec, substring, text, banner, nil
+//   - true if a value has no :TEXT part, false otherwise.
+func hasBareColor(colors []string) bool {
+	for _, value := range colors {
+		if _, _, hasSubstring, _ := parseColorRule(value); !hasSubstring {
+			return true
+		}
+	}
+	return false
 }
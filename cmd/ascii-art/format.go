@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"os"
+	"strings"
+
+	"github.com/vxanthio/ascii-art-color/internal/color"
+	"github.com/vxanthio/ascii-art-color/internal/export"
+	"github.com/vxanthio/ascii-art-color/internal/terminal"
+)
+
+// Output formats of --format.
+const (
+	textFormat = "text"
+	htmlFormat = "html"
+	svgFormat  = "svg"
+	pngFormat  = "png"
+	gifFormat  = "gif"
+)
+
+// formats lists the output formats, in the order --help and errors name them.
+var formats = []string{textFormat, htmlFormat, svgFormat, pngFormat, gifFormat}
+
+// formatOptions maps the options that apply only to some output formats to
+// those formats.
+var formatOptions = map[string][]string{
+	htmlDocOption:    {htmlFormat},
+	htmlClassOption:  {htmlFormat},
+	svgCellsOption:   {svgFormat},
+	fontSizeOption:   {svgFormat},
+	canvasOption:     {svgFormat, pngFormat, gifFormat},
+	scaleOption:      {pngFormat, gifFormat},
+	paddingOption:    {pngFormat, gifFormat},
+	effectOption:     {gifFormat},
+	delayOption:      {gifFormat},
+	loopOption:       {gifFormat},
+	canvasSizeOption: {gifFormat},
+}
+
+const (
+	// maxFontSize is the largest --font-size, in pixels.
+	maxFontSize = 1000
+	// maxScale is the largest --scale.
+	maxScale = 32
+	// maxPadding is the largest --padding, in pixels.
+	maxPadding = 1000
+	// defaultPadding is the --padding of PNG and GIF images, in pixels.
+	defaultPadding = 8
+)
+
+// parseFormat validates an output format name.
+//
+// Parameters:
+//   - name: The format name, case-insensitive.
+//
+// Returns:
+//   - The format, in lowercase.
+//   - An error if no format has that name.
+func parseFormat(name string) (string, error) {
+	for _, format := range formats {
+		if strings.EqualFold(name, format) {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("invalid format: %q\nValid options: %s", name, strings.Join(formats, ", "))
+}
+
+// writeDocument renders the text and writes it to out as a document in the
+// --format output format. The rows are rendered exactly as for the terminal,
+// with 24-bit colors, and then converted.
+//
+// Parameters:
+//   - out: The file to write the document to.
+//   - opts: The parsed command-line options.
+func writeDocument(out *os.File, opts cliOptions) {
+	if (opts.format == pngFormat || opts.format == gifFormat) && terminal.IsTerminal(out) {
+		fmt.Fprintf(os.Stderr, "Error: refusing to write a %s image to a terminal; use --%s=FILE or redirect the output\n", strings.ToUpper(opts.format), outputOption)
+		exit(exitCodeOutputError)
+	}
+	if opts.format == gifFormat && opts.text == stdinArg {
+		// The typewriter effect needs the lines of the text as well as their
+		// rendering, so the input is read once, up front.
+		opts.text = readText(opts)
+	}
+
+	var buf bytes.Buffer
+	render(&buf, out, opts)
+	rows := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+
+	switch opts.format {
+	case htmlFormat:
+		fmt.Fprint(out, export.HTML(rows, export.HTMLOptions{
+			Document: opts.htmlDocument,
+			Classes:  opts.htmlClasses,
+			Title:    documentTitle(opts.text),
+		}))
+	case svgFormat:
+		fmt.Fprint(out, export.SVG(rows, export.SVGOptions{
+			FontSize:   opts.fontSize,
+			Background: mustCanvasColor(opts.canvas),
+			Cells:      opts.svgCells,
+		}))
+	case pngFormat:
+		err := export.PNG(out, rows, export.ImageOptions{
+			Scale:      opts.scale,
+			Padding:    opts.padding,
+			Background: mustCanvasColor(opts.canvas),
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			exit(exitCodeOutputError)
+		}
+	case gifFormat:
+		var reveal []image.Rectangle
+		if opts.effect == export.EffectTypewriter {
+			reveal = glyphCells(out, opts)
+		}
+		err := export.GIF(out, rows, export.GIFOptions{
+			ImageOptions: export.ImageOptions{
+				Scale:      opts.scale,
+				Padding:    opts.padding,
+				Background: mustCanvasColor(opts.canvas),
+			},
+			Effect: opts.effect,
+			Delay:  opts.delay,
+			Loops:  opts.loops,
+			Width:  opts.canvasWidth,
+			Height: opts.canvasHeight,
+			Reveal: reveal,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			exit(exitCodeOutputError)
+		}
+	}
+}
+
+// requiredFormats returns the formats an option of formatOptions applies to,
+// as a phrase such as "--format=png or --format=gif".
+//
+// Parameters:
+//   - option: The option name.
+//
+// Returns:
+//   - The phrase.
+func requiredFormats(option string) string {
+	list := make([]string, len(formatOptions[option]))
+	for i, format := range formatOptions[option] {
+		list[i] = fmt.Sprintf("--%s=%s", formatOption, format)
+	}
+	if len(list) == 1 {
+		return list[0]
+	}
+	return strings.Join(list[:len(list)-1], ", ") + " or " + list[len(list)-1]
+}
+
+// mustCanvasColor parses the --canvas color, exiting if it is invalid.
+//
+// Parameters:
+//   - spec: The color specification; empty for none.
+//
+// Returns:
+//   - The color; not valid for an empty spec.
+func mustCanvasColor(spec string) export.Color {
+	canvas, err := canvasColor(spec)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exit(exitCodeColorError)
+	}
+	return canvas
+}
+
+// canvasColor parses the --canvas color of an image background.
+//
+// Parameters:
+//   - spec: The color specification; empty for none.
+//
+// Returns:
+//   - The color; not valid for an empty spec.
+//   - An error if spec is not a valid color.
+func canvasColor(spec string) (export.Color, error) {
+	if spec == "" {
+		return export.Color{}, nil
+	}
+	rgb, err := color.Parse(spec)
+	if err != nil {
+		return export.Color{}, fmt.Errorf("invalid canvas: %w", err)
+	}
+	return export.Color{R: rgb.R, G: rgb.G, B: rgb.B, Valid: true}, nil
+}
+
+// documentTitle returns the title of a document rendered from text.
+//
+// Parameters:
+//   - text: The text argument, or stdinArg.
+//
+// Returns:
+//   - The text on one line, or "ASCII art" for text read from a file or
+//     standard input.
+func documentTitle(text string) string {
+	if text == stdinArg {
+		return "ASCII art"
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// minDelay and maxDelay bound --delay, in milliseconds.
+	minDelay, maxDelay = 10, 60000
+	// maxLoops is the largest --loop, the largest repeat count of a GIF
+	// image.
+	maxLoops = 65535
+	// maxCanvasSize is the largest width and height of --canvas-size, in
+	// pixels.
+	maxCanvasSize = 10000
+)
+
+// parseCanvasSize parses a --canvas-size value of the form WIDTHxHEIGHT.
+//
+// Parameters:
+//   - value: The value, such as "640x200".
+//
+// Returns:
+//   - The width and height in pixels.
+//   - An error if the value is malformed or a side is out of range.
+func parseCanvasSize(value string) (width, height int, err error) {
+	w, h, found := strings.Cut(strings.ToLower(value), "x")
+	width, errW := strconv.Atoi(w)
+	height, errH := strconv.Atoi(h)
+	if !found || errW != nil || errH != nil || width < 1 || height < 1 || width > maxCanvasSize || height > maxCanvasSize {
+		return 0, 0, fmt.Errorf("invalid value for --%s=<width>x<height>: %q must be two integers from 1 to %d, such as 640x200", canvasSizeOption, value, maxCanvasSize)
+	}
+	return width, height, nil
+}
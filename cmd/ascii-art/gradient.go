@@ -0,0 +1,76 @@
+package main
+
+import (
+	"github.com/vxanthio/ascii-art-color/internal/color"
+	"github.com/vxanthio/ascii-art-color/internal/coloring"
+)
+
+// parseGradientRule splits a --gradient value of the form COLORS or
+// COLORS:TEXT, where COLORS is a comma-separated list of at least two colors.
+//
+// As for --color, the value is split at the first colon whose left part is a
+// valid color list, so TEXT may itself contain colons.
+//
+// Parameters:
+//   - value: The --gradient value.
+//
+// Returns:
+//   - stops: The gradient colors, in order.
+//   - substring: The TEXT part; empty without one.
+//   - hasSubstring: Whether the value names its own TEXT.
+//   - err: An error if a color is invalid, there are fewer than two, or TEXT
+//     is empty.
+func parseGradientRule(value string) (stops []color.RGB, substring string, hasSubstring bool, err error) {
+	spec, substring, hasSubstring, err := splitRule(value, func(spec string) bool {
+		_, err := color.ParseGradient(spec)
+		return err == nil
+	})
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	stops, err = color.ParseGradient(spec)
+	return stops, substring, hasSubstring, err
+}
+
+// hasBareGradient reports whether any --gradient value applies to the
+// substring argument rather than naming its own text.
+//
+// Parameters:
+//   - gradients: The --gradient values.
+//
+// Returns:
+//   - true if a value has no :TEXT part, false otherwise.
+func hasBareGradient(gradients []string) bool {
+	for _, value := range gradients {
+		if _, _, hasSubstring, _ := parseGradientRule(value); !hasSubstring {
+			return true
+		}
+	}
+	return false
+}
+
+// gradientShader returns a shader that spreads g across the columns of the
+// rendered art, from its first column to its last, or across its rows when
+// vertical is set.
+//
+// Parameters:
+//   - g: The gradient to spread.
+//   - vertical: Whether the gradient runs top to bottom.
+//   - base: The background and attributes of every cell.
+//
+// Returns:
+//   - The shader.
+func gradientShader(g color.Gradient, vertical bool, base color.Style) coloring.Shader {
+	return func(row, column, rows, columns int) coloring.Style {
+		position, extent := column, columns
+		if vertical {
+			position, extent = row, rows
+		}
+		t := 0.0
+		if extent > 1 {
+			t = float64(position) / float64(extent-1)
+		}
+		return base.WithForeground(g.At(t))
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/vxanthio/ascii-art-color/internal/parser"
+	"github.com/vxanthio/ascii-art-color/internal/renderer"
+)
+
+// stdinArg is the text argument that makes the program read its text from
+// standard input.
+const stdinArg = "-"
+
+// stdinRedirected reports whether standard input is a pipe or a file, as
+// opposed to a terminal or another character device such as /dev/null.
+//
+// Returns:
+//   - true if standard input is redirected, false otherwise.
+func stdinRedirected() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
+// eachLine calls fn for each line of text, in order.
+//
+// When text is stdinArg the lines are read one at a time from the --input
+// file, or from standard input, so that large inputs are never held in memory
+// at once. Otherwise text is split at newline characters, and a trailing
+// newline does not produce an extra empty line. The program exits if the
+// input cannot be opened or read.
+//
+// Parameters:
+//   - text: The text argument, or stdinArg.
+//   - opts: The parsed command-line options.
+//   - fn: The function to call with each line, without its line terminator.
+func eachLine(text string, opts cliOptions, fn func(line string)) {
+	if text != stdinArg {
+		lines := strings.Split(text, "\n")
+		if lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		for _, line := range lines {
+			fn(line)
+		}
+		return
+	}
+
+	input := os.Stdin
+	if opts.input != "" {
+		file, err := os.Open(opts.input)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitCodeInputError)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	if err := readLines(input, fn); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exit(exitCodeInputError)
+	}
+}
+
+// readLines calls fn for each line read from r.
+//
+// Lines may end with "\n" or "\r\n"; the terminator is not passed to fn. A
+// final line without a terminator is passed as well.
+//
+// Parameters:
+//   - r: The reader to read lines from.
+//   - fn: The function to call with each line.
+//
+// Returns:
+//   - An error if reading fails.
+func readLines(r io.Reader, fn func(line string)) error {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			fn(strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"))
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading input: %w", err)
+		}
+	}
+}
+
+// renderInput renders the streamed text line by line and writes each block as
+// soon as it is rendered, exiting on failure.
+//
+// Blocks aligned to the widest block, without an output width, can only be
+// printed once every line is known; in that case the lines are collected and
+// rendered together.
+//
+// Parameters:
+//   - w: The writer to write the output to.
+//   - opts: The parsed command-line options.
+//   - banner: The banner to render with.
+//   - renderOpts: The renderer options.
+func renderInput(w io.Writer, opts cliOptions, banner parser.Banner, renderOpts renderer.Options) {
+	if renderOpts.Align != renderer.AlignLeft && renderOpts.Width <= 0 {
+		var lines []string
+		eachLine(stdinArg, opts, func(line string) {
+			lines = append(lines, line)
+		})
+		printArt(w, strings.Join(lines, "\n")+"\n", banner, renderOpts)
+		return
+	}
+
+	eachLine(stdinArg, opts, func(line string) {
+		if line == "" {
+			fmt.Fprintln(w)
+			return
+		}
+		printArt(w, line, banner, renderOpts)
+	})
+}
+
+// printArt renders text and writes the result to w, exiting on failure.
+//
+// Parameters:
+//   - w: The writer to write the output to.
+//   - text: The text to render.
+//   - banner: The banner to render with.
+//   - renderOpts: The renderer options.
+func printArt(w io.Writer, text string, banner parser.Banner, renderOpts renderer.Options) {
+	result, err := renderer.ASCIIWithOptions(text, banner, renderOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error rendering text: %v\n", err)
+		exit(exitCodeRenderError)
+	}
+	fmt.Fprint(w, result)
+}
+
+// readText reads the whole text from the --input file or standard input.
+//
+// Parameters:
+//   - opts: The parsed command-line options.
+//
+// Returns:
+//   - The lines read, joined with newlines.
+func readText(opts cliOptions) string {
+	var lines []string
+	eachLine(stdinArg, opts, func(line string) {
+		lines = append(lines, line)
+	})
+	return strings.Join(lines, "\n")
+}
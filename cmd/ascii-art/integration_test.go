@@ -1,14 +1,28 @@
 package main
 
 import (
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 )
 
+// TestMain forces 24-bit colors, so that the expected escape sequences do not
+// depend on the terminal the tests run in; output to a pipe is otherwise
+// uncolored.
+func TestMain(m *testing.M) {
+	os.Setenv("FORCE_COLOR", "3")
+	os.Unsetenv("NO_COLOR")
+	os.Exit(m.Run())
+}
+
 func TestMainProgram_Integration(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -82,6 +96,106 @@ func TestMainProgram_Integration(t *testing.T) {
 			expectError: true,
 			checkOutput: nil,
 		},
+		{
+			name:        "FIGlet font file",
+			args:        []string{"Hi", "--font=testdata/boxed.flf"},
+			expectError: false,
+			checkOutput: func(output string) bool {
+				return strings.Count(output, "\n") == 3 && strings.Contains(output, "|H||i|")
+			},
+		},
+		{
+			name:        "Code-tagged FIGlet character",
+			args:        []string{"Hé", "--font=testdata/boxed.flf"},
+			expectError: false,
+			checkOutput: func(output string) bool {
+				return strings.Count(output, "\n") == 3 && strings.Contains(output, "|H||e|") && strings.HasPrefix(output, "+-++'+\n")
+			},
+		},
+		{
+			name:        "Fallback for missing character",
+			args:        []string{"Café", "--fallback=?"},
+			expectError: false,
+			checkOutput: func(output string) bool {
+				return strings.Count(output, "\n") == 8 && strings.HasPrefix(output, "  _____            __   ___   \n")
+			},
+		},
+		{
+			name:        "Missing character without fallback",
+			args:        []string{"Café"},
+			expectError: true,
+			checkOutput: nil,
+		},
+		{
+			name:        "Wrap to width",
+			args:        []string{"Hello World", "--width=40"},
+			expectError: false,
+			checkOutput: func(output string) bool {
+				for _, row := range strings.Split(strings.TrimSuffix(output, "\n"), "\n") {
+					if len(row) > 40 {
+						return false
+					}
+				}
+				return strings.Count(output, "\n") == 24 && strings.HasPrefix(output, " _    _          _   _          \n")
+			},
+		},
+		{
+			name:        "Width zero disables wrapping",
+			args:        []string{"Hello World", "--width=0"},
+			expectError: false,
+			checkOutput: func(output string) bool {
+				return strings.Count(output, "\n") == 8
+			},
+		},
+		{
+			name:        "Invalid width",
+			args:        []string{"Hello", "--width=narrow"},
+			expectError: true,
+			checkOutput: nil,
+		},
+		{
+			name:        "Center alignment",
+			args:        []string{"Hi", "--align=center", "--width=20"},
+			expectError: false,
+			checkOutput: func(output string) bool {
+				return strings.Count(output, "\n") == 8 && strings.HasPrefix(output, "    _    _   _      \n")
+			},
+		},
+		{
+			name:        "Right alignment to widest line",
+			args:        []string{"Hi\\ni", "--align=right"},
+			expectError: false,
+			checkOutput: func(output string) bool {
+				rows := strings.Split(output, "\n")
+				return len(rows) == 17 && rows[9] == "         (_) " && len(rows[0]) == len(rows[8])
+			},
+		},
+		{
+			name:        "Invalid alignment",
+			args:        []string{"Hi", "--align=middle"},
+			expectError: true,
+			checkOutput: nil,
+		},
+		{
+			name:        "Smushing layout",
+			args:        []string{"HH", "--layout=smushing"},
+			expectError: false,
+			checkOutput: func(output string) bool {
+				return strings.Count(output, "\n") == 8 && strings.HasPrefix(output, " _    _ _    _  \n")
+			},
+		},
+		{
+			name:        "Invalid layout",
+			args:        []string{"Hi", "--layout=squash"},
+			expectError: true,
+			checkOutput: nil,
+		},
+		{
+			name:        "Missing FIGlet font file",
+			args:        []string{"Hi", "--font=testdata/nope.flf"},
+			expectError: true,
+			checkOutput: nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -218,6 +332,51 @@ func TestRunColorMode(t *testing.T) {
 					strings.Count(output, "\n") == 8
 			},
 		},
+		{
+			name: "substring with FIGlet font",
+			args: []string{"--font=testdata/boxed.flf", "--color=red", "i", "Hi"},
+			checkOutput: func(output string) bool {
+				return strings.Contains(output, "|H|\033[38;2;255;0;0m|i|\033[0m") &&
+					strings.Count(output, "\n") == 3
+			},
+		},
+		{
+			name: "substring after multi-byte character",
+			args: []string{"--font=testdata/boxed.flf", "--color=red", "i", "Héi"},
+			checkOutput: func(output string) bool {
+				return strings.Contains(output, "|H||e|\033[38;2;255;0;0m|i|\033[0m")
+			},
+		},
+		{
+			name: "multi-byte substring",
+			args: []string{"--font=testdata/boxed.flf", "--color=red", "é", "Héi"},
+			checkOutput: func(output string) bool {
+				return strings.Contains(output, "|H|\033[38;2;255;0;0m|e|\033[0m|i|")
+			},
+		},
+		{
+			name: "substring with wrapped output",
+			args: []string{"--color=red", "--width=30", "o", "Hi you"},
+			checkOutput: func(output string) bool {
+				return strings.Count(output, "\n") == 16 &&
+					strings.Contains(output, "| |_| | \033[38;2;255;0;0m| (_) | \033[0m| |_| | \n")
+			},
+		},
+		{
+			name: "substring with right alignment",
+			args: []string{"--color=red", "--align=right", "--width=30", "i", "Hi"},
+			checkOutput: func(output string) bool {
+				return strings.Contains(output, "\n                 |_|  |_| \033[38;2;255;0;0m|_| \033[0m\n")
+			},
+		},
+		{
+			name: "substring with fitting layout",
+			args: []string{"--layout=fitting", "--color=red", "i", "Hi"},
+			checkOutput: func(output string) bool {
+				return strings.Contains(output, "| |  | |\033[38;2;255;0;0m(_) \033[0m") &&
+					strings.Count(output, "\n") == 8
+			},
+		},
 		{
 			name:        "invalid color name",
 			args:        []string{"--color=notacolor", "hello"},
@@ -292,6 +451,42 @@ func TestMainProgram_RealBannerFiles(t *testing.T) {
 	}
 }
 
+func TestMainProgram_TallFIGletFont(t *testing.T) {
+	const height = 12
+
+	var font strings.Builder
+	font.WriteString("flf2a$ 12 10 3 -1 0\n")
+	for char := ' '; char <= '~'; char++ {
+		for row := 0; row < height; row++ {
+			end := "@"
+			if row == height-1 {
+				end = "@@"
+			}
+			if char == ' ' {
+				font.WriteString("$$" + end + "\n")
+				continue
+			}
+			font.WriteString("#" + string(char) + end + "\n")
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "tall.flf")
+	if err := os.WriteFile(path, []byte(font.String()), 0o644); err != nil {
+		t.Fatalf("failed to write font: %v", err)
+	}
+
+	output, err := exec.Command("go", "run", ".", "--font="+path, "ab").CombinedOutput()
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nOutput: %s", err, output)
+	}
+	if lines := strings.Count(string(output), "\n"); lines != height {
+		t.Errorf("expected %d lines, got %d\nOutput:\n%s", height, lines, output)
+	}
+	if !strings.HasPrefix(string(output), "#a#b\n") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+}
+
 func TestMainProgram_ErrorHandling(t *testing.T) {
 	errorTests := []struct {
 		name     string
@@ -439,3 +634,894 @@ func TestBuiltBinary_FromRepoRoot(t *testing.T) {
 		})
 	}
 }
+
+func TestMainProgram_Input(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("Hi\r\n\nyo\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	standard := func(text string) string {
+		t.Helper()
+		output, err := exec.Command("go", "run", ".", text).CombinedOutput()
+		if err != nil {
+			t.Fatalf("rendering %q: %v\n%s", text, err, output)
+		}
+		return string(output)
+	}
+	want := standard("Hi\\n\\nyo")
+
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+		want  string
+	}{
+		{"piped without text argument", nil, "Hi\n\nyo\n", want},
+		{"dash reads stdin", []string{"-"}, "Hi\n\nyo", want},
+		{"dash with banner", []string{"-", "standard"}, "Hi\n\nyo\n", want},
+		{"input file", []string{"--input=" + path}, "", want},
+		{"input file with banner", []string{"standard", "--input=" + path}, "", want},
+		{"each line its own block", []string{"-"}, "a\nb\n", standard("a\\nb")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command("go", append([]string{"run", "."}, tt.args...)...)
+			cmd.Stdin = strings.NewReader(tt.stdin)
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("unexpected error: %v\n%s", err, output)
+			}
+			if string(output) != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", output, tt.want)
+			}
+		})
+	}
+}
+
+func TestMainProgram_InputColor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("ab\nba\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := exec.Command("go", "run", ".", "--color=red", "b", "ab\\nba").CombinedOutput()
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, want)
+	}
+
+	for _, args := range [][]string{
+		{"--color=red", "b", "--input=" + path},
+		{"--color=red", "b", "--input=" + path, "standard"},
+	} {
+		output, err := exec.Command("go", append([]string{"run", "."}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v\n%s", args, err, output)
+		}
+		if string(output) != string(want) {
+			t.Errorf("%q: got:\n%s\nwant:\n%s", args, output, want)
+		}
+	}
+}
+
+func TestMainProgram_InputErrors(t *testing.T) {
+	cmd := exec.Command("go", "run", ".", "--input=testdata/missing.txt")
+	output, err := cmd.CombinedOutput()
+	if err == nil || !strings.Contains(string(output), "exit status 5") {
+		t.Errorf("expected input error exit status 5, got %v\n%s", err, output)
+	}
+	if !strings.Contains(string(output), "missing.txt") {
+		t.Errorf("expected error naming the file, got: %s", output)
+	}
+
+	cmd = exec.Command("go", "run", ".", "-")
+	cmd.Stdin = strings.NewReader("tab\there\n")
+	output, err = cmd.CombinedOutput()
+	if err == nil || !strings.Contains(string(output), "Error rendering text") {
+		t.Errorf("expected render error, got %v\n%s", err, output)
+	}
+}
+
+func TestMainProgram_Options(t *testing.T) {
+	run := func(args ...string) string {
+		t.Helper()
+		output, err := exec.Command("go", append([]string{"run", "."}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v\n%s", args, err, output)
+		}
+		return string(output)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"banner option", []string{"--banner=shadow", "Hi"}, []string{"Hi", "shadow"}},
+		{"short banner after text", []string{"Hi", "-b", "shadow"}, []string{"Hi", "shadow"}},
+		{"substring option", []string{"-c", "red", "-s", "i", "Hi"}, []string{"--color=red", "i", "Hi"}},
+		{"substring option with banner option", []string{"Hi", "--substring=i", "--color=red", "--banner=thinkertoy"}, []string{"--color=red", "i", "Hi", "thinkertoy"}},
+		{"short width and align", []string{"-w", "30", "-a", "right", "Hi"}, []string{"Hi", "--width=30", "--align=right"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := run(tt.args...), run(tt.want...); got != want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+
+	dashed := run("--", "-1")
+	if lines := strings.Split(dashed, "\n"); len(lines) != 9 || !strings.Contains(dashed, "______") {
+		t.Errorf("expected \"-1\" rendered after --, got:\n%s", dashed)
+	}
+}
+
+func TestMainProgram_Help(t *testing.T) {
+	output, err := exec.Command("go", "run", ".", "--help").CombinedOutput()
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, output)
+	}
+	for _, want := range []string{"Usage:", "-b, --banner=NAME", "-o, --output=PATH", "-h, --help"} {
+		if !strings.Contains(string(output), want) {
+			t.Errorf("help output does not contain %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestMainProgram_Output(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "banner.txt")
+	output, err := exec.Command("go", "run", ".", "Hi", "--output="+path).CombinedOutput()
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, output)
+	}
+	if len(output) != 0 {
+		t.Errorf("expected nothing on stdout, got:\n%s", output)
+	}
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := exec.Command("go", "run", ".", "Hi").CombinedOutput()
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, want)
+	}
+	if string(written) != string(want) {
+		t.Errorf("file contents:\n%s\nwant:\n%s", written, want)
+	}
+
+	output, err = exec.Command("go", "run", ".", "Hi", "-o", filepath.Join(path, "nested.txt")).CombinedOutput()
+	if err == nil || !strings.Contains(string(output), "exit status 6") {
+		t.Errorf("expected output error exit status 6, got %v\n%s", err, output)
+	}
+}
+
+func TestMainProgram_OutputFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "banner.txt")
+	if err := os.WriteFile(path, []byte("keep me\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	contents := func() string {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	output, err := exec.Command("go", "run", ".", "Hi", "--output="+path).CombinedOutput()
+	if err == nil || !strings.Contains(string(output), "already exists; use --force to overwrite it") {
+		t.Errorf("expected a refusal to overwrite, got %v\n%s", err, output)
+	}
+	if got := contents(); got != "keep me\n" {
+		t.Errorf("refused overwrite changed the file to %q", got)
+	}
+
+	output, err = exec.Command("go", "run", ".", "Hi\u2603", "--output="+path, "--force").CombinedOutput()
+	if err == nil {
+		t.Errorf("expected a rendering error, got none\n%s", output)
+	}
+	if got := contents(); got != "keep me\n" {
+		t.Errorf("failed render changed the file to %q", got)
+	}
+
+	// FORCE_COLOR=3 is set for the tests, but files get no escape sequences.
+	output, err = exec.Command("go", "run", ".", "--color=red", "Hi", "--output="+path, "--force").CombinedOutput()
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, output)
+	}
+	if got := contents(); strings.Contains(got, "\033[") || !strings.HasPrefix(got, " _    _   _  \n") {
+		t.Errorf("file contents = %q, want uncolored art", got)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("overwritten file mode = %v, %v; want the original 0600", info.Mode().Perm(), err)
+	}
+
+	svg := filepath.Join(dir, "banner.svg")
+	if output, err := exec.Command("go", "run", ".", "Hi", "-o", svg).CombinedOutput(); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, output)
+	}
+	if data, err := os.ReadFile(svg); err != nil || !strings.HasPrefix(string(data), "<?xml") {
+		t.Errorf("banner.svg = %.40q, %v; want an SVG document", data, err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if name := entry.Name(); name != "banner.txt" && name != "banner.svg" {
+			t.Errorf("unexpected file left in the output directory: %s", name)
+		}
+	}
+}
+
+func TestMainProgram_ColorRules(t *testing.T) {
+	red, green, reset := "\033[38;2;255;0;0m", "\033[38;2;0;255;0m", "\033[0m"
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "independent rules",
+			args: []string{"--color=red:E", "--color=green:O", "EO"},
+			want: red + "|  ____| " + reset + green + " / __ \\  " + reset,
+		},
+		{
+			name: "later rule wins over base color",
+			args: []string{"--color=red", "--color=green:O", "EO"},
+			want: red + "|  ____| " + reset + green + " / __ \\  " + reset,
+		},
+		{
+			name: "earlier rule hidden by later base color",
+			args: []string{"--color=green:O", "--color=red", "EO"},
+			want: red + "|  ____|  / __ \\  " + reset,
+		},
+		{
+			name: "uncolored gap between rules",
+			args: []string{"--color=red:E", "--color=green:O", "EXO"},
+			want: red + "|  ____| " + reset + "\\ \\ / / " + green + " / __ \\  " + reset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := exec.Command("go", append([]string{"run", "."}, tt.args...)...).CombinedOutput()
+			if err != nil {
+				t.Fatalf("unexpected error: %v\n%s", err, output)
+			}
+			if got := strings.SplitN(string(output), "\n", 3)[1]; got != tt.want {
+				t.Errorf("second row = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	output, err := exec.Command("go", "run", ".", "--color=red:", "hello").CombinedOutput()
+	if err == nil || !strings.Contains(string(output), "exit status 4") {
+		t.Errorf("expected color error for empty rule text, got %v\n%s", err, output)
+	}
+}
+
+func TestMainProgram_Match(t *testing.T) {
+	red, reset := "\033[38;2;255;0;0m", "\033[0m"
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "regex",
+			args: []string{"--match=regex", "--color=red:[EO]", "EXO"},
+			want: red + "|  ____| " + reset + "\\ \\ / / " + red + " / __ \\  " + reset,
+		},
+		{
+			name: "ignore case",
+			args: []string{"--ignore-case", "--color=red:e", "EXO"},
+			want: red + "|  ____| " + reset + "\\ \\ / /  / __ \\  ",
+		},
+		{
+			name: "second occurrence",
+			args: []string{"--occurrence=2", "--color=red", "E", "EXE"},
+			want: "|  ____| \\ \\ / / " + red + "|  ____| " + reset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := exec.Command("go", append([]string{"run", "."}, tt.args...)...).CombinedOutput()
+			if err != nil {
+				t.Fatalf("unexpected error: %v\n%s", err, output)
+			}
+			if got := strings.SplitN(string(output), "\n", 3)[1]; got != tt.want {
+				t.Errorf("second row = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	output, err := exec.Command("go", "run", ".", "--match=regex", "--color=red:(", "hello").CombinedOutput()
+	if err == nil || !strings.Contains(string(output), "exit status 4") {
+		t.Errorf("expected color error for invalid regular expression, got %v\n%s", err, output)
+	}
+}
+
+func TestMainProgram_Gradient(t *testing.T) {
+	red, blue, reset := "\033[38;2;255;0;0m", "\033[38;2;0;0;255m", "\033[0m"
+
+	output, err := exec.Command("go", "run", ".", "--gradient=red,blue", "--gradient-direction=vertical", "EO").CombinedOutput()
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, output)
+	}
+	rows := strings.Split(string(output), "\n")
+	if want := red + " ______    ____   " + reset; rows[0] != want {
+		t.Errorf("first row = %q, want %q", rows[0], want)
+	}
+	if want := blue + "                  " + reset; rows[7] != want {
+		t.Errorf("last row = %q, want %q", rows[7], want)
+	}
+
+	output, err = exec.Command("go", "run", ".", "--gradient=red,blue:O", "EO").CombinedOutput()
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, output)
+	}
+	row := strings.SplitN(string(output), "\n", 3)[1]
+	if !strings.HasPrefix(row, "|  ____| \033[38;2;") || !strings.HasSuffix(row, blue+" "+reset) {
+		t.Errorf("substring gradient row = %q, want the uncolored E and the O ending in blue", row)
+	}
+
+	output, err = exec.Command("go", "run", ".", "--gradient=red", "hello").CombinedOutput()
+	if err == nil || !strings.Contains(string(output), "exit status 4") {
+		t.Errorf("expected color error for a single-color gradient, got %v\n%s", err, output)
+	}
+}
+
+func TestMainProgram_Palette(t *testing.T) {
+	red, orange, yellow, reset := "\033[38;2;255;0;0m", "\033[38;2;255;127;0m", "\033[38;2;255;255;0m", "\033[0m"
+
+	tests := []struct {
+		name string
+		args []string
+		row  int
+		want string
+	}{
+		{
+			name: "rainbow per glyph",
+			args: []string{"--rainbow", "EO"},
+			row:  1,
+			want: red + "|  ____| " + reset + orange + " / __ \\  " + reset,
+		},
+		{
+			name: "rainbow per row",
+			args: []string{"--rainbow", "--cycle=row", "EO"},
+			row:  2,
+			want: yellow + "| |__    | |  | | " + reset,
+		},
+		{
+			name: "palette on its text",
+			args: []string{"--palette=rainbow:O", "EO"},
+			row:  1,
+			want: "|  ____| " + red + " / __ \\  " + reset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := exec.Command("go", append([]string{"run", "."}, tt.args...)...).CombinedOutput()
+			if err != nil {
+				t.Fatalf("unexpected error: %v\n%s", err, output)
+			}
+			if got := strings.Split(string(output), "\n")[tt.row]; got != tt.want {
+				t.Errorf("row %d = %q, want %q", tt.row, got, tt.want)
+			}
+		})
+	}
+
+	output, err := exec.Command("go", "run", ".", "--palette=vaporwave", "hello").CombinedOutput()
+	if err == nil || !strings.Contains(string(output), "exit status 4") {
+		t.Errorf("expected color error for an unknown palette, got %v\n%s", err, output)
+	}
+}
+
+func TestMainProgram_Style(t *testing.T) {
+	reset := "\033[0m"
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "background and attributes on a color rule",
+			args: []string{"--color=red:O", "--bg=blue", "--attr=bold,underline", "EO"},
+			want: "|  ____| " + "\033[1;4;38;2;255;0;0;48;2;0;0;255m" + " / __ \\  " + reset,
+		},
+		{
+			name: "background alone",
+			args: []string{"--bg=blue", "E", "EO"},
+			want: "\033[48;2;0;0;255m" + "|  ____| " + reset + " / __ \\  ",
+		},
+		{
+			name: "reverse video alone",
+			args: []string{"--attr=reverse", "EO"},
+			want: "\033[7m" + "|  ____|  / __ \\  " + reset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := exec.Command("go", append([]string{"run", "."}, tt.args...)...).CombinedOutput()
+			if err != nil {
+				t.Fatalf("unexpected error: %v\n%s", err, output)
+			}
+			if got := strings.SplitN(string(output), "\n", 3)[1]; got != tt.want {
+				t.Errorf("second row = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	output, err := exec.Command("go", "run", ".", "--bg=notacolor", "hello").CombinedOutput()
+	if err == nil || !strings.Contains(string(output), "exit status 4") {
+		t.Errorf("expected color error for an invalid background, got %v\n%s", err, output)
+	}
+}
+
+func TestMainProgram_ColorMode(t *testing.T) {
+	reset := "\033[0m"
+
+	tests := []struct {
+		name string
+		args []string
+		env  []string
+		want string
+	}{
+		{
+			name: "256 colors",
+			args: []string{"--color-mode=256", "--color=red", "EO"},
+			want: "\033[38;5;196m" + "|  ____|  / __ \\  " + reset,
+		},
+		{
+			name: "16 colors with a background",
+			args: []string{"--color-mode=16", "--color=red:O", "--bg=blue", "EO"},
+			want: "|  ____| " + "\033[91;44m" + " / __ \\  " + reset,
+		},
+		{
+			name: "no colors",
+			args: []string{"--color-mode=none", "--color=red", "--attr=bold", "EO"},
+			want: "|  ____|  / __ \\  ",
+		},
+		{
+			name: "NO_COLOR disables colors",
+			args: []string{"--color=red", "EO"},
+			env:  []string{"NO_COLOR=1"},
+			want: "|  ____|  / __ \\  ",
+		},
+		{
+			name: "explicit mode overrides NO_COLOR",
+			args: []string{"--color-mode=truecolor", "--color=red", "EO"},
+			env:  []string{"NO_COLOR=1"},
+			want: "\033[38;2;255;0;0m" + "|  ____|  / __ \\  " + reset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command("go", append([]string{"run", "."}, tt.args...)...)
+			if tt.env != nil {
+				cmd.Env = append(environWithout("FORCE_COLOR"), tt.env...)
+			}
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("unexpected error: %v\n%s", err, output)
+			}
+			if got := strings.SplitN(string(output), "\n", 3)[1]; got != tt.want {
+				t.Errorf("second row = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// environWithout returns the environment of the test process without the
+// named variable.
+func environWithout(name string) []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, name+"=") {
+			env = append(env, kv)
+		}
+	}
+	return env
+}
+
+func TestMainProgram_ColorNames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rgb.txt")
+	if err := os.WriteFile(path, []byte("! custom names\n0 110 120\t\tbrand teal\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := exec.Command("go", "run", ".", "--color-names="+path, "--color=BrandTeal:O", "EO").CombinedOutput()
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, output)
+	}
+	want := "|  ____| " + "\033[38;2;0;110;120m" + " / __ \\  " + "\033[0m"
+	if got := strings.SplitN(string(output), "\n", 3)[1]; got != want {
+		t.Errorf("second row = %q, want %q", got, want)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"missing names file", []string{"--color-names=testdata/missing.txt", "--color=red", "hello"}, "cannot load color names"},
+		{"misspelled color", []string{"--color=ornage:ll", "hello"}, "did you mean 'orange'?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := exec.Command("go", append([]string{"run", "."}, tt.args...)...).CombinedOutput()
+			if err == nil || !strings.Contains(string(output), "exit status 4") || !strings.Contains(string(output), tt.want) {
+				t.Errorf("expected color error containing %q, got %v\n%s", tt.want, err, output)
+			}
+		})
+	}
+}
+
+func TestMainProgram_HTML(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "colored fragment",
+			args: []string{"--format=html", "--color=red:O", "EO"},
+			want: []string{
+				"<pre class=\"ascii-art\"> ______  <span style=\"color:#ff0000\">  ____   </span>\n",
+				"|  ____| <span style=\"color:#ff0000\"> / __ \\  </span>\n",
+				"</pre>\n",
+			},
+		},
+		{
+			name: "document with classes",
+			args: []string{"--format=html", "--html-document", "--html-classes", "--bg=navy", "<"},
+			want: []string{
+				"<title>&lt;</title>\n<style>\n.aa-s1 { background-color:#000080 }\n</style>\n",
+				"<span class=\"aa-s1\">  / / </span>\n",
+				"</body>\n</html>\n",
+			},
+		},
+		{
+			name: "plain text",
+			args: []string{"--format=html", "&"},
+			want: []string{"<pre class=\"ascii-art\">         \n  ___    \n ( _ )   \n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := exec.Command("go", append([]string{"run", "."}, tt.args...)...).CombinedOutput()
+			if err != nil {
+				t.Fatalf("unexpected error: %v\n%s", err, output)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(output), want) {
+					t.Errorf("output =\n%s\nwant it to contain %q", output, want)
+				}
+			}
+			if strings.Contains(string(output), "\033") {
+				t.Errorf("output contains escape sequences:\n%q", output)
+			}
+		})
+	}
+}
+
+func TestMainProgram_SVG(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "colored rows",
+			args: []string{"--format=svg", "--color=red:O", "EO"},
+			want: []string{
+				"<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"151.2\" height=\"134.4\" viewBox=\"0 0 151.2 134.4\" font-family=\"monospace\" font-size=\"14\" fill=\"#000000\" xml:space=\"preserve\">\n",
+				"<text y=\"30.1\" textLength=\"151.2\" lengthAdjust=\"spacingAndGlyphs\">|  ____| <tspan fill=\"#ff0000\"> / __ \\  </tspan></text>\n",
+				"</svg>\n",
+			},
+		},
+		{
+			name: "cells, font size and canvas",
+			args: []string{"--format=svg", "--svg-cells", "--font-size=10", "--canvas=black", "I"},
+			want: []string{
+				" width=\"48\" height=\"96\" viewBox=\"0 0 48 96\" font-family=\"monospace\" font-size=\"10\" fill=\"#ffffff\"",
+				"<rect width=\"100%\" height=\"100%\" fill=\"#000000\"/>\n",
+				"<text y=\"21.5\"><tspan x=\"0\">|</tspan><tspan x=\"6\">_</tspan><tspan x=\"30\">_</tspan><tspan x=\"36\">|</tspan></text>\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := exec.Command("go", append([]string{"run", "."}, tt.args...)...).CombinedOutput()
+			if err != nil {
+				t.Fatalf("unexpected error: %v\n%s", err, output)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(output), want) {
+					t.Errorf("output =\n%s\nwant it to contain %q", output, want)
+				}
+			}
+		})
+	}
+
+	output, err := exec.Command("go", "run", ".", "--format=svg", "--canvas=nope", "hello").CombinedOutput()
+	if err == nil || !strings.Contains(string(output), "exit status 4") {
+		t.Errorf("expected color error for an invalid canvas, got %v\n%s", err, output)
+	}
+}
+
+func TestMainProgram_PNG(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "banner.png")
+	cmd := exec.Command("go", "run", ".", "--format=png", "--output="+path, "--scale=1", "--padding=3", "--canvas=white", "--color=red", "I")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, output)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	img, err := png.Decode(file)
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+
+	// The "I" glyph of the standard banner is 8 columns by 8 rows; its first
+	// row is " _____  " with underscores in font row 7.
+	if got, want := img.Bounds(), image.Rect(0, 0, 8*6+6, 8*8+6); got != want {
+		t.Errorf("bounds = %v, want %v", got, want)
+	}
+	tests := []struct {
+		x, y int
+		want color.Color
+	}{
+		{0, 0, color.RGBA{R: 255, G: 255, B: 255, A: 255}},
+		{3 + 6, 3 + 7, color.RGBA{R: 255, A: 255}},
+		{3 + 6, 3 + 6, color.RGBA{R: 255, G: 255, B: 255, A: 255}},
+	}
+	for _, tt := range tests {
+		if got := color.RGBAModel.Convert(img.At(tt.x, tt.y)); got != tt.want {
+			t.Errorf("pixel (%d,%d) = %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestMainProgram_GIF(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantFrames int
+		wantDelay  int
+		wantLoop   int
+		wantBounds image.Rectangle
+	}{
+		{
+			name:       "typewriter, one frame per character",
+			args:       []string{"--scale=1", "--padding=0", "Hi"},
+			wantFrames: 2, wantDelay: 10, wantLoop: 0,
+			wantBounds: image.Rect(0, 0, 13*6, 8*8),
+		},
+		{
+			name:       "typewriter from standard input",
+			args:       []string{"--scale=1", "--padding=0", "--loop=2", "-"},
+			stdin:      "a\nb\n",
+			wantFrames: 2, wantDelay: 10, wantLoop: 1,
+			wantBounds: image.Rect(0, 0, 8*6, 16*8),
+		},
+		{
+			name:       "blink with delay and canvas size",
+			args:       []string{"--effect=blink", "--delay=500", "--canvas-size=200x100", "--color=red:B", "AB"},
+			wantFrames: 2, wantDelay: 50, wantLoop: 0,
+			wantBounds: image.Rect(0, 0, 200, 100),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "banner.gif")
+			cmd := exec.Command("go", append([]string{"run", ".", "--format=gif", "--output=" + path}, tt.args...)...)
+			cmd.Stdin = strings.NewReader(tt.stdin)
+			if output, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("unexpected error: %v\n%s", err, output)
+			}
+
+			file, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			anim, err := gif.DecodeAll(file)
+			if err != nil {
+				t.Fatalf("gif.DecodeAll() error = %v", err)
+			}
+			if len(anim.Image) != tt.wantFrames {
+				t.Fatalf("frames = %d, want %d", len(anim.Image), tt.wantFrames)
+			}
+			if anim.Delay[0] != tt.wantDelay || anim.LoopCount != tt.wantLoop {
+				t.Errorf("delay, loop count = %d, %d; want %d, %d", anim.Delay[0], anim.LoopCount, tt.wantDelay, tt.wantLoop)
+			}
+			if got := anim.Image[0].Bounds(); got != tt.wantBounds {
+				t.Errorf("bounds = %v, want %v", got, tt.wantBounds)
+			}
+		})
+	}
+}
+
+func TestMainProgram_Animate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("interrupting a process is not supported on this platform")
+	}
+
+	binPath := filepath.Join(t.TempDir(), "ascii-art")
+	if output, err := exec.Command("go", "build", "-o", binPath, ".").CombinedOutput(); err != nil {
+		t.Fatalf("failed to build binary: %v\n%s", err, output)
+	}
+
+	t.Run("typewriter ends with the whole banner", func(t *testing.T) {
+		output, err := exec.Command(binPath, "--animate=typewriter", "--fps=60", "Hi").Output()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// Two frames of eight rows, the second drawn over the first.
+		want := "\033[8F _    _   _  \033[K\n| |  | | (_) \033[K\n"
+		if !strings.HasPrefix(string(output), "\033[?25l") || !strings.Contains(string(output), want) ||
+			!strings.HasSuffix(string(output), "\033[?25h") {
+			t.Errorf("output = %q, want hidden cursor, redrawn frame %q and shown cursor", output, want)
+		}
+	})
+
+	t.Run("marquee stops on SIGINT", func(t *testing.T) {
+		var stdout strings.Builder
+		cmd := exec.Command(binPath, "--animate=marquee", "--width=20", "Hello")
+		cmd.Stdout = &stdout
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(500 * time.Millisecond)
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			t.Fatal(err)
+		}
+		if err := cmd.Wait(); err != nil {
+			t.Fatalf("expected a clean exit on SIGINT, got %v", err)
+		}
+		if output := stdout.String(); !strings.Contains(output, "\033[8F") || !strings.HasSuffix(output, "\033[?25h") {
+			t.Errorf("output = %q, want redrawn frames and the cursor shown at the end", output)
+		}
+	})
+}
+
+func TestMainProgram_Reverse(t *testing.T) {
+	dir := t.TempDir()
+	const text = "Hello, World!\n\n{x} = 42"
+	for _, banner := range []string{"standard", "shadow", "thinkertoy"} {
+		t.Run(banner, func(t *testing.T) {
+			art, err := exec.Command("go", "run", ".", text, banner).Output()
+			if err != nil {
+				t.Fatalf("rendering failed: %v", err)
+			}
+			path := filepath.Join(dir, banner+".txt")
+			if err := os.WriteFile(path, art, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			output, err := exec.Command("go", "run", ".", "--reverse="+path).Output()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if want := strings.ReplaceAll(text, "\\n", "\n") + "\n"; string(output) != want {
+				t.Errorf("output = %q, want %q", output, want)
+			}
+		})
+	}
+
+	t.Run("trimmed art from standard input", func(t *testing.T) {
+		art, err := exec.Command("go", "run", ".", "--color=red", "hi there").Output()
+		if err != nil {
+			t.Fatalf("rendering failed: %v", err)
+		}
+		var trimmed []string
+		for _, row := range strings.Split(string(art), "\n") {
+			trimmed = append(trimmed, strings.TrimRight(row, " "))
+		}
+
+		cmd := exec.Command("go", "run", ".", "--reverse=-")
+		cmd.Stdin = strings.NewReader(strings.TrimRight(strings.Join(trimmed, "\n"), "\n"))
+		output, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(output) != "hi there\n" {
+			t.Errorf("output = %q, want %q", output, "hi there\n")
+		}
+	})
+
+	t.Run("wrong banner", func(t *testing.T) {
+		cmd := exec.Command("go", "run", ".", "--reverse="+filepath.Join(dir, "shadow.txt"), "standard")
+		output, err := cmd.CombinedOutput()
+		if err == nil {
+			t.Fatal("expected an error, got none")
+		}
+		if !strings.Contains(string(output), "Error recognizing art with standard: no glyph matches the art at row") {
+			t.Errorf("unexpected error output: %s", output)
+		}
+	})
+}
+
+func TestMainProgram_UserBanners(t *testing.T) {
+	standard, err := os.ReadFile(filepath.Join("..", "..", "internal", "banners", "standard.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	corp := filepath.Join(dir, "corp.txt")
+	if err := os.WriteFile(corp, []byte(strings.ReplaceAll(string(standard), "|", "!")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	env := append(os.Environ(), fontPathEnv+"="+dir)
+	const firstRow = " _    _   _  \n! !  ! ! (_) \n"
+
+	run := func(t *testing.T, env []string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("go", append([]string{"run", "."}, args...)...)
+		cmd.Env = env
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("unexpected error: %v\n%s", err, output)
+		}
+		return string(output)
+	}
+
+	t.Run("banner file", func(t *testing.T) {
+		if output := run(t, nil, "--banner="+corp, "Hi"); !strings.HasPrefix(output, firstRow) {
+			t.Errorf("output:\n%s", output)
+		}
+	})
+
+	t.Run("banner of the search path", func(t *testing.T) {
+		if output := run(t, env, "Hi", "corp"); !strings.HasPrefix(output, firstRow) {
+			t.Errorf("output:\n%s", output)
+		}
+	})
+
+	t.Run("list banners", func(t *testing.T) {
+		want := "standard    built in\nshadow      built in\nthinkertoy  built in\ncorp        " + corp + "\n"
+		if output := run(t, env, "--list-banners"); output != want {
+			t.Errorf("output = %q, want %q", output, want)
+		}
+	})
+
+	t.Run("reverse detects the banner", func(t *testing.T) {
+		art := filepath.Join(t.TempDir(), "art.txt")
+		run(t, env, "-b", "corp", "-o", art, "Hi")
+		if output := run(t, env, "--reverse="+art); output != "Hi\n" {
+			t.Errorf("output = %q, want %q", output, "Hi\n")
+		}
+	})
+
+	t.Run("unknown name", func(t *testing.T) {
+		cmd := exec.Command("go", "run", ".", "-b", "corp", "Hi")
+		cmd.Env = environWithout(fontPathEnv)
+		output, err := cmd.CombinedOutput()
+		if err == nil || !strings.Contains(string(output), "invalid banner name: \"corp\"") {
+			t.Errorf("expected an invalid banner error, got %v\n%s", err, output)
+		}
+	})
+}
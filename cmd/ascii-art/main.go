@@ -6,26 +6,44 @@
 //
 // Usage:
 //
+//	go run . [OPTION]... "text" [banner]
+//	go run . --color=<color> [OPTION]... [substring] "text" [banner]
+//	go run . --help
+//	go run . --list-banners
+//
+// Options such as --banner, --color, --substring, --width, --align and
+// --output may appear anywhere on the command line, in long (--width=40) or
+// short (-w 40) form; "--" ends them. The positional forms are those of the
+// original command line:
+//
 //	go run . "text" [banner]
 //	go run . --color=<color> "text" [banner]
 //	go run . --color=<color> <substring> "text" [banner]
+//	go run . --color=<color>:<substring> [--color=<color>:<substring>]... "text" [banner]
+//	go run . --input=<file> [banner]
+//	command | go run . - [banner]
 //
 // Responsibilities of this package:
 //   - Parse and validate command-line arguments
+//   - Generate the --help text
 //   - Route between normal mode and color mode
+//   - Convert the output to the --format document format
 //   - Validate and resolve banner file paths
 //   - Coordinate between parser, renderer, and coloring
 //   - Handle errors with appropriate exit codes
 //
+// When the text argument is "-", or is omitted while standard input is
+// redirected, the text is read from standard input; --input reads it from a
+// file instead. Each input line is rendered as its own block.
+//
 // Any invalid input, missing files, or rendering errors are reported to stderr.
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
-
-	"ascii-art-color/internal/parser"
-	"ascii-art-color/internal/renderer"
 )
 
 const (
@@ -34,6 +52,8 @@ const (
 	exitCodeBannerError = 2
 	exitCodeRenderError = 3
 	exitCodeColorError  = 4
+	exitCodeInputError  = 5
+	exitCodeOutputError = 6
 
 	// Default banner style.
 	defaultBanner = "standard"
@@ -41,38 +61,77 @@ const (
 
 // main is the entry point of the ascii-art application.
 //
-// It determines whether to run in normal mode or color mode based on
-// the presence of the --color flag, then orchestrates the appropriate
-// packages to render ASCII art with optional ANSI color codes.
+// It parses the command line, then recognizes the --reverse art, plays the
+// --animate animation, writes the --format document, or renders text: in
+// color mode when a --color or --gradient option is given and in normal mode
+// otherwise, orchestrating the appropriate packages to render ASCII art with
+// optional ANSI color codes.
 func main() {
-	if hasColorFlag(os.Args) {
-		runColorMode(os.Args)
-		return
-	}
-
-	text, banner, err := ParseArgs(os.Args)
+	opts, err := parseCommandLine(os.Args, stdinRedirected())
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(exitCodeUsageError)
+		if errors.Is(err, errColorNames) {
+			exit(exitCodeColorError)
+		}
+		exit(exitCodeUsageError)
 	}
-
-	bannerPath, err := GetBannerPath(banner)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(exitCodeUsageError)
+	if opts.help {
+		fmt.Print(helpText())
+		return
+	}
+	if opts.listBanners {
+		if err := writeBannerList(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			exit(exitCodeOutputError)
+		}
+		return
 	}
 
-	charMap, err := parser.LoadBanner(GetBannerFS(), bannerPath)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading banner file: %v\n", err)
-		os.Exit(exitCodeBannerError)
+	out := openOutput(opts)
+
+	switch {
+	case opts.reverse != "":
+		runReverse(out, opts)
+	case opts.animated:
+		runAnimation(out.File, opts)
+	case opts.format == textFormat:
+		render(out, out.File, opts)
+	default:
+		writeDocument(out.File, opts)
 	}
 
-	result, err := renderer.ASCII(text, charMap)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error rendering text: %v\n", err)
-		os.Exit(exitCodeRenderError)
+	out.commit()
+}
+
+// render renders the text as rows of text, in color mode when a coloring
+// option is given and in normal mode otherwise.
+//
+// Parameters:
+//   - w: The writer to write the rows to.
+//   - out: The file the output ends up in, whose terminal sets the defaults
+//     of the width and color depth.
+//   - opts: The parsed command-line options.
+func render(w io.Writer, out *os.File, opts cliOptions) {
+	if opts.colored() {
+		runColorMode(w, out, opts)
+	} else {
+		runNormalMode(w, out, opts)
 	}
+}
+
+// runNormalMode renders the text without color.
+//
+// Parameters:
+//   - w: The writer to write the output to.
+//   - out: The file the output ends up in.
+//   - opts: The parsed command-line options.
+func runNormalMode(w io.Writer, out *os.File, opts cliOptions) {
+	charMap, renderOpts := loadBanner(opts.banner, opts)
+	applyOutputOptions(&renderOpts, opts, out)
 
-	fmt.Print(result)
+	if opts.text == stdinArg {
+		renderInput(w, opts, charMap, renderOpts)
+		return
+	}
+	printArt(w, opts.text, charMap, renderOpts)
 }
@@ -1,16 +1,39 @@
 package main
 
 import (
+	"errors"
+	"image"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/vxanthio/ascii-art-color/internal/animate"
+	"github.com/vxanthio/ascii-art-color/internal/color"
+	"github.com/vxanthio/ascii-art-color/internal/coloring"
+	"github.com/vxanthio/ascii-art-color/internal/export"
+	"github.com/vxanthio/ascii-art-color/internal/renderer"
 )
 
-func TestParseArgs_NoArguments(t *testing.T) {
-	args := []string{"./ascii-art"}
+// mustParse parses args, failing the test on error.
+func mustParse(t *testing.T, args ...string) cliOptions {
+	t.Helper()
+	opts, err := parseCommandLine(append([]string{"./ascii-art"}, args...), false)
+	if err != nil {
+		t.Fatalf("parseCommandLine(%q) unexpected error: %v", args, err)
+	}
+	return opts
+}
 
-	_, _, err := ParseArgs(args)
+func TestParseCommandLine_NoArguments(t *testing.T) {
+	_, err := parseCommandLine([]string{"./ascii-art"}, false)
 
 	if err == nil {
-		t.Error("Expected error for no arguments, got nil")
+		t.Fatal("Expected error for no arguments, got nil")
 	}
 
 	expectedMsg := "usage: go run . \"text\" [banner]"
@@ -19,120 +42,62 @@ func TestParseArgs_NoArguments(t *testing.T) {
 	}
 }
 
-func TestParseArgs_TextOnly(t *testing.T) {
-	args := []string{"./ascii-art", "Hello"}
-
-	text, banner, err := ParseArgs(args)
-
-	if err != nil {
-		t.Errorf("Expected no error, got: %v", err)
-	}
+func TestParseCommandLine_TextOnly(t *testing.T) {
+	opts := mustParse(t, "Hello")
 
-	if text != "Hello" {
-		t.Errorf("Expected text: 'Hello', got: %q", text)
+	if opts.text != "Hello" {
+		t.Errorf("Expected text: 'Hello', got: %q", opts.text)
 	}
 
-	if banner != "standard" {
-		t.Errorf("Expected banner: 'standard', got: %q", banner)
+	if opts.banner != "standard" {
+		t.Errorf("Expected banner: 'standard', got: %q", opts.banner)
 	}
 }
 
-func TestParseArgs_TextAndBanner(t *testing.T) {
-	args := []string{"./ascii-art", "Hello", "shadow"}
+func TestParseCommandLine_TextAndBanner(t *testing.T) {
+	opts := mustParse(t, "Hello", "shadow")
 
-	text, banner, err := ParseArgs(args)
-
-	if err != nil {
-		t.Errorf("Expected no error, got: %v", err)
-	}
-
-	if text != "Hello" {
-		t.Errorf("Expected text: 'Hello', got: %q", text)
+	if opts.text != "Hello" {
+		t.Errorf("Expected text: 'Hello', got: %q", opts.text)
 	}
 
-	if banner != "shadow" {
-		t.Errorf("Expected banner: 'shadow', got: %q", banner)
+	if opts.banner != "shadow" {
+		t.Errorf("Expected banner: 'shadow', got: %q", opts.banner)
 	}
 }
 
-func TestParseArgs_TooManyArguments(t *testing.T) {
-	args := []string{"./ascii-art", "Hello", "shadow", "extra"}
-
-	_, _, err := ParseArgs(args)
+func TestParseCommandLine_TooManyArguments(t *testing.T) {
+	_, err := parseCommandLine([]string{"./ascii-art", "Hello", "shadow", "extra"}, false)
 
 	if err == nil {
 		t.Error("Expected error for too many arguments, got nil")
 	}
 }
 
-func TestParseArgs_AllBannerTypes(t *testing.T) {
-	testCases := []struct {
-		args           []string
-		expectedBanner string
-	}{
-		{[]string{"prog", "Hi", "standard"}, "standard"},
-		{[]string{"prog", "Hi", "shadow"}, "shadow"},
-		{[]string{"prog", "Hi", "thinkertoy"}, "thinkertoy"},
-	}
-
-	for _, tc := range testCases {
-		_, banner, err := ParseArgs(tc.args)
-
-		if err != nil {
-			t.Errorf("Args %v: expected no error, got: %v", tc.args, err)
+func TestParseCommandLine_AllBannerTypes(t *testing.T) {
+	for _, banner := range []string{"standard", "shadow", "thinkertoy"} {
+		if opts := mustParse(t, "Hi", banner); opts.banner != banner {
+			t.Errorf("expected banner %q, got: %q", banner, opts.banner)
 		}
-
-		if banner != tc.expectedBanner {
-			t.Errorf("Args %v: expected banner %q, got: %q",
-				tc.args, tc.expectedBanner, banner)
+		if opts := mustParse(t, "--banner="+banner, "Hi"); opts.banner != banner {
+			t.Errorf("--banner: expected banner %q, got: %q", banner, opts.banner)
 		}
 	}
 }
 
-func TestParseArgs_EmptyStringText(t *testing.T) {
-	args := []string{"./ascii-art", ""}
-
-	text, banner, err := ParseArgs(args)
-
-	if err != nil {
-		t.Errorf("Expected no error for empty string, got: %v", err)
-	}
-
-	if text != "" {
-		t.Errorf("Expected empty text, got: %q", text)
-	}
-
-	if banner != "standard" {
-		t.Errorf("Expected banner: 'standard', got: %q", banner)
-	}
-}
+func TestParseCommandLine_EmptyStringText(t *testing.T) {
+	opts := mustParse(t, "")
 
-func TestHasColorFlag(t *testing.T) {
-	tests := []struct {
-		name string
-		args []string
-		want bool
-	}{
-		{"no args", []string{"prog"}, false},
-		{"text only", []string{"prog", "hello"}, false},
-		{"text and banner", []string{"prog", "hello", "shadow"}, false},
-		{"color flag present", []string{"prog", "--color=red", "hello"}, true},
-		{"color flag with substring", []string{"prog", "--color=red", "sub", "hello"}, true},
-		{"wrong format dash", []string{"prog", "-color=red", "hello"}, false},
-		{"wrong format colon", []string{"prog", "--color:red", "hello"}, true},
+	if opts.text != "" {
+		t.Errorf("Expected empty text, got: %q", opts.text)
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := hasColorFlag(tt.args)
-			if got != tt.want {
-				t.Errorf("hasColorFlag(%v) = %v, want %v", tt.args, got, tt.want)
-			}
-		})
+	if opts.banner != "standard" {
+		t.Errorf("Expected banner: 'standard', got: %q", opts.banner)
 	}
 }
 
-func TestExtractColorArgs(t *testing.T) {
+func TestParseCommandLine_Positionals(t *testing.T) {
 	tests := []struct {
 		name      string
 		args      []string
@@ -140,118 +105,1353 @@ func TestExtractColorArgs(t *testing.T) {
 		wantSub   string
 		wantText  string
 		wantBnr   string
-		wantErr   bool
 	}{
 		{
 			name:      "flag and text only",
-			args:      []string{"prog", "--color=red", "hello"},
+			args:      []string{"--color=red", "hello"},
 			wantColor: "red", wantSub: "", wantText: "hello", wantBnr: "standard",
 		},
 		{
 			name:      "flag text and banner",
-			args:      []string{"prog", "--color=red", "hello", "shadow"},
+			args:      []string{"--color=red", "hello", "shadow"},
 			wantColor: "red", wantSub: "", wantText: "hello", wantBnr: "shadow",
 		},
 		{
 			name:      "flag substring and text",
-			args:      []string{"prog", "--color=red", "sub", "hello"},
+			args:      []string{"--color=red", "sub", "hello"},
 			wantColor: "red", wantSub: "sub", wantText: "hello", wantBnr: "standard",
 		},
 		{
 			name:      "flag substring text and banner",
-			args:      []string{"prog", "--color=red", "sub", "hello", "thinkertoy"},
+			args:      []string{"--color=red", "sub", "hello", "thinkertoy"},
 			wantColor: "red", wantSub: "sub", wantText: "hello", wantBnr: "thinkertoy",
 		},
 		{
 			name:      "audit case orange GuYs",
-			args:      []string{"prog", "--color=orange", "GuYs", "HeY GuYs"},
+			args:      []string{"--color=orange", "GuYs", "HeY GuYs"},
 			wantColor: "orange", wantSub: "GuYs", wantText: "HeY GuYs", wantBnr: "standard",
 		},
 		{
 			name:      "audit case blue B",
-			args:      []string{"prog", "--color=blue", "B", "RGB()"},
+			args:      []string{"--color=blue", "B", "RGB()"},
 			wantColor: "blue", wantSub: "B", wantText: "RGB()", wantBnr: "standard",
 		},
 		{
 			name:      "hex color",
-			args:      []string{"prog", "--color=#ff0000", "hello"},
+			args:      []string{"--color=#ff0000", "hello"},
 			wantColor: "#ff0000", wantSub: "", wantText: "hello", wantBnr: "standard",
 		},
 		{
 			name:      "rgb color",
-			args:      []string{"prog", "--color=rgb(255,0,0)", "hello"},
+			args:      []string{"--color=rgb(255,0,0)", "hello"},
 			wantColor: "rgb(255,0,0)", wantSub: "", wantText: "hello", wantBnr: "standard",
 		},
 		{
 			name:      "newline in text",
-			args:      []string{"prog", "--color=red", "hello\\nworld"},
+			args:      []string{"--color=red", "hello\\nworld"},
 			wantColor: "red", wantSub: "", wantText: "hello\nworld", wantBnr: "standard",
 		},
 		{
-			name:    "missing text after flag",
-			args:    []string{"prog", "--color=red"},
-			wantErr: true,
+			name:      "color flag after text",
+			args:      []string{"sub", "hello", "--color=red"},
+			wantColor: "red", wantSub: "sub", wantText: "hello", wantBnr: "standard",
+		},
+		{
+			name:      "substring option",
+			args:      []string{"--color=red", "--substring=ell", "hello", "shadow"},
+			wantColor: "red", wantSub: "ell", wantText: "hello", wantBnr: "shadow",
+		},
+		{
+			name:      "banner option leaves substring and text",
+			args:      []string{"-c", "red", "ell", "hello", "-b", "shadow"},
+			wantColor: "red", wantSub: "ell", wantText: "hello", wantBnr: "shadow",
+		},
+		{
+			name:      "banner name as text with banner option",
+			args:      []string{"--color=red", "--banner=thinkertoy", "shadow"},
+			wantColor: "red", wantSub: "", wantText: "shadow", wantBnr: "thinkertoy",
+		},
+		{
+			name:      "color rules",
+			args:      []string{"--color=red:ERROR", "--color=green:OK", "ERROR OK"},
+			wantColor: "red:ERROR green:OK", wantSub: "", wantText: "ERROR OK", wantBnr: "standard",
+		},
+		{
+			name:      "color rules with banner",
+			args:      []string{"--color=red:ERROR", "-c", "green:OK", "ERROR OK", "shadow"},
+			wantColor: "red:ERROR green:OK", wantSub: "", wantText: "ERROR OK", wantBnr: "shadow",
+		},
+		{
+			name:      "color rule and bare color with substring",
+			args:      []string{"--color=red:ERROR", "--color=blue", "OK", "ERROR OK"},
+			wantColor: "red:ERROR blue", wantSub: "OK", wantText: "ERROR OK", wantBnr: "standard",
+		},
+		{
+			name:     "double dash ends options",
+			args:     []string{"--", "-5", "shadow"},
+			wantText: "-5", wantBnr: "shadow",
+		},
+		{
+			name:     "text starting with a dash",
+			args:     []string{"-5", "shadow"},
+			wantText: "-5", wantBnr: "shadow",
+		},
+		{
+			name:     "dash reads stdin",
+			args:     []string{"-", "shadow"},
+			wantText: stdinArg, wantBnr: "shadow",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			colorSpec, sub, text, bnr, err := extractColorArgs(tt.args)
-			if tt.wantErr {
-				if err == nil {
-					t.Fatalf("expected error, got nil")
-				}
-				return
+			opts := mustParse(t, tt.args...)
+			if strings.Join(opts.colors, " ") != tt.wantColor {
+				t.Errorf("colors = %q, want %q", opts.colors, tt.wantColor)
 			}
-			if err != nil {
-				t.Fatalf("unexpected error: %v", err)
+			if opts.substring != tt.wantSub {
+				t.Errorf("substring = %q, want %q", opts.substring, tt.wantSub)
 			}
-			if colorSpec != tt.wantColor {
-				t.Errorf("color = %q, want %q", colorSpec, tt.wantColor)
+			if opts.text != tt.wantText {
+				t.Errorf("text = %q, want %q", opts.text, tt.wantText)
 			}
-			if sub != tt.wantSub {
-				t.Errorf("substring = %q, want %q", sub, tt.wantSub)
+			if opts.banner != tt.wantBnr {
+				t.Errorf("banner = %q, want %q", opts.banner, tt.wantBnr)
 			}
-			if text != tt.wantText {
-				t.Errorf("text = %q, want %q", text, tt.wantText)
+		})
+	}
+}
+
+func TestParseCommandLine_Options(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantFont string
+		wantMode renderer.Layout
+		wantFall rune
+		wantWide int
+		wantAlgn renderer.Align
+		wantFile string
+		wantOut  string
+	}{
+		{
+			name: "no options",
+			args: []string{"hello", "shadow"},
+		},
+		{
+			name:     "font after text",
+			args:     []string{"hello", "--font=fonts/slant.flf"},
+			wantFont: "fonts/slant.flf",
+		},
+		{
+			name:     "font before color flag",
+			args:     []string{"--font=slant.flf", "--color=red", "hello"},
+			wantFont: "slant.flf",
+		},
+		{
+			name:     "layout and font",
+			args:     []string{"--layout=smushing", "hello", "--font=slant.flf"},
+			wantFont: "slant.flf",
+			wantMode: renderer.Smushing,
+		},
+		{
+			name:     "multi-byte fallback",
+			args:     []string{"--fallback=¿", "Café"},
+			wantFall: '¿',
+		},
+		{
+			name:     "width",
+			args:     []string{"hello", "--width=60"},
+			wantWide: 60,
+		},
+		{
+			name:     "short width attached",
+			args:     []string{"hello", "-w60"},
+			wantWide: 60,
+		},
+		{
+			name:     "align",
+			args:     []string{"--align=center", "hello"},
+			wantAlgn: renderer.AlignCenter,
+		},
+		{
+			name:     "short align separate",
+			args:     []string{"-a", "right", "hello"},
+			wantAlgn: renderer.AlignRight,
+		},
+		{
+			name:     "input",
+			args:     []string{"--input=notes.txt", "shadow"},
+			wantFile: "notes.txt",
+		},
+		{
+			name:    "output",
+			args:    []string{"hello", "-o", "banner.txt"},
+			wantOut: "banner.txt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := mustParse(t, tt.args...)
+			if opts.font != tt.wantFont {
+				t.Errorf("font = %q, want %q", opts.font, tt.wantFont)
 			}
-			if bnr != tt.wantBnr {
-				t.Errorf("banner = %q, want %q", bnr, tt.wantBnr)
+			if opts.layout != tt.wantMode || opts.layoutSet != (tt.wantMode != renderer.FullWidth) {
+				t.Errorf("layout = %v (set %t), want %v", opts.layout, opts.layoutSet, tt.wantMode)
+			}
+			if opts.width != tt.wantWide || opts.widthSet != (tt.wantWide != 0) {
+				t.Errorf("width = %d (set %t), want %d", opts.width, opts.widthSet, tt.wantWide)
+			}
+			if opts.align != tt.wantAlgn {
+				t.Errorf("align = %v, want %v", opts.align, tt.wantAlgn)
+			}
+			if opts.input != tt.wantFile {
+				t.Errorf("input = %q, want %q", opts.input, tt.wantFile)
+			}
+			if opts.output != tt.wantOut {
+				t.Errorf("output = %q, want %q", opts.output, tt.wantOut)
+			}
+			if opts.fallback != tt.wantFall {
+				t.Errorf("fallback = %q, want %q", opts.fallback, tt.wantFall)
 			}
 		})
 	}
 }
 
-func TestGetBannerPath_ValidBanners(t *testing.T) {
-	testCases := []struct {
-		banner       string
-		expectedPath string
+func TestParseCommandLine_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantUsage error
 	}{
-		{"standard", "testdata/standard.txt"},
-		{"shadow", "testdata/shadow.txt"},
-		{"thinkertoy", "testdata/thinkertoy.txt"},
+		{"align without value", []string{"--align=", "hello"}, errColorUsage},
+		{"unknown alignment", []string{"--align=middle", "hello"}, nil},
+		{"negative width", []string{"hello", "--width=-1"}, nil},
+		{"non-numeric width", []string{"hello", "--width=wide"}, nil},
+		{"fallback without value", []string{"--fallback=", "hello"}, errColorUsage},
+		{"fallback with several characters", []string{"--fallback=??", "hello"}, nil},
+		{"layout without value", []string{"--layout=", "hello"}, errColorUsage},
+		{"unknown layout", []string{"--layout=squash", "hello"}, nil},
+		{"font without value", []string{"hello", "--font="}, errColorUsage},
+		{"input without value", []string{"--input="}, errColorUsage},
+		{"short option without value", []string{"hello", "-w"}, errColorUsage},
+		{"unknown option", []string{"--colour=red", "hello"}, errColorUsage},
+		{"long option value in the next argument", []string{"--width", "40", "hello"}, errColorUsage},
+		{"color without equals", []string{"--color", "red", "banana"}, errColorUsage},
+		{"color with colon", []string{"--color:red", "hello"}, errColorUsage},
+		{"missing text after color", []string{"--color=red"}, errColorUsage},
+		{"substring argument without bare color", []string{"--color=red:a", "--color=blue:b", "sub", "text", "shadow"}, errColorUsage},
+		{"too many color arguments", []string{"--color=red", "sub", "text", "shadow", "extra"}, errColorUsage},
+		{"substring without color", []string{"--substring=ell", "hello"}, errColorUsage},
+		{"gradient direction without gradient", []string{"--gradient-direction=vertical", "hello"}, errColorUsage},
+		{"unknown gradient direction", []string{"--gradient=red,blue", "--gradient-direction=diagonal", "hello"}, nil},
+		{"unknown gradient space", []string{"--gradient=red,blue", "--gradient-space=lab", "hello"}, nil},
+		{"cycle without palette", []string{"--cycle=word", "hello"}, errColorUsage},
+		{"unknown cycle", []string{"--rainbow", "--cycle=column", "hello"}, nil},
+		{"rainbow with value", []string{"--rainbow=yes", "hello"}, errColorUsage},
+		{"unknown attribute", []string{"--attr=bold,shiny", "hello"}, nil},
+		{"unknown color mode", []string{"--color-mode=8", "--color=red", "hello"}, nil},
+		{"unknown format", []string{"--format=pdf", "hello"}, nil},
+		{"html document without html", []string{"--html-document", "hello"}, nil},
+		{"html classes with text", []string{"--format=text", "--html-classes", "hello"}, nil},
+		{"svg cells with html", []string{"--format=html", "--svg-cells", "hello"}, nil},
+		{"font size without svg", []string{"--font-size=20", "hello"}, nil},
+		{"zero font size", []string{"--format=svg", "--font-size=0", "hello"}, nil},
+		{"font size not a number", []string{"--format=svg", "--font-size=big", "hello"}, nil},
+		{"canvas with html", []string{"--format=html", "--canvas=white", "hello"}, nil},
+		{"scale without png", []string{"--scale=2", "hello"}, nil},
+		{"padding with svg", []string{"--format=svg", "--padding=2", "hello"}, nil},
+		{"zero scale", []string{"--format=png", "--scale=0", "hello"}, nil},
+		{"scale too large", []string{"--format=png", "--scale=33", "hello"}, nil},
+		{"negative padding", []string{"--format=png", "--padding=-1", "hello"}, nil},
+		{"effect with png", []string{"--format=png", "--effect=blink", "hello"}, nil},
+		{"unknown effect", []string{"--format=gif", "--effect=fade", "hello"}, nil},
+		{"delay too short", []string{"--format=gif", "--delay=5", "hello"}, nil},
+		{"negative loop", []string{"--format=gif", "--loop=-1", "hello"}, nil},
+		{"malformed canvas size", []string{"--format=gif", "--canvas-size=640", "hello"}, nil},
+		{"typewriter centered", []string{"--format=gif", "--align=center", "hello"}, nil},
+		{"unknown animation", []string{"--animate=spin", "hello"}, nil},
+		{"animation with html", []string{"--format=html", "--animate=wave", "hello"}, nil},
+		{"fps without animation", []string{"--fps=20", "hello"}, nil},
+		{"fps too high", []string{"--animate=wave", "--fps=61", "hello"}, nil},
+		{"wave justified", []string{"--animate=wave", "--align=justify", "hello"}, nil},
+		{"reverse with text", []string{"--reverse=art.txt", "hello", "shadow"}, nil},
+		{"force without output", []string{"--force", "hello"}, nil},
+		{"animation to a file", []string{"--animate=wave", "--output=wave.txt", "hello"}, nil},
+		{"gif option with svg output", []string{"--output=banner.svg", "--effect=marquee", "hello"}, nil},
+		{"reverse with color", []string{"--reverse=art.txt", "--color=red"}, nil},
+		{"reverse with input", []string{"--reverse=art.txt", "--input=notes.txt"}, nil},
+		{"bg without value", []string{"--bg=", "hello"}, errColorUsage},
+		{"match without color", []string{"--match=regex", "hello"}, errColorUsage},
+		{"ignore case without color", []string{"--ignore-case", "hello"}, errColorUsage},
+		{"unknown match mode", []string{"--color=red:a", "--match=glob", "abc"}, nil},
+		{"zero occurrence", []string{"--color=red:a", "--occurrence=0", "abc"}, nil},
+		{"ignore case with value", []string{"--color=red:a", "--ignore-case=yes", "abc"}, errColorUsage},
+		{"too many arguments", []string{"Hello", "shadow", "extra"}, errTextUsage},
 	}
 
-	for _, tc := range testCases {
-		path, err := GetBannerPath(tc.banner)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCommandLine(append([]string{"./ascii-art"}, tt.args...), false)
+			if err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+			if tt.wantUsage != nil && !strings.Contains(err.Error(), tt.wantUsage.Error()) {
+				t.Errorf("error %q does not include the usage %q", err, tt.wantUsage)
+			}
+		})
+	}
+}
 
-		if err != nil {
-			t.Errorf("Banner %q: expected no error, got: %v", tc.banner, err)
-		}
+func TestParseCommandLine_Match(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want coloring.MatchOptions
+	}{
+		{"defaults", []string{"--color=red:a", "abc"}, coloring.MatchOptions{}},
+		{"regex", []string{"--color=red:[0-9]", "--match=regex", "a1"}, coloring.MatchOptions{Mode: coloring.MatchRegexp}},
+		{"word ignoring case", []string{"-m", "word", "-I", "--color=red", "go", "Go go"}, coloring.MatchOptions{Mode: coloring.MatchWord, IgnoreCase: true}},
+		{"occurrence", []string{"--color=red:a", "-n2", "aaa"}, coloring.MatchOptions{Occurrence: 2}},
+	}
 
-		if path != tc.expectedPath {
-			t.Errorf("Banner %q: expected path %q, got: %q",
-				tc.banner, tc.expectedPath, path)
-		}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mustParse(t, tt.args...).match; got != tt.want {
+				t.Errorf("match = %+v, want %+v", got, tt.want)
+			}
+		})
 	}
 }
 
-func TestGetBannerPath_InvalidBanner(t *testing.T) {
-	banner := "invalid"
+func TestParseCommandLine_Gradient(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		wantGradients string
+		wantSubstring string
+		wantText      string
+		wantVertical  bool
+		wantSpace     color.Space
+	}{
+		{
+			name:          "whole text",
+			args:          []string{"--gradient=#ff0000,#0000ff", "hello"},
+			wantGradients: "#ff0000,#0000ff",
+			wantText:      "hello",
+		},
+		{
+			name:          "substring argument",
+			args:          []string{"-g", "red,blue", "ell", "hello"},
+			wantGradients: "red,blue",
+			wantSubstring: "ell",
+			wantText:      "hello",
+		},
+		{
+			name:          "own text with color rule",
+			args:          []string{"--gradient=red,rgb(0,255,0),blue:ell", "--color=red:o", "hello"},
+			wantGradients: "red,rgb(0,255,0),blue:ell",
+			wantText:      "hello",
+		},
+		{
+			name:          "vertical oklab",
+			args:          []string{"--gradient=red,blue", "--gradient-direction=vertical", "--gradient-space=oklab", "hello"},
+			wantGradients: "red,blue",
+			wantText:      "hello",
+			wantVertical:  true,
+			wantSpace:     color.SpaceOKLab,
+		},
+	}
 
-	_, err := GetBannerPath(banner)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := mustParse(t, tt.args...)
+			if got := strings.Join(opts.gradients, " "); got != tt.wantGradients {
+				t.Errorf("gradients = %q, want %q", got, tt.wantGradients)
+			}
+			if opts.substring != tt.wantSubstring {
+				t.Errorf("substring = %q, want %q", opts.substring, tt.wantSubstring)
+			}
+			if opts.text != tt.wantText {
+				t.Errorf("text = %q, want %q", opts.text, tt.wantText)
+			}
+			if opts.vertical != tt.wantVertical {
+				t.Errorf("vertical = %t, want %t", opts.vertical, tt.wantVertical)
+			}
+			if opts.space != tt.wantSpace {
+				t.Errorf("space = %v, want %v", opts.space, tt.wantSpace)
+			}
+		})
+	}
+}
 
-	if err == nil {
-		t.Error("Expected error for invalid banner, got nil")
+func TestParseCommandLine_Palette(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		wantPalettes  string
+		wantSubstring string
+		wantText      string
+		wantCycle     coloring.Cycle
+	}{
+		{
+			name:         "rainbow",
+			args:         []string{"--rainbow", "hello"},
+			wantPalettes: "rainbow",
+			wantText:     "hello",
+		},
+		{
+			name:          "palette with substring argument",
+			args:          []string{"-p", "pride", "ell", "hello"},
+			wantPalettes:  "pride",
+			wantSubstring: "ell",
+			wantText:      "hello",
+		},
+		{
+			name:         "rainbow before palettes, word cycle",
+			args:         []string{"--palette=nord:ell", "-r", "--cycle=word", "hello"},
+			wantPalettes: "rainbow nord:ell",
+			wantText:     "hello",
+			wantCycle:    coloring.CycleWord,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := mustParse(t, tt.args...)
+			if got := strings.Join(opts.palettes, " "); got != tt.wantPalettes {
+				t.Errorf("palettes = %q, want %q", got, tt.wantPalettes)
+			}
+			if opts.substring != tt.wantSubstring {
+				t.Errorf("substring = %q, want %q", opts.substring, tt.wantSubstring)
+			}
+			if opts.text != tt.wantText {
+				t.Errorf("text = %q, want %q", opts.text, tt.wantText)
+			}
+			if opts.cycle != tt.wantCycle {
+				t.Errorf("cycle = %v, want %v", opts.cycle, tt.wantCycle)
+			}
+		})
+	}
+}
+
+func TestParsePaletteRule(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         string
+		wantColors    int
+		wantSubstring string
+		wantHas       bool
+		wantErr       bool
+	}{
+		{"name only", "solarized", 8, "", false, false},
+		{"case-insensitive", "Pastel", 6, "", false, false},
+		{"with text", "pride:v1.0", 6, "v1.0", true, false},
+		{"unknown", "vaporwave", 0, "", false, true},
+		{"empty text", "nord:", 0, "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			colors, substring, has, err := parsePaletteRule(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePaletteRule(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if len(colors) != tt.wantColors || substring != tt.wantSubstring || has != tt.wantHas {
+				t.Errorf("parsePaletteRule(%q) = %d colors, %q, %t; want %d colors, %q, %t",
+					tt.value, len(colors), substring, has, tt.wantColors, tt.wantSubstring, tt.wantHas)
+			}
+		})
+	}
+}
+
+func TestParseCommandLine_Style(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		wantBg        string
+		wantAttrs     color.Attr
+		wantSubstring string
+		wantText      string
+	}{
+		{
+			name:     "background alone colors the text",
+			args:     []string{"--bg=navy", "hello"},
+			wantBg:   "navy",
+			wantText: "hello",
+		},
+		{
+			name:          "attributes alone take a substring",
+			args:          []string{"--attr=bold,underline", "ell", "hello"},
+			wantAttrs:     color.Bold | color.Underline,
+			wantSubstring: "ell",
+			wantText:      "hello",
+		},
+		{
+			name:     "with color rules naming their text",
+			args:     []string{"--color=red:ell", "--bg=white", "hello", "shadow"},
+			wantBg:   "white",
+			wantText: "hello",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := mustParse(t, tt.args...)
+			if opts.background != tt.wantBg {
+				t.Errorf("background = %q, want %q", opts.background, tt.wantBg)
+			}
+			if opts.attrs != tt.wantAttrs {
+				t.Errorf("attrs = %v, want %v", opts.attrs, tt.wantAttrs)
+			}
+			if opts.substring != tt.wantSubstring {
+				t.Errorf("substring = %q, want %q", opts.substring, tt.wantSubstring)
+			}
+			if opts.text != tt.wantText {
+				t.Errorf("text = %q, want %q", opts.text, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestParseCommandLine_ColorMode(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"default is auto", []string{"--color=red", "hello"}, ""},
+		{"explicit auto", []string{"--color-mode=AUTO", "--color=red", "hello"}, ""},
+		{"256 colors", []string{"--color-mode=256", "--color=red", "hello"}, "256"},
+		{"without coloring", []string{"--color-mode=none", "hello"}, "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mustParse(t, tt.args...).colorMode; got != tt.want {
+				t.Errorf("colorMode = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCommandLine_Format(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantFormat  string
+		wantDoc     bool
+		wantClasses bool
+	}{
+		{"default is text", []string{"hello"}, textFormat, false, false},
+		{"html fragment", []string{"--format=HTML", "hello"}, htmlFormat, false, false},
+		{"html document with classes", []string{"--format=html", "--html-document", "--html-classes", "hello"}, htmlFormat, true, true},
+		{"svg", []string{"--format=svg", "hello"}, svgFormat, false, false},
+		{"png", []string{"--format=PNG", "hello"}, pngFormat, false, false},
+		{"gif", []string{"--format=gif", "hello"}, gifFormat, false, false},
+		{"output extension", []string{"--output=banner.SVG", "hello"}, svgFormat, false, false},
+		{"output extension with html options", []string{"-o", "page.htm", "--html-document", "hello"}, htmlFormat, true, false},
+		{"format wins over the extension", []string{"--output=banner.svg", "--format=text", "hello"}, textFormat, false, false},
+		{"unknown extension", []string{"--output=banner.out", "hello"}, textFormat, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := mustParse(t, tt.args...)
+			if opts.format != tt.wantFormat || opts.htmlDocument != tt.wantDoc || opts.htmlClasses != tt.wantClasses {
+				t.Errorf("format, htmlDocument, htmlClasses = %q, %t, %t; want %q, %t, %t",
+					opts.format, opts.htmlDocument, opts.htmlClasses, tt.wantFormat, tt.wantDoc, tt.wantClasses)
+			}
+		})
+	}
+}
+
+func TestParseCommandLine_SVG(t *testing.T) {
+	opts := mustParse(t, "--format=svg", "--svg-cells", "--font-size=20.5", "--canvas=navy", "hello")
+	if !opts.svgCells || opts.fontSize != 20.5 || opts.canvas != "navy" {
+		t.Errorf("svgCells, fontSize, canvas = %t, %v, %q; want true, 20.5, \"navy\"", opts.svgCells, opts.fontSize, opts.canvas)
+	}
+}
+
+func TestParseCommandLine_PNG(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantScale   int
+		wantPadding int
+		wantCanvas  string
+	}{
+		{"defaults", []string{"--format=png", "hello"}, 0, defaultPadding, ""},
+		{"scale, padding and canvas", []string{"--format=png", "--scale=4", "--padding=0", "--canvas=white", "hello"}, 4, 0, "white"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := mustParse(t, tt.args...)
+			if opts.scale != tt.wantScale || opts.padding != tt.wantPadding || opts.canvas != tt.wantCanvas {
+				t.Errorf("scale, padding, canvas = %d, %d, %q; want %d, %d, %q",
+					opts.scale, opts.padding, opts.canvas, tt.wantScale, tt.wantPadding, tt.wantCanvas)
+			}
+		})
+	}
+}
+
+func TestParseCommandLine_GIF(t *testing.T) {
+	opts := mustParse(t, "--format=gif", "--effect=Marquee", "--delay=250", "--loop=3", "--canvas-size=640x200", "--align=center", "hello")
+	if opts.effect != export.EffectMarquee || opts.delay != 250*time.Millisecond || opts.loops != 3 {
+		t.Errorf("effect, delay, loops = %v, %v, %d; want marquee, 250ms, 3", opts.effect, opts.delay, opts.loops)
+	}
+	if opts.canvasWidth != 640 || opts.canvasHeight != 200 {
+		t.Errorf("canvas size = %dx%d, want 640x200", opts.canvasWidth, opts.canvasHeight)
+	}
+
+	opts = mustParse(t, "--format=gif", "hello")
+	if opts.effect != export.EffectTypewriter || opts.delay != 0 || opts.loops != 0 {
+		t.Errorf("defaults = %v, %v, %d; want typewriter, 0, 0", opts.effect, opts.delay, opts.loops)
+	}
+}
+
+func TestParseCommandLine_Animate(t *testing.T) {
+	opts := mustParse(t, "--animate=Marquee", "--fps=24", "--align=center", "hello")
+	if !opts.animated || opts.animation != animate.EffectMarquee || opts.fps != 24 {
+		t.Errorf("animated, animation, fps = %t, %v, %d; want true, marquee, 24", opts.animated, opts.animation, opts.fps)
+	}
+
+	opts = mustParse(t, "--animate=typewriter", "hello")
+	if !opts.animated || opts.animation != animate.EffectTypewriter || opts.fps != 0 {
+		t.Errorf("animated, animation, fps = %t, %v, %d; want true, typewriter, 0", opts.animated, opts.animation, opts.fps)
+	}
+}
+
+func TestPlay(t *testing.T) {
+	frames := [][]string{{"a", "b"}, {"c", "d"}}
+	const k = "\033[K\n"
+
+	var buf strings.Builder
+	if err := play(&buf, frames, time.Millisecond, false, nil); err != nil {
+		t.Fatalf("play() error = %v", err)
+	}
+	want := "\033[?25l" + "a" + k + "b" + k + "\033[2F" + "c" + k + "d" + k + "\033[?25h"
+	if got := buf.String(); got != want {
+		t.Errorf("play() wrote %q, want %q", got, want)
+	}
+
+	// A repeating animation plays until stopped, and still shows the cursor.
+	stop := make(chan os.Signal, 1)
+	stop <- os.Interrupt
+	buf.Reset()
+	if err := play(&buf, frames, time.Hour, true, stop); err != nil {
+		t.Fatalf("play() error = %v", err)
+	}
+	want = "\033[?25l" + "a" + k + "b" + k + "\033[?25h"
+	if got := buf.String(); got != want {
+		t.Errorf("play() until stopped wrote %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	if err := play(&buf, nil, time.Millisecond, true, nil); err != nil || buf.Len() != 0 {
+		t.Errorf("play() without frames wrote %q, %v; want nothing", buf.String(), err)
+	}
+}
+
+func TestParseCanvasSize(t *testing.T) {
+	tests := []struct {
+		value      string
+		wantWidth  int
+		wantHeight int
+		wantErr    bool
+	}{
+		{"640x200", 640, 200, false},
+		{"1X1", 1, 1, false},
+		{"640", 0, 0, true},
+		{"0x200", 0, 0, true},
+		{"640x", 0, 0, true},
+		{"20000x10", 0, 0, true},
+	}
+	for _, tt := range tests {
+		width, height, err := parseCanvasSize(tt.value)
+		if (err != nil) != tt.wantErr || width != tt.wantWidth || height != tt.wantHeight {
+			t.Errorf("parseCanvasSize(%q) = %d, %d, %v; want %d, %d, error %t",
+				tt.value, width, height, err, tt.wantWidth, tt.wantHeight, tt.wantErr)
+		}
+	}
+}
+
+func TestGlyphCells(t *testing.T) {
+	tests := []struct {
+		name string
+		opts cliOptions
+		want []image.Rectangle
+	}{
+		{
+			name: "one line",
+			opts: cliOptions{text: "Hi"},
+			want: []image.Rectangle{image.Rect(0, 0, 9, 8), image.Rect(9, 0, 13, 8)},
+		},
+		{
+			name: "empty line between lines",
+			opts: cliOptions{text: "i\n\ni"},
+			want: []image.Rectangle{image.Rect(0, 0, 4, 8), image.Rect(0, 9, 4, 17)},
+		},
+		{
+			name: "wrapped line",
+			opts: cliOptions{text: "i i", width: 6, widthSet: true},
+			want: []image.Rectangle{image.Rect(0, 0, 4, 8), image.Rect(0, 8, 4, 16)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.banner = defaultBanner
+			got := glyphCells(nil, tt.opts)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("glyphCells() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequiredFormats(t *testing.T) {
+	tests := []struct {
+		option string
+		want   string
+	}{
+		{htmlDocOption, "--format=html"},
+		{scaleOption, "--format=png or --format=gif"},
+		{canvasOption, "--format=svg, --format=png or --format=gif"},
+	}
+	for _, tt := range tests {
+		if got := requiredFormats(tt.option); got != tt.want {
+			t.Errorf("requiredFormats(%q) = %q, want %q", tt.option, got, tt.want)
+		}
+	}
+}
+
+func TestCanvasColor(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    export.Color
+		wantErr bool
+	}{
+		{"", export.Color{}, false},
+		{"navy", export.Color{B: 128, Valid: true}, false},
+		{"#fff", export.Color{R: 255, G: 255, B: 255, Valid: true}, false},
+		{"nope", export.Color{}, true},
+	}
+	for _, tt := range tests {
+		got, err := canvasColor(tt.spec)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("canvasColor(%q) = %v, %v; want %v, error %t", tt.spec, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestDocumentTitle(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Hello", "Hello"},
+		{"Hello\nWorld", "Hello World"},
+		{stdinArg, "ASCII art"},
+	}
+	for _, tt := range tests {
+		if got := documentTitle(tt.text); got != tt.want {
+			t.Errorf("documentTitle(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestOutputColorMode(t *testing.T) {
+	t.Setenv("FORCE_COLOR", "")
+	t.Setenv("NO_COLOR", "")
+
+	if got := outputColorMode(cliOptions{format: textFormat, colorMode: "16"}, nil); got != color.Color16 {
+		t.Errorf("explicit mode = %v, want Color16", got)
+	}
+	if got := outputColorMode(cliOptions{format: textFormat}, nil); got != color.NoColor {
+		t.Errorf("auto mode without a terminal = %v, want NoColor", got)
+	}
+
+	t.Setenv("FORCE_COLOR", "2")
+	if got := outputColorMode(cliOptions{format: textFormat}, nil); got != color.Color256 {
+		t.Errorf("auto mode with FORCE_COLOR=2 = %v, want Color256", got)
+	}
+	if got := outputColorMode(cliOptions{format: textFormat, output: "banner.txt"}, nil); got != color.NoColor {
+		t.Errorf("auto mode to an --output file = %v, want NoColor", got)
+	}
+	if got := outputColorMode(cliOptions{format: textFormat, output: "banner.txt", colorMode: "256"}, nil); got != color.Color256 {
+		t.Errorf("explicit mode to an --output file = %v, want Color256", got)
+	}
+
+	if got := outputColorMode(cliOptions{format: htmlFormat, colorMode: "16"}, nil); got != color.TrueColor {
+		t.Errorf("html with 16 colors = %v, want TrueColor", got)
+	}
+	if got := outputColorMode(cliOptions{format: htmlFormat, colorMode: "none"}, nil); got != color.NoColor {
+		t.Errorf("html without colors = %v, want NoColor", got)
+	}
+}
+
+func TestColorRules(t *testing.T) {
+	red, navy := color.RGB{R: 255}, color.RGB{B: 128}
+	base := color.Style{Background: &navy, Attrs: color.Bold}
+
+	tests := []struct {
+		name      string
+		opts      cliOptions
+		wantRules int
+		wantStyle string
+		wantErr   bool
+	}{
+		{
+			name:      "color with background and attributes",
+			opts:      cliOptions{colors: []string{"red"}, background: "#000080", attrs: color.Bold},
+			wantRules: 1,
+			wantStyle: base.WithForeground(red).ANSI(),
+		},
+		{
+			name:      "background and attributes alone",
+			opts:      cliOptions{background: "#000080", attrs: color.Bold},
+			wantRules: 1,
+			wantStyle: base.ANSI(),
+		},
+		{
+			name:      "palette, gradient and color in precedence order",
+			opts:      cliOptions{colors: []string{"red:a"}, gradients: []string{"red,blue"}, palettes: []string{"nord"}},
+			wantRules: 3,
+			wantStyle: color.ANSI(red),
+		},
+		{
+			name:    "invalid background",
+			opts:    cliOptions{colors: []string{"red"}, background: "notacolor"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid palette",
+			opts:    cliOptions{palettes: []string{"vaporwave"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := colorRules(tt.opts, color.TrueColor)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("colorRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(rules) != tt.wantRules {
+				t.Fatalf("colorRules() returned %d rules, want %d", len(rules), tt.wantRules)
+			}
+			last := rules[len(rules)-1]
+			if got := last.Style.ANSI(); got != tt.wantStyle {
+				t.Errorf("last rule style = %q, want %q", got, tt.wantStyle)
+			}
+			for _, rule := range rules[:len(rules)-1] {
+				if rule.Palette == nil && rule.Shader == nil {
+					t.Errorf("expected the palette and gradient rules first, got %+v", rule)
+				}
+			}
+		})
+	}
+}
+
+func TestParseGradientRule(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         string
+		wantStops     int
+		wantSubstring string
+		wantHas       bool
+		wantErr       bool
+	}{
+		{"colors only", "red,blue", 2, "", false, false},
+		{"with text", "red,green,blue:ERROR", 3, "ERROR", true, false},
+		{"text with colon", "red,blue:a:b", 2, "a:b", true, false},
+		{"rgb stops", "rgb(255,0,0),rgb(0,0,255):x", 2, "x", true, false},
+		{"single color", "red", 0, "", false, true},
+		{"single color with text", "red:ERROR", 0, "", false, true},
+		{"empty text", "red,blue:", 0, "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stops, substring, has, err := parseGradientRule(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseGradientRule(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if len(stops) != tt.wantStops || substring != tt.wantSubstring || has != tt.wantHas {
+				t.Errorf("parseGradientRule(%q) = %d stops, %q, %t; want %d stops, %q, %t",
+					tt.value, len(stops), substring, has, tt.wantStops, tt.wantSubstring, tt.wantHas)
+			}
+		})
+	}
+}
+
+func TestGradientShader(t *testing.T) {
+	g := color.Gradient{Stops: []color.RGB{{R: 255}, {B: 255}}}
+	red, blue := color.ANSI(color.RGB{R: 255}), color.ANSI(color.RGB{B: 255})
+
+	horizontal := gradientShader(g, false, color.Style{})
+	if got := horizontal(7, 0, 8, 11).ANSI(); got != red {
+		t.Errorf("first column = %q, want %q", got, red)
+	}
+	if got := horizontal(0, 10, 8, 11).ANSI(); got != blue {
+		t.Errorf("last column = %q, want %q", got, blue)
+	}
+	if got := horizontal(0, 0, 8, 1).ANSI(); got != red {
+		t.Errorf("single column = %q, want %q", got, red)
+	}
+
+	vertical := gradientShader(g, true, color.Style{})
+	if got := vertical(0, 10, 8, 11).ANSI(); got != red {
+		t.Errorf("first row = %q, want %q", got, red)
+	}
+	if got := vertical(7, 0, 8, 11).ANSI(); got != blue {
+		t.Errorf("last row = %q, want %q", got, blue)
+	}
+
+	bold := gradientShader(g, false, color.Style{Attrs: color.Bold})
+	if got, want := bold(0, 0, 1, 2).ANSI(), "\033[1;38;2;255;0;0m"; got != want {
+		t.Errorf("styled first column = %q, want %q", got, want)
+	}
+}
+
+func TestNewColorRule(t *testing.T) {
+	red := "\033[31m"
+	tests := []struct {
+		name      string
+		substring string
+		match     coloring.MatchOptions
+		text      string
+		want      string
+	}{
+		{"exact", "o", coloring.MatchOptions{}, "foO", ".^."},
+		{"whole text ignores match options", "", coloring.MatchOptions{Mode: coloring.MatchWord}, "ab", "^^"},
+		{"ignore case", "o", coloring.MatchOptions{IgnoreCase: true}, "foO", ".^^"},
+		{"regex", `\d+`, coloring.MatchOptions{Mode: coloring.MatchRegexp}, "v1.22", ".^.^^"},
+		{"nth word", "go", coloring.MatchOptions{Mode: coloring.MatchWord, Occurrence: 2}, "go gone go", "........^^"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := newColorRule(tt.substring, coloring.Code(red), tt.match)
+			if err != nil {
+				t.Fatalf("newColorRule(%q) error: %v", tt.substring, err)
+			}
+			var got strings.Builder
+			for _, code := range coloring.Codes(tt.text, []coloring.Rule{rule}) {
+				if code == red {
+					got.WriteByte('^')
+				} else {
+					got.WriteByte('.')
+				}
+			}
+			if got.String() != tt.want {
+				t.Errorf("colored %s, want %s", got.String(), tt.want)
+			}
+		})
+	}
+
+	if _, err := newColorRule("(", coloring.Code(red), coloring.MatchOptions{Mode: coloring.MatchRegexp}); err == nil {
+		t.Error("expected error for invalid regular expression")
+	}
+}
+
+func TestParseCommandLine_Input(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		piped   bool
+		wantSub string
+		wantBnr string
+	}{
+		{"input file", []string{"--input=notes.txt"}, false, "", "standard"},
+		{"input file and banner", []string{"--input=notes.txt", "shadow"}, false, "", "shadow"},
+		{"color input file", []string{"--color=red", "--input=notes.txt"}, false, "", "standard"},
+		{"color input file and banner", []string{"--color=red", "shadow", "--input=notes.txt"}, false, "", "shadow"},
+		{"color input file and substring", []string{"--color=red", "ERR", "--input=notes.txt"}, false, "ERR", "standard"},
+		{"color input file, substring and banner", []string{"--color=red", "ERR", "shadow", "-i", "notes.txt"}, false, "ERR", "shadow"},
+		{"piped without text", nil, true, "", "standard"},
+		{"piped with color and no text", []string{"--color=red"}, true, "", "standard"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := parseCommandLine(append([]string{"./ascii-art"}, tt.args...), tt.piped)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if opts.text != stdinArg {
+				t.Errorf("text = %q, want %q", opts.text, stdinArg)
+			}
+			if opts.substring != tt.wantSub {
+				t.Errorf("substring = %q, want %q", opts.substring, tt.wantSub)
+			}
+			if opts.banner != tt.wantBnr {
+				t.Errorf("banner = %q, want %q", opts.banner, tt.wantBnr)
+			}
+		})
+	}
+
+	opts, err := parseCommandLine([]string{"./ascii-art", "hello"}, true)
+	if err != nil || opts.text != "hello" {
+		t.Errorf("text argument with piped input: got %q, %v; want %q", opts.text, err, "hello")
+	}
+}
+
+func TestParseCommandLine_Reverse(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		wantBanner    string
+		wantBannerSet bool
+	}{
+		{"file only", []string{"--reverse=art.txt"}, "standard", false},
+		{"banner argument", []string{"--reverse=art.txt", "shadow"}, "shadow", true},
+		{"banner option", []string{"-b", "thinkertoy", "--reverse=art.txt", "--output=text.txt"}, "thinkertoy", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := mustParse(t, tt.args...)
+			if opts.reverse != "art.txt" || opts.text != "" {
+				t.Errorf("reverse, text = %q, %q; want %q, \"\"", opts.reverse, opts.text, "art.txt")
+			}
+			if opts.banner != tt.wantBanner || opts.bannerSet != tt.wantBannerSet {
+				t.Errorf("banner, bannerSet = %q, %t; want %q, %t", opts.banner, opts.bannerSet, tt.wantBanner, tt.wantBannerSet)
+			}
+		})
+	}
+}
+
+func TestParseCommandLine_Help(t *testing.T) {
+	for _, args := range [][]string{{"--help"}, {"-h"}, {"hello", "--help", "--width=bad"}} {
+		opts, err := parseCommandLine(append([]string{"./ascii-art"}, args...), false)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", args, err)
+		}
+		if !opts.help {
+			t.Errorf("%q: help not set", args)
+		}
+	}
+
+	opts, err := parseCommandLine([]string{"./ascii-art", "--list-banners"}, false)
+	if err != nil || !opts.listBanners {
+		t.Errorf("--list-banners: listBanners = %t, %v; want true", opts.listBanners, err)
+	}
+}
+
+func TestHelpText(t *testing.T) {
+	help := helpText()
+	for _, flag := range cliFlags {
+		if !strings.Contains(help, "--"+flag.Name) {
+			t.Errorf("help text does not list --%s", flag.Name)
+		}
+	}
+	for _, want := range []string{"-w, --width=COLUMNS", "--fallback=CHAR", "ascii-art -5", "--width=40, not --width 40", "path of a banner\nfile", "Exit status"} {
+		if !strings.Contains(help, want) {
+			t.Errorf("help text does not contain %q:\n%s", want, help)
+		}
+	}
+}
+
+func TestParseColorRule(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantSub string
+		wantHas bool
+		wantErr bool
+	}{
+		{"red", "", false, false},
+		{"red:ERROR", "ERROR", true, false},
+		{"#00ff00:OK", "OK", true, false},
+		{"rgb(0,0,255):a:b", "a:b", true, false},
+		{"red:12:30", "12:30", true, false},
+		{"red:", "", false, true},
+		{"nocolor:ERROR", "", false, true},
+		{"nocolor", "", false, true},
+		{"ansi:208:OK", "OK", true, false},
+		{"hsl(30,100%,50%):a", "a", true, false},
+		{"#f80:b", "b", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			_, sub, has, err := parseColorRule(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseColorRule(%q) error = %v, wantErr %t", tt.value, err, tt.wantErr)
+			}
+			if sub != tt.wantSub || has != tt.wantHas {
+				t.Errorf("parseColorRule(%q) = %q, %t; want %q, %t", tt.value, sub, has, tt.wantSub, tt.wantHas)
+			}
+		})
+	}
+}
+
+func TestParseColorRule_Suggestion(t *testing.T) {
+	_, _, _, err := parseColorRule("ornage:ERROR")
+	if err == nil || !strings.Contains(err.Error(), "did you mean 'orange'?") {
+		t.Errorf("parseColorRule(\"ornage:ERROR\") error = %v, want a suggestion of orange", err)
+	}
+}
+
+func TestGetBannerPath_ValidBanners(t *testing.T) {
+	testCases := []struct {
+		banner       string
+		expectedPath string
+	}{
+		{"standard", "standard.txt"},
+		{"shadow", "shadow.txt"},
+		{"thinkertoy", "thinkertoy.txt"},
+	}
+	t.Setenv(fontPathEnv, "")
+
+	for _, tc := range testCases {
+		path, err := GetBannerPath(tc.banner)
+
+		if err != nil {
+			t.Errorf("Banner %q: expected no error, got: %v", tc.banner, err)
+		}
+
+		if path != tc.expectedPath {
+			t.Errorf("Banner %q: expected path %q, got: %q",
+				tc.banner, tc.expectedPath, path)
+		}
+	}
+}
+
+func TestGetBannerPath_InvalidBanner(t *testing.T) {
+	t.Setenv(fontPathEnv, "")
+	for _, banner := range []string{"invalid", "corrupted", "../testdata/standard", "standard.txt", ""} {
+		if _, err := GetBannerPath(banner); err == nil {
+			t.Errorf("GetBannerPath(%q): expected an error, got nil", banner)
+		}
+	}
+}
+
+// writeBannerDir writes banner files named after the keys of files, with the
+// given contents, to a new directory.
+func writeBannerDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestBannerSearchPath(t *testing.T) {
+	first := writeBannerDir(t, map[string]string{"corp.txt": "first", "shadow.txt": "my shadow", "notes.md": "not a banner"})
+	second := writeBannerDir(t, map[string]string{"corp.txt": "second", "zine.txt": "zine"})
+	t.Setenv(fontPathEnv, strings.Join([]string{first, "", filepath.Join(first, "missing"), second}, string(filepath.ListSeparator)))
+
+	if path, err := GetBannerPath("corp"); err != nil || path != "corp.txt" {
+		t.Errorf("GetBannerPath(corp) = %q, %v; want %q", path, err, "corp.txt")
+	}
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"corp.txt", "first"},
+		{"zine.txt", "zine"},
+		{"shadow.txt", "my shadow"},
+	}
+	for _, tt := range tests {
+		data, err := fs.ReadFile(GetBannerFS(), tt.path)
+		if err != nil || string(data) != tt.want {
+			t.Errorf("ReadFile(%s) = %q, %v; want %q", tt.path, data, err, tt.want)
+		}
+	}
+	if data, err := fs.ReadFile(GetBannerFS(), "standard.txt"); err != nil || len(data) == 0 {
+		t.Errorf("ReadFile(standard.txt) = %d bytes, %v; want the built-in banner", len(data), err)
+	}
+
+	got := findBanners()
+	want := []bannerEntry{
+		{"standard", builtinOrigin},
+		{"shadow", filepath.Join(first, "shadow.txt")},
+		{"thinkertoy", builtinOrigin},
+		{"corp", filepath.Join(first, "corp.txt")},
+		{"zine", filepath.Join(second, "zine.txt")},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("findBanners() = %v, want %v", got, want)
+	}
+
+	var b strings.Builder
+	if err := writeBannerList(&b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(b.String(), "standard    built in\nshadow      "+first) {
+		t.Errorf("writeBannerList() wrote:\n%s", b.String())
+	}
+
+	for _, name := range []string{"corp", "zine", "standard", filepath.Join(first, "corp.txt")} {
+		if !isValidBanner(name) {
+			t.Errorf("isValidBanner(%q) = false, want true", name)
+		}
+	}
+	if isValidBanner("notes") || isValidBanner("missing.txt") {
+		t.Error("isValidBanner() accepted a banner that does not exist")
+	}
+}
+
+func TestFontSearchPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	sep := string(filepath.Separator)
+	t.Setenv(fontPathEnv, "~"+sep+"fonts"+string(filepath.ListSeparator)+sep+"opt"+sep+"fonts")
+	want := []string{filepath.Join(home, "fonts"), sep + "opt" + sep + "fonts"}
+	if got := fontSearchPath(); !slices.Equal(got, want) {
+		t.Errorf("fontSearchPath() = %q, want %q", got, want)
+	}
+}
+
+func TestIsBannerFile(t *testing.T) {
+	tests := map[string]bool{
+		"standard":           false,
+		"corp":               false,
+		"corp.txt":           true,
+		"./fonts/corp.txt":   true,
+		"fonts/corp":         true,
+		"/usr/share/art/big": true,
+	}
+	for banner, want := range tests {
+		if got := isBannerFile(banner); got != want {
+			t.Errorf("isBannerFile(%q) = %t, want %t", banner, got, want)
+		}
+	}
+}
+
+func TestOutputWidth_ExplicitWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		opts cliOptions
+		want int
+	}{
+		{"explicit width", cliOptions{width: 72, widthSet: true}, 72},
+		{"wrapping disabled", cliOptions{width: 0, widthSet: true}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := outputWidth(tt.opts, os.Stdout); got != tt.want {
+				t.Errorf("outputWidth() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"empty", "", nil},
+		{"single line", "hello\n", []string{"hello"}},
+		{"no final newline", "a\nb", []string{"a", "b"}},
+		{"empty lines", "a\n\nb\n", []string{"a", "", "b"}},
+		{"CRLF", "a\r\nb\r\n", []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			err := readLines(strings.NewReader(tt.input), func(line string) {
+				got = append(got, line)
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(got, "/") != strings.Join(tt.want, "/") || len(got) != len(tt.want) {
+				t.Errorf("readLines(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	err := readLines(iotest.ErrReader(errors.New("broken pipe")), func(string) {})
+	if err == nil {
+		t.Error("expected read error, got nil")
+	}
+}
+
+func TestOutputFile_Publish(t *testing.T) {
+	tests := []struct {
+		name    string
+		force   bool
+		created bool // whether the target appears after openOutput
+		want    string
+		wantErr bool
+	}{
+		{"new target", false, false, "new", false},
+		{"target created meanwhile", false, true, "old", true},
+		{"target replaced with force", true, true, "new", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			target := filepath.Join(dir, "art.txt")
+			out := openOutput(cliOptions{output: target, force: tt.force})
+			t.Cleanup(func() { pending = nil })
+			if _, err := out.WriteString("new"); err != nil {
+				t.Fatal(err)
+			}
+			if tt.created {
+				if err := os.WriteFile(target, []byte("old"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := out.publish()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("publish() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "already exists") {
+				t.Errorf("publish() error = %v, want an already exists error", err)
+			}
+			out.discard()
+			if data, err := os.ReadFile(target); err != nil || string(data) != tt.want {
+				t.Errorf("target holds %q, %v; want %q", data, err, tt.want)
+			}
+			if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+				t.Errorf("directory holds %d files, want only the target", len(entries))
+			}
+		})
+	}
+}
+
+func TestCopyExclusive(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	if err := os.WriteFile(src, []byte("new"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(dir, "dst")
+	if err := copyExclusive(src, dst); err != nil {
+		t.Fatalf("copyExclusive() error = %v", err)
+	}
+	info, err := os.Stat(dst)
+	if data, _ := os.ReadFile(dst); err != nil || string(data) != "new" || info.Mode().Perm() != 0o600 {
+		t.Errorf("copy holds %q, %v; want %q with mode 0600", data, err, "new")
+	}
+
+	existing := filepath.Join(dir, "existing")
+	if err := os.WriteFile(existing, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := copyExclusive(src, existing); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("copyExclusive() onto an existing file error = %v, want fs.ErrExist", err)
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "old" {
+		t.Errorf("existing file holds %q, want it untouched", data)
 	}
 }
@@ -0,0 +1,722 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/vxanthio/ascii-art-color/internal/animate"
+	"github.com/vxanthio/ascii-art-color/internal/color"
+	"github.com/vxanthio/ascii-art-color/internal/coloring"
+	"github.com/vxanthio/ascii-art-color/internal/export"
+	"github.com/vxanthio/ascii-art-color/internal/flagparser"
+	"github.com/vxanthio/ascii-art-color/internal/renderer"
+	"github.com/vxanthio/ascii-art-color/internal/terminal"
+)
+
+// Long names of the command-line options.
+const (
+	bannerOption      = "banner"
+	fontOption        = "font"
+	colorOption       = "color"
+	substringOption   = "substring"
+	gradientOption    = "gradient"
+	directionOption   = "gradient-direction"
+	spaceOption       = "gradient-space"
+	rainbowOption     = "rainbow"
+	paletteOption     = "palette"
+	cycleOption       = "cycle"
+	bgOption          = "bg"
+	attrOption        = "attr"
+	colorModeOption   = "color-mode"
+	colorNamesOption  = "color-names"
+	matchOption       = "match"
+	ignoreCaseOption  = "ignore-case"
+	occurrenceOption  = "occurrence"
+	layoutOption      = "layout"
+	fallbackOption    = "fallback"
+	widthOption       = "width"
+	alignOption       = "align"
+	inputOption       = "input"
+	outputOption      = "output"
+	forceOption       = "force"
+	formatOption      = "format"
+	htmlDocOption     = "html-document"
+	htmlClassOption   = "html-classes"
+	svgCellsOption    = "svg-cells"
+	fontSizeOption    = "font-size"
+	canvasOption      = "canvas"
+	scaleOption       = "scale"
+	paddingOption     = "padding"
+	effectOption      = "effect"
+	delayOption       = "delay"
+	loopOption        = "loop"
+	canvasSizeOption  = "canvas-size"
+	animateOption     = "animate"
+	fpsOption         = "fps"
+	reverseOption     = "reverse"
+	listBannersOption = "list-banners"
+	helpOption        = "help"
+)
+
+// cliFlags declares every command-line option, in the order --help lists them.
+var cliFlags = []flagparser.Option{
+	{Name: bannerOption, Short: 'b', Value: "NAME", Usage: "Banner style: standard, shadow, thinkertoy, a banner of ASCII_ART_FONT_PATH, or a .txt file (default standard)"},
+	{Name: fontOption, Short: 'f', Value: "FILE", Usage: "FIGlet (.flf) font file to use instead of a banner"},
+	{Name: colorOption, Short: 'c', Value: "COLOR[:TEXT]", Usage: "Color the text, or only TEXT: a name, #rrggbb, rgb(), hsl(), hsv(), or ansi:N; repeatable, later rules win"},
+	{Name: gradientOption, Short: 'g', Value: "COLORS[:TEXT]", Usage: "Color the text, or only TEXT, with a gradient of comma-separated colors; repeatable"},
+	{Name: directionOption, Value: "DIR", Usage: "Gradient direction: horizontal or vertical (default horizontal)"},
+	{Name: spaceOption, Value: "SPACE", Usage: "Gradient blending: rgb, hsl, or oklab (default rgb)"},
+	{Name: rainbowOption, Short: 'r', Usage: "Color each glyph with the next color of the rainbow; same as --palette=rainbow"},
+	{Name: paletteOption, Short: 'p', Value: "NAME[:TEXT]", Usage: "Cycle the text, or only TEXT, through a palette: rainbow, pride, pastel, solarized, or nord; repeatable"},
+	{Name: cycleOption, Value: "UNIT", Usage: "What takes the next palette color: glyph, word, or row (default glyph)"},
+	{Name: bgOption, Value: "COLOR", Usage: "Background color of the colored text, or of the whole text on its own"},
+	{Name: attrOption, Value: "LIST", Usage: "Text attributes: comma-separated bold, dim, italic, underline, blink, reverse"},
+	{Name: colorModeOption, Value: "MODE", Usage: "Color depth: auto, truecolor, 256, 16, or none (default auto)"},
+	{Name: colorNamesOption, Value: "FILE", Usage: "Load extra color names from FILE, in the format of X11 rgb.txt"},
+	{Name: substringOption, Short: 's', Value: "TEXT", Usage: "Color only the occurrences of TEXT with the colors given without :TEXT"},
+	{Name: matchOption, Short: 'm', Value: "MODE", Usage: "How color TEXT is matched: exact, word, or regex (default exact)"},
+	{Name: ignoreCaseOption, Short: 'I', Usage: "Match color TEXT without regard to case"},
+	{Name: occurrenceOption, Short: 'n', Value: "N", Usage: "Color only the N-th match of each color TEXT"},
+	{Name: layoutOption, Short: 'l', Value: "MODE", Usage: "Glyph layout: full, fitting, smushing, or universal"},
+	{Name: fallbackOption, Value: "CHAR", Usage: "Banner character drawn for characters the banner lacks"},
+	{Name: widthOption, Short: 'w', Value: "COLUMNS", Usage: "Wrap output at COLUMNS; 0 disables wrapping (default: terminal width)"},
+	{Name: alignOption, Short: 'a', Value: "MODE", Usage: "Block alignment: left, center, right, or justify (default left)"},
+	{Name: inputOption, Short: 'i', Value: "FILE", Usage: "Read the text from FILE, one block per line"},
+	{Name: outputOption, Short: 'o', Value: "PATH", Usage: "Write the output to PATH instead of standard output; a .txt, .html, .svg, .png or .gif extension sets the format"},
+	{Name: forceOption, Usage: "With --output, overwrite PATH if it exists"},
+	{Name: formatOption, Value: "FORMAT", Usage: "Output format: text, html, svg, png, or gif (default text)"},
+	{Name: htmlDocOption, Usage: "With --format=html, write a complete HTML document instead of a fragment"},
+	{Name: htmlClassOption, Usage: "With --format=html, style with CSS classes instead of inline styles"},
+	{Name: svgCellsOption, Usage: "With --format=svg, place every character cell on its own instead of writing whole rows"},
+	{Name: fontSizeOption, Value: "PX", Usage: "With --format=svg, font size in pixels (default 14)"},
+	{Name: canvasOption, Value: "COLOR", Usage: "With --format=svg, png or gif, background color of the image (default transparent)"},
+	{Name: scaleOption, Value: "N", Usage: "With --format=png or gif, size of a font pixel in image pixels, 1 to 32 (default 2)"},
+	{Name: paddingOption, Value: "PX", Usage: "With --format=png or gif, margin around the art in pixels (default 8)"},
+	{Name: effectOption, Value: "NAME", Usage: "With --format=gif, animation: typewriter, marquee, rainbow, or blink (default typewriter)"},
+	{Name: delayOption, Value: "MS", Usage: "With --format=gif, time each frame shows in milliseconds, 10 to 60000 (default 100)"},
+	{Name: loopOption, Value: "N", Usage: "With --format=gif, number of times the animation plays; 0 repeats forever (default 0)"},
+	{Name: canvasSizeOption, Value: "WxH", Usage: "With --format=gif, size of the image in pixels (default sized to the art)"},
+	{Name: animateOption, Value: "NAME", Usage: "Animate the banner in the terminal: typewriter, marquee, or wave; Ctrl-C stops it"},
+	{Name: fpsOption, Value: "N", Usage: "With --animate, frames per second, 1 to 60 (default 10)"},
+	{Name: reverseOption, Value: "FILE", Usage: "Recognize the art in FILE, or - for standard input, and print its text"},
+	{Name: listBannersOption, Usage: "List the built-in banners and those of ASCII_ART_FONT_PATH, and exit"},
+	{Name: helpOption, Short: 'h', Usage: "Show this help and exit"},
+}
+
+// cliOptions holds everything given on the command line, from options and
+// positional arguments alike.
+type cliOptions struct {
+	// text is the text to render, with \n escapes interpreted, or stdinArg
+	// when the text is streamed from standard input or the --input file.
+	text string
+	// banner names the banner style.
+	banner string
+	// bannerSet reports whether the banner was given, by --banner or as an
+	// argument.
+	bannerSet bool
+	// colors holds the --color values, COLOR or COLOR:TEXT, in command-line
+	// order; none disables color mode.
+	colors []string
+	// gradients holds the --gradient values, COLORS or COLORS:TEXT, in
+	// command-line order.
+	gradients []string
+	// vertical reports whether gradients run top to bottom instead of left
+	// to right.
+	vertical bool
+	// space is the color space gradients blend in.
+	space color.Space
+	// palettes holds the --palette values, NAME or NAME:TEXT, in
+	// command-line order, preceded by "rainbow" for --rainbow.
+	palettes []string
+	// cycle selects what advances palettes to their next color.
+	cycle coloring.Cycle
+	// background is the --bg color specification; empty for none.
+	background string
+	// attrs holds the --attr text attributes.
+	attrs color.Attr
+	// colorMode names the --color-mode color depth; empty means auto.
+	colorMode string
+	// substring is the text colored by the colors and gradients given
+	// without :TEXT; empty colors the whole text.
+	substring string
+	// match controls how the text of each color rule is matched: the
+	// --match mode, --ignore-case and --occurrence.
+	match coloring.MatchOptions
+	// font is the path to a FIGlet (.flf) font file that replaces the banner.
+	font string
+	// layout is the --layout glyph layout; only meaningful when layoutSet is
+	// true, since banners and fonts otherwise use their own.
+	layout renderer.Layout
+	// layoutSet reports whether --layout was given.
+	layoutSet bool
+	// fallback is the banner character drawn for characters the banner does
+	// not define; zero when unset.
+	fallback rune
+	// width is the column limit to wrap output at; only meaningful when
+	// widthSet is true. Zero disables wrapping.
+	width int
+	// widthSet reports whether --width was given.
+	widthSet bool
+	// align is the block alignment.
+	align renderer.Align
+	// format is the output format: text, html, svg, png, or gif.
+	format string
+	// htmlDocument reports whether HTML output is a complete document.
+	htmlDocument bool
+	// htmlClasses reports whether HTML output styles with CSS classes.
+	htmlClasses bool
+	// svgCells reports whether SVG output places every cell on its own.
+	svgCells bool
+	// fontSize is the font size of SVG output in pixels; zero for the
+	// default.
+	fontSize float64
+	// canvas is the --canvas color specification of image backgrounds;
+	// empty for a transparent background.
+	canvas string
+	// scale is the size of a font pixel of PNG and GIF output in image
+	// pixels; zero for the default.
+	scale int
+	// padding is the margin around PNG and GIF output in pixels.
+	padding int
+	// effect is the animation of GIF output.
+	effect export.Effect
+	// delay is the time each frame of GIF output shows; zero for the
+	// default.
+	delay time.Duration
+	// loops is the number of times GIF output plays; zero repeats it
+	// forever.
+	loops int
+	// canvasWidth and canvasHeight are the --canvas-size of GIF output in
+	// pixels; zero sizes the image to the art.
+	canvasWidth, canvasHeight int
+	// animated reports whether --animate was given.
+	animated bool
+	// animation is the --animate terminal animation.
+	animation animate.Effect
+	// fps is the frame rate of terminal animations; zero for the default.
+	fps int
+	// reverse is the path of the --reverse file of art to recognize, or
+	// stdinArg for standard input; empty when not given.
+	reverse string
+	// input is the path of a file to read the text from, in place of the
+	// text argument.
+	input string
+	// output is the path of a file to write to in place of standard output.
+	output string
+	// force reports whether --output may overwrite an existing file.
+	force bool
+	// listBanners reports whether --list-banners was given.
+	listBanners bool
+	// help reports whether --help was given.
+	help bool
+}
+
+// parseCommandLine parses the options and positional arguments of the
+// command line.
+//
+// Options may appear anywhere; see the flagparser package for the syntax. The
+// positional arguments keep their original forms, text [banner] and, in color
+// mode, [substring] text [banner] (see assignPositionals).
+//
+// Parameters:
+//   - args: Command-line arguments slice (args[0] is program name).
+//   - stdinPiped: Whether standard input is redirected, in which case a
+//     missing text argument reads the text from it.
+//
+// Returns:
+//   - The parsed command line.
+//   - An error, including the usage text, if the command line is invalid.
+func parseCommandLine(args []string, stdinPiped bool) (cliOptions, error) {
+	if len(args) == 0 {
+		return cliOptions{}, errTextUsage
+	}
+
+	result, err := flagparser.Parse(cliFlags, args[1:])
+	if err != nil {
+		return cliOptions{}, fmt.Errorf("%w\n\n%w", err, errColorUsage)
+	}
+
+	opts := cliOptions{banner: defaultBanner, format: textFormat, padding: defaultPadding, help: result.IsSet(helpOption)}
+	opts.listBanners = result.IsSet(listBannersOption)
+	if opts.help || opts.listBanners {
+		return opts, nil
+	}
+	if err := applyFlags(&opts, result); err != nil {
+		return cliOptions{}, err
+	}
+	if err := assignPositionals(&opts, result, stdinPiped); err != nil {
+		return cliOptions{}, err
+	}
+	return opts, nil
+}
+
+// applyFlags copies the parsed option values into opts, validating the ones
+// whose values are checked independently of the rest of the command line.
+//
+// The options are applied one feature at a time, each by its own helper; the
+// later helpers may rely on the options the earlier ones set.
+//
+// Parameters:
+//   - opts: The options to fill in.
+//   - result: The parsed command line.
+//
+// Returns:
+//   - The first error of applyInputFlags, applyOutputFlags,
+//     applyLayoutFlags, applyColorFlags, applyExportFlags and
+//     applyAnimationFlags, in that order.
+func applyFlags(opts *cliOptions, result *flagparser.Result) error {
+	for _, apply := range []func(*cliOptions, *flagparser.Result) error{
+		applyInputFlags,
+		applyOutputFlags,
+		applyLayoutFlags,
+		applyColorFlags,
+		applyExportFlags,
+		applyAnimationFlags,
+	} {
+		if err := apply(opts, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyInputFlags applies the options that select what is read: --reverse,
+// --banner, --font and --input.
+//
+// Parameters:
+//   - opts: The options to fill in.
+//   - result: The parsed command line.
+//
+// Returns:
+//   - An error if --reverse is combined with an option other than --banner,
+//     --font, --output and --force.
+func applyInputFlags(opts *cliOptions, result *flagparser.Result) error {
+	if value, ok := result.Value(reverseOption); ok {
+		for _, flag := range cliFlags {
+			if result.IsSet(flag.Name) && !slices.Contains(reverseOptions, flag.Name) {
+				return fmt.Errorf("--%s cannot be used with --%s", flag.Name, reverseOption)
+			}
+		}
+		opts.reverse = value
+	}
+
+	opts.banner = valueOr(result, bannerOption, opts.banner)
+	opts.bannerSet = result.IsSet(bannerOption)
+	opts.font, _ = result.Value(fontOption)
+	opts.input, _ = result.Value(inputOption)
+	return nil
+}
+
+// applyOutputFlags applies --output and --force.
+//
+// Parameters:
+//   - opts: The options to fill in.
+//   - result: The parsed command line.
+//
+// Returns:
+//   - An error if --force is given without --output.
+func applyOutputFlags(opts *cliOptions, result *flagparser.Result) error {
+	opts.output, _ = result.Value(outputOption)
+	opts.force = result.IsSet(forceOption)
+	if opts.force && opts.output == "" {
+		return fmt.Errorf("--%s requires --%s", forceOption, outputOption)
+	}
+	return nil
+}
+
+// applyLayoutFlags applies the options that lay the glyphs out: --fallback,
+// --layout, --align and --width.
+//
+// Parameters:
+//   - opts: The options to fill in.
+//   - result: The parsed command line.
+//
+// Returns:
+//   - An error if --fallback is not a single character, if --layout or
+//     --align names no known value, or if --width is not a non-negative
+//     integer.
+func applyLayoutFlags(opts *cliOptions, result *flagparser.Result) error {
+	if value, ok := result.Value(fallbackOption); ok {
+		if utf8.RuneCountInString(value) != 1 {
+			return fmt.Errorf("invalid value for --%s=<char>: %q must be a single character", fallbackOption, value)
+		}
+		opts.fallback, _ = utf8.DecodeRuneInString(value)
+	}
+
+	if value, ok := result.Value(layoutOption); ok {
+		layout, err := renderer.ParseLayout(value)
+		if err != nil {
+			return err
+		}
+		opts.layout, opts.layoutSet = layout, true
+	}
+
+	if value, ok := result.Value(alignOption); ok {
+		align, err := renderer.ParseAlign(value)
+		if err != nil {
+			return err
+		}
+		opts.align = align
+	}
+
+	if value, ok := result.Value(widthOption); ok {
+		width, err := strconv.Atoi(value)
+		if err != nil || width < 0 {
+			return fmt.Errorf("invalid value for --%s=<columns>: %q must be a non-negative integer", widthOption, value)
+		}
+		opts.width, opts.widthSet = width, true
+	}
+	return nil
+}
+
+// applyColorFlags applies the coloring options: the coloring rules, their
+// styles, the gradient and palette settings, the matching options and
+// --color-mode.
+//
+// Parameters:
+//   - opts: The options to fill in.
+//   - result: The parsed command line.
+//
+// Returns:
+//   - An error if --gradient-direction, --gradient-space, --cycle, --attr,
+//     --color-mode or --match names no known value, if --occurrence is not
+//     a positive integer, or if --substring, a matching option, a gradient
+//     option or --cycle is given without a coloring option. The error wraps
+//     errColorNames if the --color-names file cannot be loaded.
+func applyColorFlags(opts *cliOptions, result *flagparser.Result) error {
+	if path, ok := result.Value(colorNamesOption); ok {
+		if err := loadColorNames(path); err != nil {
+			return err
+		}
+	}
+
+	opts.colors = result.Values(colorOption)
+	opts.gradients = result.Values(gradientOption)
+	if result.IsSet(rainbowOption) {
+		opts.palettes = append(opts.palettes, rainbowPalette)
+	}
+	opts.palettes = append(opts.palettes, result.Values(paletteOption)...)
+	opts.substring, _ = result.Value(substringOption)
+	opts.background, _ = result.Value(bgOption)
+
+	if value, ok := result.Value(directionOption); ok {
+		switch strings.ToLower(value) {
+		case "horizontal":
+			opts.vertical = false
+		case "vertical":
+			opts.vertical = true
+		default:
+			return fmt.Errorf("invalid gradient direction: %q\nValid options: horizontal, vertical", value)
+		}
+	}
+
+	if value, ok := result.Value(spaceOption); ok {
+		space, err := color.ParseSpace(value)
+		if err != nil {
+			return err
+		}
+		opts.space = space
+	}
+
+	if value, ok := result.Value(colorModeOption); ok && !strings.EqualFold(value, autoColorMode) {
+		if _, err := color.ParseMode(value); err != nil {
+			return err
+		}
+		opts.colorMode = value
+	}
+
+	if value, ok := result.Value(attrOption); ok {
+		attrs, err := color.ParseAttrs(value)
+		if err != nil {
+			return err
+		}
+		opts.attrs = attrs
+	}
+
+	if value, ok := result.Value(cycleOption); ok {
+		cycle, err := coloring.ParseCycle(value)
+		if err != nil {
+			return err
+		}
+		opts.cycle = cycle
+	}
+
+	if value, ok := result.Value(matchOption); ok {
+		mode, err := coloring.ParseMatchMode(value)
+		if err != nil {
+			return err
+		}
+		opts.match.Mode = mode
+	}
+	opts.match.IgnoreCase = result.IsSet(ignoreCaseOption)
+
+	if value, ok := result.Value(occurrenceOption); ok {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid value for --%s=<n>: %q must be a positive integer", occurrenceOption, value)
+		}
+		opts.match.Occurrence = n
+	}
+
+	if !opts.colored() {
+		for _, name := range []string{substringOption, matchOption, ignoreCaseOption, occurrenceOption, directionOption, spaceOption, cycleOption} {
+			if result.IsSet(name) {
+				return fmt.Errorf("--%s requires --%s\n\n%w", name, colorOption, errColorUsage)
+			}
+		}
+	}
+	return nil
+}
+
+// applyExportFlags applies --format and the options of the document and
+// image formats. Without --format, the format follows the extension of the
+// --output path.
+//
+// Parameters:
+//   - opts: The options to fill in; opts.output must already be set.
+//   - result: The parsed command line.
+//
+// Returns:
+//   - An error if --format or --effect names no known value, if --font-size
+//     is not a positive number, if --scale, --padding, --delay, --loop or
+//     --canvas-size is out of range, or if an option of some output formats
+//     is given with another format.
+func applyExportFlags(opts *cliOptions, result *flagparser.Result) error {
+	if value, ok := result.Value(formatOption); ok {
+		format, err := parseFormat(value)
+		if err != nil {
+			return err
+		}
+		opts.format = format
+	} else if opts.output != "" {
+		opts.format = formatForPath(opts.output)
+	}
+	opts.htmlDocument = result.IsSet(htmlDocOption)
+	opts.htmlClasses = result.IsSet(htmlClassOption)
+	opts.svgCells = result.IsSet(svgCellsOption)
+	opts.canvas, _ = result.Value(canvasOption)
+
+	if value, ok := result.Value(fontSizeOption); ok {
+		size, err := strconv.ParseFloat(value, 64)
+		if err != nil || !(size > 0 && size <= maxFontSize) {
+			return fmt.Errorf("invalid value for --%s=<px>: %q must be a number above 0 and at most %d", fontSizeOption, value, maxFontSize)
+		}
+		opts.fontSize = size
+	}
+	if value, ok := result.Value(scaleOption); ok {
+		scale, err := strconv.Atoi(value)
+		if err != nil || scale < 1 || scale > maxScale {
+			return fmt.Errorf("invalid value for --%s=<n>: %q must be an integer from 1 to %d", scaleOption, value, maxScale)
+		}
+		opts.scale = scale
+	}
+	if value, ok := result.Value(paddingOption); ok {
+		padding, err := strconv.Atoi(value)
+		if err != nil || padding < 0 || padding > maxPadding {
+			return fmt.Errorf("invalid value for --%s=<px>: %q must be an integer from 0 to %d", paddingOption, value, maxPadding)
+		}
+		opts.padding = padding
+	}
+	if value, ok := result.Value(effectOption); ok {
+		effect, err := export.ParseEffect(value)
+		if err != nil {
+			return err
+		}
+		opts.effect = effect
+	}
+	if value, ok := result.Value(delayOption); ok {
+		delay, err := strconv.Atoi(value)
+		if err != nil || delay < minDelay || delay > maxDelay {
+			return fmt.Errorf("invalid value for --%s=<ms>: %q must be an integer from %d to %d", delayOption, value, minDelay, maxDelay)
+		}
+		opts.delay = time.Duration(delay) * time.Millisecond
+	}
+	if value, ok := result.Value(loopOption); ok {
+		loops, err := strconv.Atoi(value)
+		if err != nil || loops < 0 || loops > maxLoops {
+			return fmt.Errorf("invalid value for --%s=<n>: %q must be an integer from 0 to %d", loopOption, value, maxLoops)
+		}
+		opts.loops = loops
+	}
+	if value, ok := result.Value(canvasSizeOption); ok {
+		width, height, err := parseCanvasSize(value)
+		if err != nil {
+			return err
+		}
+		opts.canvasWidth, opts.canvasHeight = width, height
+	}
+
+	for _, flag := range cliFlags {
+		if formats, ok := formatOptions[flag.Name]; ok && result.IsSet(flag.Name) && !slices.Contains(formats, opts.format) {
+			return fmt.Errorf("--%s requires %s", flag.Name, requiredFormats(flag.Name))
+		}
+	}
+	return nil
+}
+
+// applyAnimationFlags applies --animate and --fps, and checks that glyph by
+// glyph animations, of the terminal or of GIF output, are left aligned.
+//
+// Parameters:
+//   - opts: The options to fill in; the output, layout and export options
+//     must already be set.
+//   - result: The parsed command line.
+//
+// Returns:
+//   - An error if --animate names no known effect or is given with --output
+//     or a format other than text, if --fps is out of range or given without
+//     --animate, or if an animation that moves glyphs is combined with an
+//     alignment other than left.
+func applyAnimationFlags(opts *cliOptions, result *flagparser.Result) error {
+	if value, ok := result.Value(animateOption); ok {
+		animation, err := animate.ParseEffect(value)
+		if err != nil {
+			return err
+		}
+		if opts.output != "" {
+			return fmt.Errorf("--%s cannot be used with --%s", animateOption, outputOption)
+		}
+		if opts.format != textFormat {
+			return fmt.Errorf("--%s requires --%s=%s", animateOption, formatOption, textFormat)
+		}
+		opts.animated, opts.animation = true, animation
+	}
+	if value, ok := result.Value(fpsOption); ok {
+		fps, err := strconv.Atoi(value)
+		if err != nil || fps < 1 || fps > maxFPS {
+			return fmt.Errorf("invalid value for --%s=<n>: %q must be an integer from 1 to %d", fpsOption, value, maxFPS)
+		}
+		if !opts.animated {
+			return fmt.Errorf("--%s requires --%s", fpsOption, animateOption)
+		}
+		opts.fps = fps
+	}
+
+	if option := glyphOption(*opts); option != "" && opts.align != renderer.AlignLeft {
+		value, _ := result.Value(alignOption)
+		return fmt.Errorf("%s requires left alignment, not --%s=%s", option, alignOption, value)
+	}
+	return nil
+}
+
+// colored reports whether the command line asks for color, through --color,
+// --gradient, --palette, --rainbow, --bg or --attr.
+func (o cliOptions) colored() bool {
+	return o.foreground() || o.background != "" || o.attrs != 0
+}
+
+// foreground reports whether the command line has foreground coloring rules:
+// --color, --gradient, --palette or --rainbow.
+func (o cliOptions) foreground() bool {
+	return len(o.colors) > 0 || len(o.gradients) > 0 || len(o.palettes) > 0
+}
+
+// hasBareRule reports whether a coloring rule applies to the substring
+// argument: a --color, --gradient or --palette without :TEXT, or --bg and
+// --attr given on their own.
+func (o cliOptions) hasBareRule() bool {
+	if !o.foreground() {
+		return o.colored()
+	}
+	return hasBareColor(o.colors) || hasBareGradient(o.gradients) || hasBarePalette(o.palettes)
+}
+
+// glyphOption names the option that animates the text glyph by glyph, which
+// needs the glyphs at the columns the renderer gives them.
+//
+// Parameters:
+//   - opts: The parsed command-line options.
+//
+// Returns:
+//   - The option and its value, such as "--animate=wave"; empty when the
+//     output is not animated glyph by glyph.
+func glyphOption(opts cliOptions) string {
+	switch {
+	case opts.format == gifFormat && opts.effect == export.EffectTypewriter:
+		return fmt.Sprintf("--%s=%s", effectOption, opts.effect)
+	case opts.animated && opts.animation != animate.EffectMarquee:
+		return fmt.Sprintf("--%s=%s", animateOption, opts.animation)
+	}
+	return ""
+}
+
+// valueOr returns the last value of the named option, or fallback when the
+// option was not given.
+func valueOr(result *flagparser.Result, name, fallback string) string {
+	if value, ok := result.Value(name); ok {
+		return value
+	}
+	return fallback
+}
+
+// autoColorMode is the --color-mode value that detects the color depth.
+const autoColorMode = "auto"
+
+// outputColorMode returns the color depth to write styles for.
+//
+// Documents written with --format have 24-bit colors, or none with
+// --color-mode=none. For text, an explicit --color-mode wins; otherwise text
+// written to an --output file has no colors, and the depth is detected from
+// the environment and from whether the output goes to a terminal (see
+// color.Detect).
+//
+// Parameters:
+//   - opts: The parsed command-line options.
+//   - out: The file the output is written to.
+//
+// Returns:
+//   - The color mode.
+func outputColorMode(opts cliOptions, out *os.File) color.Mode {
+	if opts.colorMode != "" {
+		mode, _ := color.ParseMode(opts.colorMode)
+		if opts.format != textFormat && mode != color.NoColor {
+			return color.TrueColor
+		}
+		return mode
+	}
+	if opts.format != textFormat {
+		return color.TrueColor
+	}
+	if opts.output != "" {
+		return color.NoColor
+	}
+	return color.Detect(os.Getenv, terminal.IsTerminal(out))
+}
+
+// outputWidth returns the column limit to wrap output at.
+//
+// An explicit --width wins; otherwise the width of the terminal the output
+// goes to is used. Output that is not a terminal is not wrapped.
+//
+// Parameters:
+//   - opts: The parsed command-line options.
+//   - out: The file the output is written to.
+//
+// Returns:
+//   - The column limit, or zero to disable wrapping.
+func outputWidth(opts cliOptions, out *os.File) int {
+	if opts.widthSet {
+		return opts.width
+	}
+	if width, ok := terminal.Width(out); ok {
+		return width
+	}
+	return 0
+}
+
+// applyOutputOptions sets the output width and alignment of renderOpts from
+// the command-line options.
+//
+// Parameters:
+//   - renderOpts: The renderer options to update.
+//   - opts: The parsed command-line options.
+//   - out: The file the output is written to.
+func applyOutputOptions(renderOpts *renderer.Options, opts cliOptions, out *os.File) {
+	renderOpts.Width = outputWidth(opts, out)
+	renderOpts.Align = opts.align
+}
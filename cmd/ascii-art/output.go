@@ -0,0 +1,228 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// outputFileMode is the permission of a new --output file.
+const outputFileMode = 0o644
+
+// extensionFormats maps the file extensions of --output to the output format
+// they select when --format is not given.
+var extensionFormats = map[string]string{
+	".txt":  textFormat,
+	".html": htmlFormat,
+	".htm":  htmlFormat,
+	".svg":  svgFormat,
+	".png":  pngFormat,
+	".gif":  gifFormat,
+}
+
+// outputFile is the destination of the output: standard output, or with
+// --output a temporary file in the directory of the target, published as the
+// target once the output is complete so that the target never holds partial
+// output.
+type outputFile struct {
+	*os.File
+	// temp is the path of the temporary file; empty when writing to
+	// standard output or once the file has been published or removed.
+	temp string
+	// target is the --output path.
+	target string
+	// force allows replacing an existing target.
+	force bool
+}
+
+// pending is the output file being written, which exit removes when the
+// program fails before publishing it; nil when writing to standard output.
+var pending *outputFile
+
+// formatForPath returns the output format selected by the extension of an
+// --output path, case-insensitively.
+//
+// Parameters:
+//   - path: The --output path.
+//
+// Returns:
+//   - The format, or textFormat for an unknown or missing extension.
+func formatForPath(path string) string {
+	if format, ok := extensionFormats[strings.ToLower(filepath.Ext(path))]; ok {
+		return format
+	}
+	return textFormat
+}
+
+// openOutput returns the file to write the output to: standard output, or
+// with --output a new temporary file in the directory of the target, which
+// commit later publishes as the target. The program exits if the target
+// exists and --force is not given, if it is a directory, or if the temporary
+// file cannot be created.
+//
+// Parameters:
+//   - opts: The parsed command-line options.
+//
+// Returns:
+//   - The file to write the output to.
+func openOutput(opts cliOptions) *outputFile {
+	if opts.output == "" {
+		return &outputFile{File: os.Stdout}
+	}
+
+	mode := fs.FileMode(outputFileMode)
+	info, err := os.Stat(opts.output)
+	switch {
+	case err == nil && info.IsDir():
+		fmt.Fprintf(os.Stderr, "Error: %s is a directory\n", opts.output)
+		exit(exitCodeOutputError)
+	case err == nil && !opts.force:
+		fmt.Fprintln(os.Stderr, "Error:", errOutputExists(opts.output))
+		exit(exitCodeOutputError)
+	case err == nil:
+		mode = info.Mode().Perm()
+	case !errors.Is(err, fs.ErrNotExist):
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exit(exitCodeOutputError)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(opts.output), "."+filepath.Base(opts.output)+".*.tmp")
+	if err == nil {
+		pending = &outputFile{File: file, temp: file.Name(), target: opts.output, force: opts.force}
+		err = file.Chmod(mode)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exit(exitCodeOutputError)
+	}
+	return pending
+}
+
+// errOutputExists returns the error of an --output target that exists while
+// --force is not given.
+func errOutputExists(target string) error {
+	return fmt.Errorf("%s already exists; use --%s to overwrite it", target, forceOption)
+}
+
+// commit publishes the output with publish, and exits if that fails.
+func (o *outputFile) commit() {
+	if err := o.publish(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		exit(exitCodeOutputError)
+	}
+}
+
+// publish flushes the output to disk, closes it and, for --output, publishes
+// the temporary file as the target. With --force the target is replaced by a
+// rename. Without it the file is linked to the target, or copied to a target
+// created exclusively on file systems without hard links, so that a target
+// created since openOutput checked for one is never replaced; the temporary
+// file is then removed, with a warning if it cannot be.
+//
+// Returns:
+//   - An error if the file cannot be written or published; the temporary
+//     file is then left for discard to remove.
+func (o *outputFile) publish() error {
+	if o.temp == "" {
+		return nil
+	}
+
+	err := o.Sync()
+	if closeErr := o.Close(); err == nil {
+		err = closeErr
+	}
+	switch {
+	case err != nil:
+		return err
+	case o.force:
+		err = os.Rename(o.temp, o.target)
+	default:
+		if err = os.Link(o.temp, o.target); err != nil && !errors.Is(err, fs.ErrExist) {
+			err = copyExclusive(o.temp, o.target)
+		}
+		if errors.Is(err, fs.ErrExist) {
+			return errOutputExists(o.target)
+		}
+		if err == nil {
+			removeTemp(o.temp)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	o.temp = ""
+	return nil
+}
+
+// copyExclusive copies the file at src to a new file at dst with the same
+// permissions, failing if dst exists. A partial copy is removed.
+//
+// Parameters:
+//   - src: The path of the file to copy.
+//   - dst: The path of the copy, which must not exist.
+//
+// Returns:
+//   - An error wrapping fs.ErrExist if dst exists, or any error reading src
+//     or writing dst.
+func copyExclusive(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	err = out.Chmod(info.Mode().Perm())
+	if err == nil {
+		_, err = io.Copy(out, in)
+	}
+	if err == nil {
+		err = out.Sync()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		removeTemp(dst)
+	}
+	return err
+}
+
+// removeTemp removes a temporary file, warning on standard error if it
+// cannot, so that a stale file left next to the output does not go unnoticed.
+func removeTemp(path string) {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// discard removes the temporary file, if any, leaving the target untouched.
+func (o *outputFile) discard() {
+	if o == nil || o.temp == "" {
+		return
+	}
+	o.Close()
+	removeTemp(o.temp)
+	o.temp = ""
+}
+
+// exit removes the pending output file, if any, so that a failed run leaves
+// the --output target untouched, and exits with the given status.
+//
+// Parameters:
+//   - code: The exit status.
+func exit(code int) {
+	pending.discard()
+	os.Exit(code)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"github.com/vxanthio/ascii-art-color/internal/color"
+	"github.com/vxanthio/ascii-art-color/internal/coloring"
+)
+
+// rainbowPalette is the palette --rainbow stands for.
+const rainbowPalette = "rainbow"
+
+// parsePaletteRule splits a --palette value of the form NAME or NAME:TEXT.
+//
+// Parameters:
+//   - value: The --palette value.
+//
+// Returns:
+//   - colors: The palette colors, in cycling order.
+//   - substring: The TEXT part; empty without one.
+//   - hasSubstring: Whether the value names its own TEXT.
+//   - err: An error if NAME is not a built-in palette or TEXT is empty.
+func parsePaletteRule(value string) (colors []color.RGB, substring string, hasSubstring bool, err error) {
+	name, substring, hasSubstring, err := splitRule(value, func(name string) bool {
+		_, err := color.Palette(name)
+		return err == nil
+	})
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	colors, err = color.Palette(name)
+	return colors, substring, hasSubstring, err
+}
+
+// hasBarePalette reports whether any palette applies to the substring
+// argument rather than naming its own text.
+//
+// Parameters:
+//   - palettes: The --palette values, and "rainbow" for --rainbow.
+//
+// Returns:
+//   - true if a value has no :TEXT part, false otherwise.
+func hasBarePalette(palettes []string) bool {
+	for _, value := range palettes {
+		if _, _, hasSubstring, _ := parsePaletteRule(value); !hasSubstring {
+			return true
+		}
+	}
+	return false
+}
+
+// paletteStyles converts palette colors to styles.
+//
+// Parameters:
+//   - colors: The palette colors.
+//   - base: The background and attributes of every style.
+//
+// Returns:
+//   - One style per color, in order, with the color as foreground.
+func paletteStyles(colors []color.RGB, base color.Style) []coloring.Style {
+	styles := make([]coloring.Style, len(colors))
+	for i, rgb := range colors {
+		styles[i] = base.WithForeground(rgb)
+	}
+	return styles
+}
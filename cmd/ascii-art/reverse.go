@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vxanthio/ascii-art-color/internal/parser"
+	"github.com/vxanthio/ascii-art-color/internal/recognize"
+)
+
+// reverseOptions lists the options that may be combined with --reverse.
+var reverseOptions = []string{reverseOption, bannerOption, fontOption, outputOption, forceOption}
+
+// candidateBanner is a banner that --reverse tries to recognize the art with.
+type candidateBanner struct {
+	name   string
+	banner parser.Banner
+	// err is the error loading the banner, which skips it.
+	err error
+}
+
+// runReverse recognizes the art of the --reverse file and writes the text it
+// was rendered from to w, followed by a newline.
+//
+// The art is recognized with the --font file or the given banner; without
+// either, every banner that --list-banners shows is tried in turn and the
+// first that matches the art is used. The program exits if the art cannot be
+// read or no banner matches it.
+//
+// Parameters:
+//   - w: The writer to write the text to.
+//   - opts: The parsed command-line options.
+func runReverse(w io.Writer, opts cliOptions) {
+	art, err := readArt(opts.reverse)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exit(exitCodeInputError)
+	}
+
+	var failures []string
+	for _, candidate := range reverseBanners(opts) {
+		if candidate.err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", candidate.name, candidate.err))
+			continue
+		}
+		text, err := recognize.Text(art, candidate.banner.Glyphs, candidate.banner.Height)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", candidate.name, err))
+			continue
+		}
+		if art != "" {
+			text += "\n"
+		}
+		if _, err := io.WriteString(w, text); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			exit(exitCodeOutputError)
+		}
+		return
+	}
+
+	if len(failures) == 1 {
+		fmt.Fprintf(os.Stderr, "Error recognizing art with %s\n", failures[0])
+	} else {
+		fmt.Fprintf(os.Stderr, "Error recognizing art: no banner matches\n  %s\n", strings.Join(failures, "\n  "))
+	}
+	exit(exitCodeInputError)
+}
+
+// readArt reads the art to recognize from a file, or from standard input
+// when path is stdinArg.
+func readArt(path string) (string, error) {
+	if path == stdinArg {
+		data, err := io.ReadAll(os.Stdin)
+		return string(data), err
+	}
+	data, err := os.ReadFile(path)
+	return string(data), err
+}
+
+// reverseBanners returns the banners to recognize the art with, in the order
+// to try them: the --font file with its hardblanks drawn as spaces, the
+// banner given on the command line, or every banner findBanners finds. A
+// banner found that cannot be loaded is returned with its error.
+//
+// Parameters:
+//   - opts: The parsed command-line options.
+//
+// Returns:
+//   - The banners, each with the name to report it by.
+func reverseBanners(opts cliOptions) []candidateBanner {
+	if opts.font != "" {
+		font, err := parser.LoadFIGletFont(os.DirFS(filepath.Dir(opts.font)), filepath.Base(opts.font))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading font file: %v\n", err)
+			exit(exitCodeBannerError)
+		}
+		return []candidateBanner{{name: opts.font, banner: font.Banner()}}
+	}
+
+	if opts.bannerSet {
+		return []candidateBanner{{name: opts.banner, banner: loadBannerFile(opts.banner)}}
+	}
+	var banners []candidateBanner
+	for _, entry := range findBanners() {
+		banner, err := parser.LoadBanner(GetBannerFS(), entry.name+bannerExt)
+		banners = append(banners, candidateBanner{name: entry.name, banner: banner, err: err})
+	}
+	return banners
+}
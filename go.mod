@@ -1,3 +1,3 @@
-module ascii-art-color
+module github.com/vxanthio/ascii-art-color
 
 go 1.22.2
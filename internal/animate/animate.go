@@ -0,0 +1,301 @@
+// Package animate builds the frames of terminal animations from rendered
+// ASCII art.
+//
+// The rows given to the package are those written to a terminal: plain text
+// with any ANSI escape sequences the coloring package inserts, in any color
+// depth. Every visible character keeps the SGR sequences in effect where it
+// stands, so a frame can move, hide or show characters and still write each
+// one in its own colors. Sequences other than SGR are dropped.
+//
+// The characters of the art are handled by glyph: each glyph is a rectangle
+// of cells, in columns and rows, given by the caller, which knows the layout
+// of the text. Frames are returned as rows of text; playing them, by redrawing
+// the block in place, is up to the caller.
+//
+// Responsibilities of this package:
+//   - Join the cells of styled rows, as split by ansi.Split, back into rows
+//   - Build the frames of the typewriter, marquee and wave effects
+package animate
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/vxanthio/ascii-art-color/internal/ansi"
+	"github.com/vxanthio/ascii-art-color/internal/coloring"
+)
+
+// Effect is a terminal animation.
+type Effect int
+
+const (
+	// EffectTypewriter reveals the art one glyph at a time, and plays once.
+	EffectTypewriter Effect = iota
+	// EffectMarquee scrolls the art from right to left through a window of
+	// the output width, over and over.
+	EffectMarquee
+	// EffectWave moves the glyphs up and down in a travelling wave, over and
+	// over.
+	EffectWave
+)
+
+// effectNames lists the effect names, indexed by Effect.
+var effectNames = []string{"typewriter", "marquee", "wave"}
+
+// String returns the name of the effect.
+func (e Effect) String() string {
+	if e < 0 || int(e) >= len(effectNames) {
+		return fmt.Sprintf("Effect(%d)", int(e))
+	}
+	return effectNames[e]
+}
+
+// Repeats reports whether the frames of the effect play in a loop, until the
+// player is stopped, rather than once.
+func (e Effect) Repeats() bool {
+	return e != EffectTypewriter
+}
+
+// ParseEffect converts an animation name to an Effect.
+//
+// Parameters:
+//   - name: The animation name, case-insensitive: typewriter, marquee, or
+//     wave.
+//
+// Returns:
+//   - The effect.
+//   - An error if the name is not recognized.
+func ParseEffect(name string) (Effect, error) {
+	for i, effect := range effectNames {
+		if strings.EqualFold(name, effect) {
+			return Effect(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid animation: %q\nValid options: %s", name, strings.Join(effectNames, ", "))
+}
+
+const (
+	// waveAmplitude is how many rows a glyph of the wave effect rises and
+	// falls from its place.
+	waveAmplitude = 1
+	// wavePeriod is the number of frames of one wave.
+	wavePeriod = 12
+	// waveLength is the number of glyphs one wave spans.
+	waveLength = 6
+)
+
+// Options configures the frames of an animation.
+type Options struct {
+	// Glyphs lists the cells of every glyph in the order the text reads, as
+	// rectangles in columns and rows. Without them, every column is a glyph.
+	Glyphs []image.Rectangle
+	// Width is the number of columns of the marquee window; zero means the
+	// width of the art.
+	Width int
+}
+
+// blank is the cell of a hidden character.
+var blank = ansi.Cell{Text: " "}
+
+// Join encodes cells as a row, writing a style only where it changes and
+// resetting it at the end of the row.
+//
+// Parameters:
+//   - cells: The cells of a row.
+//
+// Returns:
+//   - The row.
+func Join(cells []ansi.Cell) string {
+	var b strings.Builder
+	current := ""
+	for _, cell := range cells {
+		if cell.SGR != current {
+			if current != "" {
+				b.WriteString(coloring.Reset)
+			}
+			b.WriteString(cell.SGR)
+			current = cell.SGR
+		}
+		b.WriteString(cell.Text)
+	}
+	if current != "" {
+		b.WriteString(coloring.Reset)
+	}
+	return b.String()
+}
+
+// Frames builds the frames of an animation of rendered rows. All frames of
+// an animation have the same number of rows, so each can be drawn over the
+// previous one.
+//
+// The typewriter effect adds one glyph per frame, ending with the complete
+// art. The marquee effect shows the art through a window of opts.Width
+// columns, moving it one column left per frame from just beyond the right
+// edge of the window until it has left on the left. The wave effect gives
+// every block of glyphs two extra rows and moves each glyph up and down
+// within them, a little behind the glyph before it.
+//
+// Parameters:
+//   - rows: The rendered rows, possibly containing ANSI escape sequences.
+//   - effect: The animation.
+//   - opts: The glyphs of the art and the width of the marquee window.
+//
+// Returns:
+//   - The frames, each a list of rows; none for an art without columns.
+func Frames(rows []string, effect Effect, opts Options) [][]string {
+	grid := make([][]ansi.Cell, len(rows))
+	columns := 0
+	for i, row := range rows {
+		grid[i] = ansi.Split(row)
+		columns = max(columns, len(grid[i]))
+	}
+	if columns == 0 {
+		return nil
+	}
+	glyphs := opts.Glyphs
+	if len(glyphs) == 0 {
+		for column := 0; column < columns; column++ {
+			glyphs = append(glyphs, image.Rect(column, 0, column+1, len(grid)))
+		}
+	}
+
+	switch effect {
+	case EffectMarquee:
+		return marqueeFrames(grid, columns, opts.Width)
+	case EffectWave:
+		return waveFrames(grid, glyphs)
+	}
+	return typewriterFrames(grid, glyphs)
+}
+
+// typewriterFrames shows the cells of one more glyph per frame.
+func typewriterFrames(grid [][]ansi.Cell, glyphs []image.Rectangle) [][]string {
+	frames := make([][]string, 0, len(glyphs))
+	for n := 1; n <= len(glyphs); n++ {
+		frame := make([]string, len(grid))
+		for i, cells := range grid {
+			shown := make([]ansi.Cell, len(cells))
+			for column, cell := range cells {
+				shown[column] = blank
+				for _, glyph := range glyphs[:n] {
+					if image.Pt(column, i).In(glyph) {
+						shown[column] = cell
+						break
+					}
+				}
+			}
+			frame[i] = Join(shown)
+		}
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+// marqueeFrames shows the art through a window of width columns, one column
+// further left per frame.
+func marqueeFrames(grid [][]ansi.Cell, columns, width int) [][]string {
+	if width <= 0 {
+		width = columns
+	}
+	frames := make([][]string, 0, width+columns)
+	for offset := -width; offset < columns; offset++ {
+		frame := make([]string, len(grid))
+		for i, cells := range grid {
+			window := make([]ansi.Cell, width)
+			for x := range window {
+				window[x] = blank
+				if column := offset + x; column >= 0 && column < len(cells) {
+					window[x] = cells[column]
+				}
+			}
+			frame[i] = Join(window)
+		}
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+// waveFrames moves every glyph up and down by up to waveAmplitude rows. Each
+// block of glyphs, the glyphs sharing the same rows, gets waveAmplitude extra
+// rows above and below; cells outside every glyph stay in the middle of their
+// block.
+func waveFrames(grid [][]ansi.Cell, glyphs []image.Rectangle) [][]string {
+	// place[r] is the row of the frame that row r of the art maps to.
+	inBlock := make([]bool, len(grid))
+	bottoms := map[int]bool{}
+	for _, glyph := range glyphs {
+		bottoms[glyph.Max.Y] = true
+		for r := max(glyph.Min.Y, 0); r < min(glyph.Max.Y, len(grid)); r++ {
+			inBlock[r] = true
+		}
+	}
+	place := make([]int, len(grid))
+	extra := 0
+	for r := range grid {
+		if bottoms[r] {
+			extra += 2 * waveAmplitude
+		}
+		place[r] = r + extra
+		if inBlock[r] {
+			place[r] += waveAmplitude
+		}
+	}
+	if bottoms[len(grid)] {
+		extra += 2 * waveAmplitude
+	}
+
+	frames := make([][]string, wavePeriod)
+	for f := range frames {
+		out := make([][]ansi.Cell, len(grid)+extra)
+		for r, cells := range grid {
+			out[place[r]] = slices.Clone(cells)
+		}
+		for g, glyph := range glyphs {
+			phase := 2 * math.Pi * (float64(f)/wavePeriod - float64(g)/waveLength)
+			offset := int(math.Round(waveAmplitude * math.Sin(phase)))
+			moveGlyph(grid, out, glyph, place, offset)
+		}
+
+		frame := make([]string, len(out))
+		for r, cells := range out {
+			frame[r] = Join(cells)
+		}
+		frames[f] = frame
+	}
+	return frames
+}
+
+// moveGlyph redraws the cells of glyph in out, offset rows below their place
+// in the middle of the block: it clears the glyph's columns over the whole
+// height of the block, then copies its cells.
+func moveGlyph(grid, out [][]ansi.Cell, glyph image.Rectangle, place []int, offset int) {
+	if glyph.Min.Y < 0 || glyph.Max.Y > len(grid) || glyph.Empty() {
+		return
+	}
+	top := place[glyph.Min.Y] - waveAmplitude
+	for r := top; r < top+glyph.Dy()+2*waveAmplitude; r++ {
+		for column := glyph.Min.X; column < glyph.Max.X; column++ {
+			set(out, r, column, blank)
+		}
+	}
+	for r := glyph.Min.Y; r < glyph.Max.Y; r++ {
+		for column := glyph.Min.X; column < glyph.Max.X && column < len(grid[r]); column++ {
+			set(out, top+waveAmplitude+offset+(r-glyph.Min.Y), column, grid[r][column])
+		}
+	}
+}
+
+// set writes cell at row r and column of out, padding the row with blank
+// cells as needed.
+func set(out [][]ansi.Cell, r, column int, cell ansi.Cell) {
+	if r < 0 || r >= len(out) || column < 0 {
+		return
+	}
+	for len(out[r]) <= column {
+		out[r] = append(out[r], blank)
+	}
+	out[r][column] = cell
+}
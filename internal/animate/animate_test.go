@@ -0,0 +1,136 @@
+package animate_test
+
+import (
+	"image"
+	"slices"
+	"testing"
+
+	"github.com/vxanthio/ascii-art-color/internal/animate"
+	"github.com/vxanthio/ascii-art-color/internal/ansi"
+)
+
+const red = "\033[31m"
+
+func TestParseEffect(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    animate.Effect
+		wantErr bool
+	}{
+		{"typewriter", animate.EffectTypewriter, false},
+		{"Marquee", animate.EffectMarquee, false},
+		{"WAVE", animate.EffectWave, false},
+		{"spin", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := animate.ParseEffect(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseEffect(%q) = %v, %v; want %v, error %t", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestEffect_Repeats(t *testing.T) {
+	if animate.EffectTypewriter.Repeats() || !animate.EffectMarquee.Repeats() || !animate.EffectWave.Repeats() {
+		t.Error("only the marquee and wave effects should repeat")
+	}
+}
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		name  string
+		cells []ansi.Cell
+		want  string
+	}{
+		{"plain", []ansi.Cell{{Text: "a"}, {Text: "b"}}, "ab"},
+		{
+			name:  "styled run",
+			cells: []ansi.Cell{{Text: "a"}, {Text: "b", SGR: red}, {Text: "c", SGR: red}, {Text: "d"}},
+			want:  "a" + red + "bc\033[0md",
+		},
+		{
+			name:  "stacked sequences and reset at the end",
+			cells: []ansi.Cell{{Text: "é", SGR: "\033[1m" + red}},
+			want:  "\033[1m" + red + "é\033[0m",
+		},
+		{"no cells", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := animate.Join(tt.cells); got != tt.want {
+				t.Errorf("Join(%q) = %q, want %q", tt.cells, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrames_Typewriter(t *testing.T) {
+	rows := []string{"ab" + red + "c\033[0m", "de" + red + "f\033[0m"}
+	glyphs := []image.Rectangle{image.Rect(0, 0, 2, 2), image.Rect(2, 0, 3, 2)}
+
+	got := animate.Frames(rows, animate.EffectTypewriter, animate.Options{Glyphs: glyphs})
+	want := [][]string{
+		{"ab ", "de "},
+		{"ab" + red + "c\033[0m", "de" + red + "f\033[0m"},
+	}
+	if !equalFrames(got, want) {
+		t.Errorf("Frames() = %q, want %q", got, want)
+	}
+
+	got = animate.Frames([]string{"ab"}, animate.EffectTypewriter, animate.Options{})
+	if want := [][]string{{"a "}, {"ab"}}; !equalFrames(got, want) {
+		t.Errorf("Frames() without glyphs = %q, want %q", got, want)
+	}
+}
+
+func TestFrames_Marquee(t *testing.T) {
+	got := animate.Frames([]string{"ab"}, animate.EffectMarquee, animate.Options{Width: 3})
+	want := [][]string{{"   "}, {"  a"}, {" ab"}, {"ab "}, {"b  "}}
+	if !equalFrames(got, want) {
+		t.Errorf("Frames() = %q, want %q", got, want)
+	}
+
+	if got := animate.Frames([]string{"ab"}, animate.EffectMarquee, animate.Options{}); len(got) != 4 {
+		t.Errorf("Frames() without width = %d frames, want 4", len(got))
+	}
+}
+
+func TestFrames_Wave(t *testing.T) {
+	rows := []string{"ab", "cd", "", "ef"}
+	glyphs := []image.Rectangle{
+		image.Rect(0, 0, 1, 2), image.Rect(1, 0, 2, 2),
+		image.Rect(0, 3, 1, 4), image.Rect(1, 3, 2, 4),
+	}
+	frames := animate.Frames(rows, animate.EffectWave, animate.Options{Glyphs: glyphs})
+	if len(frames) != 12 {
+		t.Fatalf("frames = %d, want 12", len(frames))
+	}
+	for i, frame := range frames {
+		if len(frame) != 8 {
+			t.Fatalf("frame %d has %d rows, want 8", i, len(frame))
+		}
+	}
+
+	// In the first frame the first glyph sits in the middle of its block and
+	// the glyphs after it follow the wave: "b" and "e" one row higher, "f"
+	// back in the middle. Cleared cells stay as blanks.
+	want := []string{" b", "ad", "c ", "  ", "", "e ", " f", "  "}
+	if got := frames[0]; !slices.Equal(got, want) {
+		t.Errorf("frame 0 = %q, want %q", got, want)
+	}
+	// A quarter period later the first two glyphs have sunk to the bottom.
+	want = []string{"  ", "  ", "ab", "cd"}
+	if got := frames[3][:4]; !slices.Equal(got, want) {
+		t.Errorf("frame 3 = %q, want %q", got, want)
+	}
+}
+
+func TestFrames_Empty(t *testing.T) {
+	if got := animate.Frames([]string{"", ""}, animate.EffectWave, animate.Options{}); got != nil {
+		t.Errorf("Frames() of empty rows = %q, want nil", got)
+	}
+}
+
+func equalFrames(a, b [][]string) bool {
+	return slices.EqualFunc(a, b, slices.Equal[[]string])
+}
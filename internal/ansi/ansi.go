@@ -0,0 +1,104 @@
+// Package ansi scans the ANSI escape sequences in rendered rows.
+//
+// The rows handled by the package are those written to a terminal: plain text
+// with the CSI escape sequences (ESC [ parameters final-byte) the coloring
+// package inserts. Escape measures one sequence. Split decodes a row into
+// cells, each a visible character with the SGR sequences in effect where it
+// stands. Strip removes every sequence.
+//
+// Every package that measures, styles or strips rendered rows scans them
+// here, so they all agree on where a sequence ends.
+//
+// Responsibilities of this package:
+//   - Measure the escape sequence at the start of a string
+//   - Split styled rows into cells
+//   - Strip the escape sequences of a string
+package ansi
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Escape measures the CSI escape sequence at the start of s. A sequence
+// without a final byte runs to the end of s.
+//
+// Parameters:
+//   - s: The text to scan.
+//
+// Returns:
+//   - n: The length of the sequence in bytes; zero if s does not start with
+//     one.
+//   - params: The parameters of the sequence.
+//   - sgr: Whether the sequence is an SGR sequence (ESC [ ... m).
+func Escape(s string) (n int, params string, sgr bool) {
+	if !strings.HasPrefix(s, "\033[") {
+		return 0, "", false
+	}
+	for i := 2; i < len(s); i++ {
+		if c := s[i]; c >= 0x40 && c <= 0x7e {
+			return i + 1, s[2:i], c == 'm'
+		}
+	}
+	return len(s), "", false
+}
+
+// Cell is one column of a rendered row.
+type Cell struct {
+	// Text is the character in the cell.
+	Text string
+	// SGR holds the SGR sequences in effect for the character, since the
+	// last reset; empty for unstyled text.
+	SGR string
+}
+
+// Split decodes a rendered row into its cells. SGR sequences accumulate
+// until a reset (ESC [ m or ESC [ 0 m); other escape sequences are dropped.
+//
+// Parameters:
+//   - row: A rendered row, possibly containing ANSI escape sequences.
+//
+// Returns:
+//   - One cell per visible column of row.
+func Split(row string) []Cell {
+	cells := make([]Cell, 0, len(row))
+	sgr := ""
+	for i := 0; i < len(row); {
+		if n, params, ok := Escape(row[i:]); n > 0 {
+			if ok && (params == "" || params == "0") {
+				sgr = ""
+			} else if ok {
+				sgr += row[i : i+n]
+			}
+			i += n
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(row[i:])
+		cells = append(cells, Cell{Text: row[i : i+size], SGR: sgr})
+		i += size
+	}
+	return cells
+}
+
+// Strip removes the escape sequences of s.
+//
+// Parameters:
+//   - s: The text to strip, possibly containing ANSI escape sequences.
+//
+// Returns:
+//   - s without its escape sequences.
+func Strip(s string) string {
+	if !strings.Contains(s, "\033[") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if n, _, _ := Escape(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		b.WriteByte(s[i])
+		i++
+	}
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package ansi_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/vxanthio/ascii-art-color/internal/ansi"
+)
+
+const red = "\033[31m"
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		name       string
+		s          string
+		wantN      int
+		wantParams string
+		wantSGR    bool
+	}{
+		{"plain text", "abc", 0, "", false},
+		{"lone escape", "\033", 0, "", false},
+		{"sgr", red + "x", 5, "31", true},
+		{"reset", "\033[mx", 3, "", true},
+		{"other sequence", "\033[2Kx", 4, "2", false},
+		{"unterminated", "\033[38;2", 6, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, params, sgr := ansi.Escape(tt.s)
+			if n != tt.wantN || params != tt.wantParams || sgr != tt.wantSGR {
+				t.Errorf("Escape(%q) = %d, %q, %t; want %d, %q, %t",
+					tt.s, n, params, sgr, tt.wantN, tt.wantParams, tt.wantSGR)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name string
+		row  string
+		want []ansi.Cell
+	}{
+		{"plain", "ab", []ansi.Cell{{Text: "a"}, {Text: "b"}}},
+		{
+			name: "styled run",
+			row:  "a" + red + "bc\033[0md",
+			want: []ansi.Cell{{Text: "a"}, {Text: "b", SGR: red}, {Text: "c", SGR: red}, {Text: "d"}},
+		},
+		{
+			name: "stacked sequences",
+			row:  "\033[1m" + red + "é\033[m",
+			want: []ansi.Cell{{Text: "é", SGR: "\033[1m" + red}},
+		},
+		{"cursor sequences dropped", "a\033[2Kb", []ansi.Cell{{Text: "a"}, {Text: "b"}}},
+		{"empty", "", []ansi.Cell{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ansi.Split(tt.row); !slices.Equal(got, tt.want) {
+				t.Errorf("Split(%q) = %q, want %q", tt.row, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrip(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"plain", "| |", "| |"},
+		{"colored", red + "é\033[0m|", "é|"},
+		{"other sequences", "a\033[2Kb\033[1;1H", "ab"},
+		{"lone escape kept", "a\033b", "a\033b"},
+		{"unterminated", "ab\033[38;2", "ab"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ansi.Strip(tt.s); got != tt.want {
+				t.Errorf("Strip(%q) = %q, want %q", tt.s, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+// Package banners embeds the built-in banner files, so that the ascii-art
+// command and the public asciiart package share a single copy of them.
+//
+// Every banner is a file named after it, with the .txt extension, at the root
+// of FS, in the classic banner format read by parser.LoadBanner.
+//
+// Responsibilities of this package:
+//   - Embed the standard, shadow and thinkertoy banner files
+//   - List the names of the built-in banners
+package banners
+
+import "embed"
+
+// FS holds the built-in banner files. The files are read-only and frozen at
+// compile time, so programs embedding them run from any directory.
+//
+//go:embed standard.txt shadow.txt thinkertoy.txt
+var FS embed.FS
+
+// Names lists the built-in banners in their usual order, the default banner
+// first.
+var Names = []string{"standard", "shadow", "thinkertoy"}
@@ -1,10 +1,22 @@
 // Package color parses color specifications into ANSI 24-bit terminal escape codes.
 //
 // Supported formats:
-//   - Named colors: black, red, green, yellow, blue, magenta, cyan, white,
-//     orange, purple, pink, brown, gray (case-insensitive)
-//   - Hex: #RRGGBB (e.g. #ff0000)
-//   - RGB: rgb(R, G, B) (e.g. rgb(255, 0, 0))
+//   - Named colors: the 148 CSS color names, such as red, orange, navy or
+//     rebeccapurple, and names added with AddNames (case- and
+//     space-insensitive)
+//   - Hex: #RGB, #RGBA, #RRGGBB and #RRGGBBAA (e.g. #f80, #ff8800)
+//   - RGB: rgb(R, G, B) with components from 0 to 255 or percentages
+//     (e.g. rgb(255, 0, 0), rgb(100%, 50%, 0%))
+//   - HSL and HSV: hsl(H, S%, L%) and hsv(H, S%, V%), with the hue in
+//     degrees (e.g. hsl(30, 100%, 50%))
+//   - ANSI: ansi:N, color N of the xterm 256-color palette (e.g. ansi:208)
+//
+// rgba() and hsla() are accepted as aliases of rgb() and hsl(), and rgb() and
+// hsl() take an optional fourth alpha component. Terminals cannot blend, so
+// alpha, in hex or functional form, is validated and then ignored.
+//
+// A Style combines a foreground color with a background color (48;2) and
+// SGR text attributes such as bold and underline into one escape sequence.
 //
 // Example:
 //
@@ -15,6 +27,7 @@ package color
 import (
 	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 )
@@ -25,6 +38,8 @@ const (
 	hexBase       = 16
 	uint8Bits     = 8
 	ansi24BitFmt  = "\033[38;2;%d;%d;%dm"
+	ansiPrefix    = "ansi:"
+	paletteSize   = 256
 )
 
 // RGB represents a 24-bit color.
@@ -32,38 +47,26 @@ type RGB struct {
 	R, G, B uint8
 }
 
-var namedColors = map[string]RGB{
-	"black":   {0, 0, 0},
-	"red":     {255, 0, 0},
-	"green":   {0, 255, 0},
-	"yellow":  {255, 255, 0},
-	"blue":    {0, 0, 255},
-	"magenta": {255, 0, 255},
-	"cyan":    {0, 255, 255},
-	"white":   {255, 255, 255},
-	"orange":  {255, 165, 0},
-	"purple":  {128, 0, 128},
-	"pink":    {255, 192, 203},
-	"brown":   {165, 42, 42},
-	"gray":    {128, 128, 128},
-}
-
 // ErrInvalidFormat is returned when color specification is malformed.
 var ErrInvalidFormat = errors.New("invalid color format")
 
 // Parse converts a color specification string to an RGB value.
 //
 // Supported formats:
-//   - Named (case-insensitive): red, green, blue, orange, purple, etc.
-//   - Hex: #RRGGBB (e.g. #ff0000)
-//   - RGB: rgb(R,G,B) (e.g. rgb(255,0,0))
+//   - Named (case-insensitive): red, green, blue, orange, navy, etc.
+//   - Hex: #RGB, #RGBA, #RRGGBB or #RRGGBBAA (e.g. #ff0000)
+//   - RGB: rgb(R,G,B) or rgba(R,G,B,A), components 0-255 or percentages
+//   - HSL and HSV: hsl(H,S%,L%), hsla(H,S%,L%,A) and hsv(H,S%,V%)
+//   - ANSI: ansi:N, with N from 0 to 255
 //
 // Parameters:
 //   - colorSpec: The color specification string to parse.
 //
 // Returns:
 //   - An RGB value representing the parsed color.
-//   - ErrInvalidFormat (wrapped) if the input is empty, unknown, or malformed.
+//   - ErrInvalidFormat (wrapped) if the input is empty, unknown, or
+//     malformed. The error for an unknown name suggests the closest valid
+//     name, if there is one.
 func Parse(colorSpec string) (RGB, error) {
 	colorSpec = strings.TrimSpace(colorSpec)
 
@@ -72,88 +75,270 @@ func Parse(colorSpec string) (RGB, error) {
 	}
 	lower := strings.ToLower(colorSpec)
 
-	if color, ok := namedColors[lower]; ok {
+	if color, ok := lookupName(colorSpec); ok {
 		return color, nil
 	}
-	if len(colorSpec) == 7 && colorSpec[0] == '#' {
+	if colorSpec[0] == '#' {
 		return parseHex(colorSpec)
 	}
-	if strings.HasPrefix(lower, "rgb(") {
-		return parseRGB(lower)
+	if strings.HasPrefix(lower, ansiPrefix) {
+		return parseANSIIndex(lower[len(ansiPrefix):])
+	}
+	if name, args, ok := splitFunction(lower); ok {
+		switch name {
+		case "rgb", "rgba":
+			return parseRGB(name, args)
+		case "hsl", "hsla", "hsv":
+			return parseHue(name, args)
+		}
+	}
+
+	if isName(colorSpec) {
+		if suggestion, ok := Suggest(colorSpec); ok {
+			return RGB{}, fmt.Errorf("unknown color %q: %w; did you mean '%s'?", colorSpec, ErrInvalidFormat, suggestion)
+		}
 	}
 	return RGB{}, fmt.Errorf("unknown color format %q: %w", colorSpec, ErrInvalidFormat)
 }
 
-// parseHex parses a hex color string in format #RRGGBB to RGB.
+// isName reports whether spec could be a color name: letters and spaces only.
+func isName(spec string) bool {
+	for _, ch := range spec {
+		if ch != ' ' && (ch < 'a' || ch > 'z') && (ch < 'A' || ch > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// parseHex parses a hex color string in format #RGB, #RGBA, #RRGGBB or
+// #RRGGBBAA to RGB. The alpha digits must be valid but are ignored.
 //
 // Parameters:
-//   - hex: Color string in format #RRGGBB (e.g., "#ff0000").
+//   - hex: Color string starting with "#" (e.g., "#ff0000").
 //
 // Returns:
 //   - RGB value representing the parsed color.
-//   - An error if the hex format is invalid or values are out of range.
+//   - An error if the length is wrong or a digit is not hexadecimal.
 func parseHex(hex string) (RGB, error) {
-	r, err := strconv.ParseUint(hex[1:3], hexBase, uint8Bits)
-	if err != nil {
-		return RGB{}, fmt.Errorf("invalid red hex: %w", err)
+	digits := hex[1:]
+	switch len(digits) {
+	case 3, 4:
+		var doubled strings.Builder
+		for _, ch := range digits {
+			doubled.WriteString(strings.Repeat(string(ch), 2))
+		}
+		digits = doubled.String()
+	case 6, 8:
+	default:
+		return RGB{}, fmt.Errorf("hex color %q must have 3, 4, 6 or 8 digits: %w", hex, ErrInvalidFormat)
 	}
 
-	g, err := strconv.ParseUint(hex[3:5], hexBase, uint8Bits)
-	if err != nil {
-		return RGB{}, fmt.Errorf("invalid green hex: %w", err)
-	}
-	b, err := strconv.ParseUint(hex[5:7], hexBase, uint8Bits)
-	if err != nil {
-		return RGB{}, fmt.Errorf("invalid blue hex: %w", err)
+	var c [4]uint8
+	for i, name := range []string{"red", "green", "blue", "alpha"}[:len(digits)/2] {
+		value, err := strconv.ParseUint(digits[2*i:2*i+2], hexBase, uint8Bits)
+		if err != nil {
+			return RGB{}, fmt.Errorf("invalid %s hex: %w", name, err)
+		}
+		c[i] = uint8(value)
 	}
-
-	return RGB{uint8(r), uint8(g), uint8(b)}, nil
+	return RGB{c[0], c[1], c[2]}, nil
 }
 
-// parseRGB parses an RGB color string in format rgb(R,G,B) to RGB.
+// splitFunction splits a functional notation such as "rgb(1, 2, 3)" into its
+// name and its comma-separated arguments.
 //
 // Parameters:
-//   - rgbStr: Color string in format rgb(R,G,B) (e.g., "rgb(255,0,0)").
+//   - spec: The lowercase color specification.
 //
 // Returns:
-//   - RGB value representing the parsed color.
-//   - An error if the format is invalid or component values are out of range (0-255).
-func parseRGB(rgbStr string) (RGB, error) {
-	if !strings.HasSuffix(rgbStr, ")") {
-		return RGB{}, fmt.Errorf("missing closing parenthesis: %w", ErrInvalidFormat)
+//   - name: The function name.
+//   - args: The trimmed arguments.
+//   - ok: Whether spec has the form name(...).
+func splitFunction(spec string) (name string, args []string, ok bool) {
+	name, rest, found := strings.Cut(spec, "(")
+	if !found {
+		return "", nil, false
+	}
+	name = strings.TrimSpace(name)
+	if !strings.HasSuffix(rest, ")") {
+		return name, nil, true
+	}
+	for _, arg := range strings.Split(strings.TrimSuffix(rest, ")"), ",") {
+		args = append(args, strings.TrimSpace(arg))
 	}
-	content := strings.TrimPrefix(rgbStr, "rgb(")
-	content = strings.TrimSuffix(content, ")")
+	return name, args, true
+}
 
-	content = strings.TrimSpace(content)
-	if content == "" {
-		return RGB{}, fmt.Errorf("rgb() components cannot be empty")
+// checkArgs verifies the arguments of a color function: present, closed by a
+// parenthesis, and three of them, or four with an alpha component.
+//
+// Parameters:
+//   - name: The function name, for error messages.
+//   - args: The arguments; nil when the closing parenthesis is missing.
+//   - alpha: Whether a fourth, alpha component is allowed.
+//
+// Returns:
+//   - An error if the arguments are missing, empty or of the wrong count, or
+//     if the alpha component is invalid.
+func checkArgs(name string, args []string, alpha bool) error {
+	if args == nil {
+		return fmt.Errorf("missing closing parenthesis: %w", ErrInvalidFormat)
+	}
+	if len(args) == 1 && args[0] == "" {
+		return fmt.Errorf("%s() components cannot be empty", name)
 	}
+	if len(args) == rgbComponents {
+		return nil
+	}
+	if !alpha || len(args) != rgbComponents+1 {
+		if alpha {
+			return fmt.Errorf("%s() requires 3 or 4 components, got %d", name, len(args))
+		}
+		return fmt.Errorf("%s() requires exactly 3 components, got %d", name, len(args))
+	}
+	if _, err := parseFraction(args[rgbComponents], 1); err != nil {
+		return fmt.Errorf("invalid %s() alpha %q: %w", name, args[rgbComponents], err)
+	}
+	return nil
+}
 
-	parts := strings.Split(content, ",")
-	if len(parts) != rgbComponents {
-		return RGB{}, fmt.Errorf("rgb() requires exactly 3 components, got %d", len(parts))
+// parseRGB parses the arguments of rgb(R,G,B) or rgba(R,G,B,A) to RGB.
+//
+// Parameters:
+//   - name: The function name, rgb or rgba.
+//   - args: The arguments; each component is an integer from 0 to 255 or a
+//     percentage from 0% to 100%.
+//
+// Returns:
+//   - RGB value representing the parsed color.
+//   - An error if the format is invalid or component values are out of range.
+func parseRGB(name string, args []string) (RGB, error) {
+	if err := checkArgs(name, args, true); err != nil {
+		return RGB{}, err
 	}
 
-	var r, g, b uint8
-	for i, part := range parts {
-		valueString := strings.TrimSpace(part)
+	var c [rgbComponents]uint8
+	for i := range c {
+		valueString := args[i]
+		if strings.HasSuffix(valueString, "%") {
+			f, err := parseFraction(valueString, 1)
+			if err != nil {
+				return RGB{}, fmt.Errorf("invalid %s() component %q: %w", name, valueString, err)
+			}
+			c[i] = toByte(f)
+			continue
+		}
 		value, err := strconv.ParseUint(valueString, decimalBase, uint8Bits)
 		if err != nil {
-			return RGB{}, fmt.Errorf("invalid rgb() component %q: %w", valueString, err)
+			return RGB{}, fmt.Errorf("invalid %s() component %q: %w", name, valueString, err)
 		}
+		c[i] = uint8(value)
+	}
+	return RGB{c[0], c[1], c[2]}, nil
+}
+
+// parseHue parses the arguments of hsl(H,S%,L%), hsla(H,S%,L%,A) or
+// hsv(H,S%,V%) to RGB.
+//
+// Parameters:
+//   - name: The function name, hsl, hsla or hsv.
+//   - args: The arguments: the hue in degrees, optionally suffixed with
+//     "deg", then two percentages from 0 to 100, with or without "%".
+//
+// Returns:
+//   - RGB value representing the parsed color.
+//   - An error if the format is invalid or a percentage is out of range.
+func parseHue(name string, args []string) (RGB, error) {
+	if err := checkArgs(name, args, name != "hsv"); err != nil {
+		return RGB{}, err
+	}
 
-		switch i {
-		case 0:
-			r = uint8(value)
-		case 1:
-			g = uint8(value)
-		case 2:
-			b = uint8(value)
+	h, err := strconv.ParseFloat(strings.TrimSuffix(args[0], "deg"), 64)
+	if err != nil || math.IsInf(h, 0) || math.IsNaN(h) {
+		return RGB{}, fmt.Errorf("invalid %s() hue %q: %w", name, args[0], ErrInvalidFormat)
+	}
+	h = math.Mod(math.Mod(h, 360)+360, 360)
+
+	var p [2]float64
+	for i := range p {
+		arg := args[i+1]
+		if !strings.HasSuffix(arg, "%") {
+			arg += "%"
+		}
+		if p[i], err = parseFraction(arg, 1); err != nil {
+			return RGB{}, fmt.Errorf("invalid %s() component %q: %w", name, args[i+1], err)
 		}
 	}
 
-	return RGB{r, g, b}, nil
+	if name == "hsv" {
+		return fromHSV(h, p[0], p[1]), nil
+	}
+	return fromHSL(h, p[0], p[1]), nil
+}
+
+// parseFraction parses a number from 0 to limit, or a percentage from 0% to
+// 100%, as a fraction of the full range.
+//
+// Parameters:
+//   - s: The number or percentage.
+//   - limit: The largest plain number; percentages always end at 100.
+//
+// Returns:
+//   - The value divided by its full range, from 0 to 1.
+//   - ErrInvalidFormat (wrapped) if s is not a number or is out of range.
+func parseFraction(s string, limit float64) (float64, error) {
+	number, percent := strings.CutSuffix(s, "%")
+	if percent {
+		limit = 100
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+	if err != nil || !(value >= 0 && value <= limit) {
+		return 0, fmt.Errorf("%q is not between 0 and %g: %w", s, limit, ErrInvalidFormat)
+	}
+	return value / limit, nil
+}
+
+// fromHSV converts hue in degrees and saturation and value in [0, 1] to RGB,
+// by way of HSL.
+func fromHSV(h, s, v float64) RGB {
+	l := v * (1 - s/2)
+	sl := 0.0
+	if l > 0 && l < 1 {
+		sl = (v - l) / math.Min(l, 1-l)
+	}
+	return fromHSL(h, sl, l)
+}
+
+// parseANSIIndex parses the index of an ansi:N color.
+//
+// Parameters:
+//   - index: The text after "ansi:".
+//
+// Returns:
+//   - The color of the xterm 256-color palette at that index.
+//   - An error if index is not an integer from 0 to 255.
+func parseANSIIndex(index string) (RGB, error) {
+	i, err := strconv.Atoi(strings.TrimSpace(index))
+	if err != nil || i < 0 || i >= paletteSize {
+		return RGB{}, fmt.Errorf("invalid ANSI color index %q, want 0 to 255: %w", index, ErrInvalidFormat)
+	}
+	return xtermColor(i), nil
+}
+
+// xtermColor returns color i of the xterm 256-color palette: the 16 basic
+// colors, the 6×6×6 color cube and the 24-step gray ramp.
+func xtermColor(i int) RGB {
+	switch {
+	case i < len(basicColors):
+		return basicColors[i]
+	case i < 232:
+		i -= 16
+		return RGB{uint8(cubeLevels[i/36]), uint8(cubeLevels[i/6%6]), uint8(cubeLevels[i%6])}
+	default:
+		gray := uint8(8 + 10*(i-232))
+		return RGB{gray, gray, gray}
+	}
 }
 
 // ANSI returns the 24-bit ANSI escape sequence for the given color.
@@ -1,9 +1,11 @@
 package color_test
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
-	"ascii-art-color/internal/color"
+	"github.com/vxanthio/ascii-art-color/internal/color"
 )
 
 func TestParse(t *testing.T) {
@@ -27,10 +29,22 @@ func TestParse(t *testing.T) {
 		{"named_brown", "brown", color.RGB{165, 42, 42}, false},
 		{"named_gray", "gray", color.RGB{128, 128, 128}, false},
 
+		// CSS names
+		{"css_navy", "navy", color.RGB{0, 0, 128}, false},
+		{"css_rebeccapurple", "RebeccaPurple", color.RGB{102, 51, 153}, false},
+		{"css_spaces", "light goldenrod yellow", color.RGB{250, 250, 210}, false},
+		{"css_lime", "lime", color.RGB{0, 255, 0}, false},
+		{"css_grey", "grey", color.RGB{128, 128, 128}, false},
+
 		// Hex
 		{"hex_red", "#ff0000", color.RGB{255, 0, 0}, false},
-		{"hex_invalid_length_short", "#ff0", color.RGB{}, true},
-		{"hex_invalid_length_long", "#ff000000", color.RGB{}, true},
+		{"hex_short", "#f80", color.RGB{255, 136, 0}, false},
+		{"hex_short_alpha", "#f808", color.RGB{255, 136, 0}, false},
+		{"hex_alpha", "#ff000080", color.RGB{255, 0, 0}, false},
+		{"hex_invalid_length_short", "#ff", color.RGB{}, true},
+		{"hex_invalid_length_odd", "#ff00000", color.RGB{}, true},
+		{"hex_invalid_length_long", "#ff00000000", color.RGB{}, true},
+		{"hex_invalid_alpha", "#ff0000zz", color.RGB{}, true},
 		{"hex_invalid_chars", "#gg0000", color.RGB{}, true},
 		{"hex_invalid_green", "#ffgg00", color.RGB{}, true},
 		{"hex_invalid_blue", "#ffffzz", color.RGB{}, true},
@@ -46,6 +60,35 @@ func TestParse(t *testing.T) {
 		{"padded_named", " red ", color.RGB{255, 0, 0}, false},
 		{"rgb_boundary_low", "rgb(0,0,0)", color.RGB{0, 0, 0}, false},
 		{"rgb_missing_paren", "rgb(255,0,0", color.RGB{}, true},
+		{"rgb_percent", "rgb(100%, 50%, 0%)", color.RGB{255, 128, 0}, false},
+		{"rgb_percent_decimal", "rgb(0%,12.5%,100%)", color.RGB{0, 32, 255}, false},
+		{"rgb_percent_out_of_range", "rgb(101%,0%,0%)", color.RGB{}, true},
+		{"rgb_alpha", "rgb(255,0,0,0.5)", color.RGB{255, 0, 0}, false},
+		{"rgba", "rgba(0, 0, 255, 50%)", color.RGB{0, 0, 255}, false},
+		{"rgba_invalid_alpha", "rgba(0,0,255,2)", color.RGB{}, true},
+		{"rgb_empty", "rgb()", color.RGB{}, true},
+
+		// HSL and HSV
+		{"hsl_red", "hsl(0, 100%, 50%)", color.RGB{255, 0, 0}, false},
+		{"hsl_orange", "hsl(30,100%,50%)", color.RGB{255, 128, 0}, false},
+		{"hsl_deg_wraps", "hsl(480deg, 100%, 25%)", color.RGB{0, 128, 0}, false},
+		{"hsl_without_percent", "hsl(240, 100, 50)", color.RGB{0, 0, 255}, false},
+		{"hsl_negative_hue", "hsl(-120, 100%, 50%)", color.RGB{0, 0, 255}, false},
+		{"hsla", "hsla(120, 100%, 50%, 0.3)", color.RGB{0, 255, 0}, false},
+		{"hsl_out_of_range", "hsl(0, 120%, 50%)", color.RGB{}, true},
+		{"hsl_bad_hue", "hsl(red, 100%, 50%)", color.RGB{}, true},
+		{"hsl_missing_paren", "hsl(0, 100%, 50%", color.RGB{}, true},
+		{"hsv_yellow", "hsv(60, 100%, 100%)", color.RGB{255, 255, 0}, false},
+		{"hsv_dark", "hsv(0, 100%, 50%)", color.RGB{128, 0, 0}, false},
+		{"hsv_white", "hsv(200, 0%, 100%)", color.RGB{255, 255, 255}, false},
+		{"hsv_no_alpha", "hsv(0, 100%, 100%, 1)", color.RGB{}, true},
+
+		// ANSI indexes
+		{"ansi_basic", "ansi:9", color.RGB{255, 0, 0}, false},
+		{"ansi_cube", "ansi:208", color.RGB{255, 135, 0}, false},
+		{"ansi_gray", "ANSI:244", color.RGB{128, 128, 128}, false},
+		{"ansi_out_of_range", "ansi:256", color.RGB{}, true},
+		{"ansi_not_a_number", "ansi:x", color.RGB{}, true},
 
 		// Empty / whitespace
 		{"empty_spec", "", color.RGB{}, true},
@@ -65,6 +108,33 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParse_Suggestion(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"ornage", "did you mean 'orange'?"},
+		{"Lightblu", "did you mean 'lightblue'?"},
+		{"rebeca purple", "did you mean 'rebeccapurple'?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := color.Parse(tt.spec)
+			if !errors.Is(err, color.ErrInvalidFormat) {
+				t.Fatalf("Parse(%q) error = %v, want ErrInvalidFormat", tt.spec, err)
+			}
+			if !strings.HasSuffix(err.Error(), tt.want) {
+				t.Errorf("Parse(%q) error = %q, want suffix %q", tt.spec, err, tt.want)
+			}
+		})
+	}
+
+	if _, err := color.Parse("zzzzzzzz"); err == nil || strings.Contains(err.Error(), "did you mean") {
+		t.Errorf("Parse(\"zzzzzzzz\") error = %v, want no suggestion", err)
+	}
+}
+
 func TestANSI(t *testing.T) {
 	tests := []struct {
 		name string
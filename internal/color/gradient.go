@@ -0,0 +1,271 @@
+package color
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const minGradientStops = 2
+
+// Space selects the color space a Gradient interpolates in.
+type Space int
+
+const (
+	// SpaceRGB blends the red, green and blue components linearly. It is the
+	// simplest blend, but the middle of a gradient between complementary
+	// colors turns gray.
+	SpaceRGB Space = iota
+	// SpaceHSL blends hue, saturation and lightness, going around the hue
+	// circle the short way, so red to blue passes through magenta.
+	SpaceHSL
+	// SpaceOKLab blends in the perceptually uniform OKLab space, so the
+	// lightness changes evenly along the gradient.
+	SpaceOKLab
+)
+
+// spaceNames maps the names accepted by ParseSpace to color spaces.
+var spaceNames = map[string]Space{
+	"rgb":   SpaceRGB,
+	"hsl":   SpaceHSL,
+	"oklab": SpaceOKLab,
+}
+
+// ParseSpace parses an interpolation space name: rgb, hsl, or oklab.
+//
+// Parameters:
+//   - name: The space name, case-insensitive.
+//
+// Returns:
+//   - The color space.
+//   - An error if the name is not a known space.
+func ParseSpace(name string) (Space, error) {
+	space, ok := spaceNames[strings.ToLower(name)]
+	if !ok {
+		return SpaceRGB, fmt.Errorf("invalid color space: %q\nValid options: rgb, hsl, oklab", name)
+	}
+	return space, nil
+}
+
+// Gradient blends a sequence of colors. The stops are spread evenly from
+// position 0, the first stop, to position 1, the last.
+type Gradient struct {
+	// Stops lists the colors of the gradient, in order; at least two.
+	Stops []RGB
+	// Space is the color space the stops are blended in.
+	Space Space
+}
+
+// ParseGradient parses a comma-separated list of at least two colors, such
+// as "#ff0000,#0000ff" or "red, rgb(0,255,0), blue". Each color takes any
+// form Parse accepts; commas inside parentheses do not separate colors.
+//
+// Parameters:
+//   - spec: The color list.
+//
+// Returns:
+//   - The colors, in order.
+//   - An error if a color is invalid or there are fewer than two.
+func ParseGradient(spec string) ([]RGB, error) {
+	parts := splitList(spec)
+	if len(parts) < minGradientStops {
+		return nil, fmt.Errorf("gradient %q needs at least %d colors: %w", spec, minGradientStops, ErrInvalidFormat)
+	}
+
+	stops := make([]RGB, len(parts))
+	for i, part := range parts {
+		rgb, err := Parse(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gradient color %d: %w", i+1, err)
+		}
+		stops[i] = rgb
+	}
+	return stops, nil
+}
+
+// splitList splits s at the commas that are not inside parentheses.
+func splitList(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, ch := range s {
+		switch ch {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// At returns the color of the gradient at position t.
+//
+// Parameters:
+//   - t: The position, from 0 at the first stop to 1 at the last; values
+//     outside that range are clamped.
+//
+// Returns:
+//   - The blended color; the zero RGB for a gradient without stops.
+func (g Gradient) At(t float64) RGB {
+	switch len(g.Stops) {
+	case 0:
+		return RGB{}
+	case 1:
+		return g.Stops[0]
+	}
+
+	t = math.Max(0, math.Min(1, t))
+	segment := t * float64(len(g.Stops)-1)
+	i := min(int(segment), len(g.Stops)-2)
+	return g.Space.blend(g.Stops[i], g.Stops[i+1], segment-float64(i))
+}
+
+// blend mixes a and b in space s: f = 0 gives a, f = 1 gives b.
+func (s Space) blend(a, b RGB, f float64) RGB {
+	switch s {
+	case SpaceHSL:
+		return blendHSL(a, b, f)
+	case SpaceOKLab:
+		return blendOKLab(a, b, f)
+	default:
+		return RGB{lerpByte(a.R, b.R, f), lerpByte(a.G, b.G, f), lerpByte(a.B, b.B, f)}
+	}
+}
+
+// lerp interpolates linearly between a and b.
+func lerp(a, b, f float64) float64 {
+	return a + (b-a)*f
+}
+
+// lerpByte interpolates linearly between two color components.
+func lerpByte(a, b uint8, f float64) uint8 {
+	return toByte(lerp(float64(a), float64(b), f) / 255)
+}
+
+// toByte converts a component in [0, 1] to a byte, clamping out-of-range values.
+func toByte(v float64) uint8 {
+	return uint8(math.Round(math.Max(0, math.Min(1, v)) * 255))
+}
+
+// blendHSL mixes a and b in HSL, taking the shorter way around the hue circle.
+// A gray has no hue of its own and takes the hue of the other color, so that
+// fading to or from gray does not sweep through unrelated hues.
+func blendHSL(a, b RGB, f float64) RGB {
+	ha, sa, la := toHSL(a)
+	hb, sb, lb := toHSL(b)
+	switch {
+	case sa == 0:
+		ha = hb
+	case sb == 0:
+		hb = ha
+	}
+
+	delta := math.Mod(hb-ha+540, 360) - 180
+	h := math.Mod(ha+delta*f+360, 360)
+	return fromHSL(h, lerp(sa, sb, f), lerp(la, lb, f))
+}
+
+// toHSL converts c to hue in degrees [0, 360) and saturation and lightness
+// in [0, 1].
+func toHSL(c RGB) (h, s, l float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	high, low := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	l = (high + low) / 2
+	if high == low {
+		return 0, 0, l
+	}
+
+	d := high - low
+	s = d / (1 - math.Abs(2*l-1))
+	switch high {
+	case r:
+		h = math.Mod((g-b)/d+6, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	return h * 60, s, l
+}
+
+// fromHSL converts hue in degrees and saturation and lightness in [0, 1] to RGB.
+func fromHSL(h, s, l float64) RGB {
+	chroma := (1 - math.Abs(2*l-1)) * s
+	x := chroma * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - chroma/2
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g = chroma, x
+	case h < 120:
+		r, g = x, chroma
+	case h < 180:
+		g, b = chroma, x
+	case h < 240:
+		g, b = x, chroma
+	case h < 300:
+		r, b = x, chroma
+	default:
+		r, b = chroma, x
+	}
+	return RGB{toByte(r + m), toByte(g + m), toByte(b + m)}
+}
+
+// blendOKLab mixes a and b in the OKLab color space.
+func blendOKLab(a, b RGB, f float64) RGB {
+	la, aa, ba := toOKLab(a)
+	lb, ab, bb := toOKLab(b)
+	return fromOKLab(lerp(la, lb, f), lerp(aa, ab, f), lerp(ba, bb, f))
+}
+
+// toOKLab converts c to OKLab lightness and a, b components, following
+// https://bottosson.github.io/posts/oklab/.
+func toOKLab(c RGB) (l, a, b float64) {
+	r, g, bl := toLinear(c.R), toLinear(c.G), toLinear(c.B)
+
+	lc := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*bl)
+	mc := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*bl)
+	sc := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*bl)
+
+	return 0.2104542553*lc + 0.7936177850*mc - 0.0040720468*sc,
+		1.9779984951*lc - 2.4285922050*mc + 0.4505937099*sc,
+		0.0259040371*lc + 0.7827717662*mc - 0.8086757660*sc
+}
+
+// fromOKLab converts OKLab lightness and a, b components to RGB, clamping
+// colors outside the sRGB gamut.
+func fromOKLab(l, a, b float64) RGB {
+	lc := l + 0.3963377774*a + 0.2158037573*b
+	mc := l - 0.1055613458*a - 0.0638541728*b
+	sc := l - 0.0894841775*a - 1.2914855480*b
+	lc, mc, sc = lc*lc*lc, mc*mc*mc, sc*sc*sc
+
+	return RGB{
+		fromLinear(+4.0767416621*lc - 3.3077115913*mc + 0.2309699292*sc),
+		fromLinear(-1.2684380046*lc + 2.6097574011*mc - 0.3413193965*sc),
+		fromLinear(-0.0041960863*lc - 0.7034186147*mc + 1.7076147010*sc),
+	}
+}
+
+// toLinear converts an sRGB component to linear light in [0, 1].
+func toLinear(v uint8) float64 {
+	c := float64(v) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// fromLinear converts linear light to an sRGB component.
+func fromLinear(c float64) uint8 {
+	if c <= 0.0031308 {
+		return toByte(12.92 * c)
+	}
+	return toByte(1.055*math.Pow(c, 1/2.4) - 0.055)
+}
@@ -0,0 +1,99 @@
+package color_test
+
+import (
+	"testing"
+
+	"github.com/vxanthio/ascii-art-color/internal/color"
+)
+
+func TestParseGradient(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    []color.RGB
+		wantErr bool
+	}{
+		{"two_hex", "#ff0000,#0000ff", []color.RGB{{255, 0, 0}, {0, 0, 255}}, false},
+		{"three_stops_spaced", "red, green , blue", []color.RGB{{255, 0, 0}, {0, 255, 0}, {0, 0, 255}}, false},
+		{"rgb_commas_kept", "rgb(1,2,3),rgb(4, 5, 6)", []color.RGB{{1, 2, 3}, {4, 5, 6}}, false},
+		{"single_color", "red", nil, true},
+		{"empty_stop", "red,,blue", nil, true},
+		{"invalid_color", "red,blurple", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := color.ParseGradient(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseGradient(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseGradient(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ParseGradient(%q) = %v, want %v", tt.spec, got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestParseSpace(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    color.Space
+		wantErr bool
+	}{
+		{"rgb", color.SpaceRGB, false},
+		{"HSL", color.SpaceHSL, false},
+		{"oklab", color.SpaceOKLab, false},
+		{"lab", color.SpaceRGB, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := color.ParseSpace(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSpace(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSpace(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGradient_At(t *testing.T) {
+	redBlue := []color.RGB{{255, 0, 0}, {0, 0, 255}}
+
+	tests := []struct {
+		name     string
+		gradient color.Gradient
+		t        float64
+		want     color.RGB
+	}{
+		{"rgb_start", color.Gradient{Stops: redBlue}, 0, color.RGB{255, 0, 0}},
+		{"rgb_end", color.Gradient{Stops: redBlue}, 1, color.RGB{0, 0, 255}},
+		{"rgb_middle", color.Gradient{Stops: redBlue}, 0.5, color.RGB{128, 0, 128}},
+		{"clamped_below", color.Gradient{Stops: redBlue}, -1, color.RGB{255, 0, 0}},
+		{"clamped_above", color.Gradient{Stops: redBlue}, 2, color.RGB{0, 0, 255}},
+		{"multi_stop_middle", color.Gradient{Stops: []color.RGB{{255, 0, 0}, {0, 255, 0}, {0, 0, 255}}}, 0.5, color.RGB{0, 255, 0}},
+		{"multi_stop_quarter", color.Gradient{Stops: []color.RGB{{0, 0, 0}, {200, 0, 0}, {200, 200, 0}}}, 0.75, color.RGB{200, 100, 0}},
+		{"hsl_short_way", color.Gradient{Stops: redBlue, Space: color.SpaceHSL}, 0.5, color.RGB{255, 0, 255}},
+		{"hsl_from_gray_keeps_hue", color.Gradient{Stops: []color.RGB{{0, 0, 0}, {255, 0, 0}}, Space: color.SpaceHSL}, 0.5, color.RGB{96, 32, 32}},
+		{"oklab_endpoints", color.Gradient{Stops: redBlue, Space: color.SpaceOKLab}, 1, color.RGB{0, 0, 255}},
+		{"oklab_gray_stays_gray", color.Gradient{Stops: []color.RGB{{0, 0, 0}, {255, 255, 255}}, Space: color.SpaceOKLab}, 0.5, color.RGB{99, 99, 99}},
+		{"single_stop", color.Gradient{Stops: redBlue[:1]}, 0.5, color.RGB{255, 0, 0}},
+		{"no_stops", color.Gradient{}, 0.5, color.RGB{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.gradient.At(tt.t); got != tt.want {
+				t.Errorf("At(%v) = %v, want %v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package color
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Mode is the color depth escape sequences are written for. Colors are
+// downgraded to the nearest color the mode supports.
+type Mode int
+
+const (
+	// TrueColor writes 24-bit colors (38;2 and 48;2). It is the zero Mode.
+	TrueColor Mode = iota
+	// Color256 writes colors of the xterm 256-color palette (38;5 and 48;5):
+	// the 6×6×6 color cube and the 24-step gray ramp.
+	Color256
+	// Color16 writes the 16 basic ANSI colors (30–37, 90–97 and their
+	// background counterparts).
+	Color16
+	// NoColor writes no escape sequences at all, attributes included.
+	NoColor
+)
+
+// modeNames maps the names accepted by ParseMode to modes.
+var modeNames = map[string]Mode{
+	"truecolor": TrueColor,
+	"24bit":     TrueColor,
+	"256":       Color256,
+	"16":        Color16,
+	"none":      NoColor,
+}
+
+// ParseMode parses a color mode name: truecolor (or 24bit), 256, 16, or none.
+//
+// Parameters:
+//   - name: The mode name, case-insensitive.
+//
+// Returns:
+//   - The mode.
+//   - An error if the name is not a known mode.
+func ParseMode(name string) (Mode, error) {
+	mode, ok := modeNames[strings.ToLower(name)]
+	if !ok {
+		return TrueColor, fmt.Errorf("invalid color mode: %q\nValid options: auto, truecolor, 256, 16, none", name)
+	}
+	return mode, nil
+}
+
+// Detect determines the color mode of a terminal from the environment, in
+// this order:
+//   - FORCE_COLOR, when set, wins: 0 or false gives NoColor, 2 gives
+//     Color256, 3 gives TrueColor, and any other value Color16.
+//   - NO_COLOR, when set to a non-empty value, gives NoColor.
+//   - Output that is not a terminal, and TERM unset or "dumb", give NoColor.
+//   - COLORTERM set to truecolor or 24bit gives TrueColor.
+//   - TERM containing 256color, as xterm-256color or tmux-256color, gives
+//     Color256; any other TERM gives Color16.
+//
+// Parameters:
+//   - getenv: Looks up an environment variable, as os.Getenv.
+//   - isTerminal: Whether the output is a terminal.
+//
+// Returns:
+//   - The detected mode.
+func Detect(getenv func(string) string, isTerminal bool) Mode {
+	if force, ok := forceMode(getenv("FORCE_COLOR")); ok {
+		return force
+	}
+	if getenv("NO_COLOR") != "" {
+		return NoColor
+	}
+
+	term := strings.ToLower(getenv("TERM"))
+	if !isTerminal || term == "" || term == "dumb" {
+		return NoColor
+	}
+
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return TrueColor
+	}
+	if strings.Contains(term, "256color") {
+		return Color256
+	}
+	return Color16
+}
+
+// forceMode interprets a FORCE_COLOR value; false if it is unset.
+func forceMode(value string) (Mode, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "":
+		return TrueColor, false
+	case "0", "false":
+		return NoColor, true
+	case "2":
+		return Color256, true
+	case "3":
+		return TrueColor, true
+	}
+	return Color16, true
+}
+
+// cubeLevels are the component values of the 6×6×6 cube of the 256-color
+// palette.
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// basicColors are the 16 basic ANSI colors, as xterm draws them.
+var basicColors = [16]RGB{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// Index256 returns the index of the color of the 256-color palette nearest
+// to c, from the color cube (16–231) or the gray ramp (232–255).
+//
+// Parameters:
+//   - c: The color to quantize.
+//
+// Returns:
+//   - The palette index.
+func Index256(c RGB) int {
+	r, g, b := nearestLevel(c.R), nearestLevel(c.G), nearestLevel(c.B)
+	cube := RGB{uint8(cubeLevels[r]), uint8(cubeLevels[g]), uint8(cubeLevels[b])}
+
+	average := (int(c.R) + int(c.G) + int(c.B)) / 3
+	step := max(0, min(23, (average-3)/10))
+	gray := uint8(8 + 10*step)
+
+	if distance(c, RGB{gray, gray, gray}) < distance(c, cube) {
+		return 232 + step
+	}
+	return 16 + 36*r + 6*g + b
+}
+
+// nearestLevel returns the index of the cube level nearest to v.
+func nearestLevel(v uint8) int {
+	best := 0
+	for i, level := range cubeLevels {
+		if abs(int(v)-level) < abs(int(v)-cubeLevels[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// Index16 returns the index of the basic ANSI color nearest to c: 0–7 for
+// the normal colors and 8–15 for the bright ones.
+//
+// Parameters:
+//   - c: The color to quantize.
+//
+// Returns:
+//   - The color index.
+func Index16(c RGB) int {
+	best := 0
+	for i, basic := range basicColors {
+		if distance(c, basic) < distance(c, basicColors[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// distance returns the squared Euclidean distance between two colors.
+func distance(a, b RGB) int {
+	dr, dg, db := int(a.R)-int(b.R), int(a.G)-int(b.G), int(a.B)-int(b.B)
+	return dr*dr + dg*dg + db*db
+}
+
+// abs returns the absolute value of v.
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// colorParams returns the SGR parameters that set c as the foreground, or as
+// the background when background is true, in mode m.
+func (m Mode) colorParams(c RGB, background bool) string {
+	switch m {
+	case Color256:
+		if background {
+			return "48;5;" + strconv.Itoa(Index256(c))
+		}
+		return "38;5;" + strconv.Itoa(Index256(c))
+	case Color16:
+		base := 30
+		if background {
+			base = 40
+		}
+		i := Index16(c)
+		if i >= 8 {
+			return strconv.Itoa(base + 60 + i - 8)
+		}
+		return strconv.Itoa(base + i)
+	default:
+		if background {
+			return fmt.Sprintf("48;2;%d;%d;%d", c.R, c.G, c.B)
+		}
+		return fmt.Sprintf("38;2;%d;%d;%d", c.R, c.G, c.B)
+	}
+}
@@ -0,0 +1,136 @@
+package color_test
+
+import (
+	"testing"
+
+	"github.com/vxanthio/ascii-art-color/internal/color"
+)
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    color.Mode
+		wantErr bool
+	}{
+		{"truecolor", color.TrueColor, false},
+		{"24BIT", color.TrueColor, false},
+		{"256", color.Color256, false},
+		{"16", color.Color16, false},
+		{"none", color.NoColor, false},
+		{"8", color.TrueColor, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := color.ParseMode(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMode(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMode(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        map[string]string
+		isTerminal bool
+		want       color.Mode
+	}{
+		{"truecolor terminal", map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, true, color.TrueColor},
+		{"24bit colorterm", map[string]string{"TERM": "xterm", "COLORTERM": "24bit"}, true, color.TrueColor},
+		{"tmux without truecolor", map[string]string{"TERM": "tmux-256color"}, true, color.Color256},
+		{"basic terminal", map[string]string{"TERM": "xterm"}, true, color.Color16},
+		{"dumb terminal", map[string]string{"TERM": "dumb", "COLORTERM": "truecolor"}, true, color.NoColor},
+		{"no TERM", map[string]string{}, true, color.NoColor},
+		{"not a terminal", map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, false, color.NoColor},
+		{"NO_COLOR", map[string]string{"TERM": "xterm-256color", "NO_COLOR": "1"}, true, color.NoColor},
+		{"empty NO_COLOR ignored", map[string]string{"TERM": "xterm", "NO_COLOR": ""}, true, color.Color16},
+		{"FORCE_COLOR without terminal", map[string]string{"FORCE_COLOR": "1"}, false, color.Color16},
+		{"FORCE_COLOR=2", map[string]string{"FORCE_COLOR": "2"}, false, color.Color256},
+		{"FORCE_COLOR=3 over NO_COLOR", map[string]string{"FORCE_COLOR": "3", "NO_COLOR": "1"}, false, color.TrueColor},
+		{"FORCE_COLOR=0", map[string]string{"FORCE_COLOR": "0", "TERM": "xterm", "COLORTERM": "truecolor"}, true, color.NoColor},
+		{"FORCE_COLOR=false", map[string]string{"FORCE_COLOR": "false", "TERM": "xterm"}, true, color.NoColor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			if got := color.Detect(getenv, tt.isTerminal); got != tt.want {
+				t.Errorf("Detect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndex256(t *testing.T) {
+	tests := []struct {
+		name string
+		rgb  color.RGB
+		want int
+	}{
+		{"black", color.RGB{0, 0, 0}, 16},
+		{"white", color.RGB{255, 255, 255}, 231},
+		{"red", color.RGB{255, 0, 0}, 196},
+		{"orange", color.RGB{255, 165, 0}, 214},
+		{"near cube level", color.RGB{100, 140, 210}, 16 + 36*1 + 6*2 + 4},
+		{"mid gray uses ramp", color.RGB{128, 128, 128}, 244},
+		{"dark gray uses ramp", color.RGB{20, 20, 20}, 233},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := color.Index256(tt.rgb); got != tt.want {
+				t.Errorf("Index256(%v) = %d, want %d", tt.rgb, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndex16(t *testing.T) {
+	tests := []struct {
+		name string
+		rgb  color.RGB
+		want int
+	}{
+		{"black", color.RGB{0, 0, 0}, 0},
+		{"bright red", color.RGB{255, 0, 0}, 9},
+		{"dark red", color.RGB{180, 10, 10}, 1},
+		{"amber to yellow", color.RGB{255, 200, 0}, 3},
+		{"gray", color.RGB{128, 128, 128}, 8},
+		{"white", color.RGB{255, 255, 255}, 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := color.Index16(tt.rgb); got != tt.want {
+				t.Errorf("Index16(%v) = %d, want %d", tt.rgb, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStyle_ANSIModes(t *testing.T) {
+	red, navy := color.RGB{255, 0, 0}, color.RGB{0, 0, 128}
+	style := color.Style{Foreground: &red, Background: &navy, Attrs: color.Bold}
+
+	tests := []struct {
+		mode color.Mode
+		want string
+	}{
+		{color.TrueColor, "\033[1;38;2;255;0;0;48;2;0;0;128m"},
+		{color.Color256, "\033[1;38;5;196;48;5;18m"},
+		{color.Color16, "\033[1;91;44m"},
+		{color.NoColor, ""},
+	}
+
+	for _, tt := range tests {
+		style.Mode = tt.mode
+		if got := style.ANSI(); got != tt.want {
+			t.Errorf("mode %v: ANSI() = %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package color_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vxanthio/ascii-art-color/internal/color"
+)
+
+func TestReadNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]color.RGB
+		wantErr bool
+	}{
+		{
+			name:  "rgb.txt lines",
+			input: "! comment\n\n255 250 250\t\tsnow\n  248 248 255\t\tghost white\n# another comment\n",
+			want: map[string]color.RGB{
+				"snow":        {255, 250, 250},
+				"ghost white": {248, 248, 255},
+			},
+		},
+		{name: "missing name", input: "1 2 3\n", wantErr: true},
+		{name: "component out of range", input: "1 2 300 x\n", wantErr: true},
+		{name: "component not a number", input: "1 b 3 x\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := color.ReadNames(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadNames() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ReadNames() = %v, want %v", got, tt.want)
+			}
+			for name, rgb := range tt.want {
+				if got[name] != rgb {
+					t.Errorf("ReadNames()[%q] = %v, want %v", name, got[name], rgb)
+				}
+			}
+		})
+	}
+}
+
+func TestAddNames(t *testing.T) {
+	color.AddNames(map[string]color.RGB{
+		"Brand Teal": {0, 110, 120},
+		"tomato":     {1, 2, 3},
+	})
+
+	tests := []struct {
+		spec string
+		want color.RGB
+	}{
+		{"brand teal", color.RGB{0, 110, 120}},
+		{"BRANDTEAL", color.RGB{0, 110, 120}},
+		{"tomato", color.RGB{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		got, err := color.Parse(tt.spec)
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q) = %v, %v; want %v", tt.spec, got, err, tt.want)
+		}
+	}
+
+	if name, ok := color.Suggest("brandtel"); !ok || name != "brandteal" {
+		t.Errorf("Suggest(\"brandtel\") = %q, %t; want \"brandteal\"", name, ok)
+	}
+}
+
+func TestNames(t *testing.T) {
+	names := color.Names()
+	if len(names) < 148 {
+		t.Fatalf("len(Names()) = %d, want at least 148", len(names))
+	}
+	for i := 1; i < len(names); i++ {
+		if names[i-1] >= names[i] {
+			t.Fatalf("Names() not sorted or not unique at %q, %q", names[i-1], names[i])
+		}
+	}
+	for _, name := range names {
+		if _, err := color.Parse(name); err != nil {
+			t.Errorf("Parse(%q) error = %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package color
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// palettes holds the built-in palettes, by lowercase name.
+var palettes = map[string][]RGB{
+	// rainbow is the classic seven-color spectrum.
+	"rainbow": {
+		{255, 0, 0}, {255, 127, 0}, {255, 255, 0}, {0, 255, 0},
+		{0, 0, 255}, {75, 0, 130}, {148, 0, 211},
+	},
+	// pride is the six stripes of the pride flag.
+	"pride": {
+		{228, 3, 3}, {255, 140, 0}, {255, 237, 0},
+		{0, 128, 38}, {0, 77, 255}, {117, 7, 135},
+	},
+	// pastel is a set of soft, light tints.
+	"pastel": {
+		{255, 179, 186}, {255, 223, 186}, {255, 255, 186},
+		{186, 255, 201}, {186, 225, 255}, {218, 190, 255},
+	},
+	// solarized is the eight accent colors of Solarized.
+	"solarized": {
+		{181, 137, 0}, {203, 75, 22}, {220, 50, 47}, {211, 54, 130},
+		{108, 113, 196}, {38, 139, 210}, {42, 161, 152}, {133, 153, 0},
+	},
+	// nord is the Aurora and Frost colors of Nord.
+	"nord": {
+		{191, 97, 106}, {208, 135, 112}, {235, 203, 139}, {163, 190, 140},
+		{180, 142, 173}, {143, 188, 187}, {136, 192, 208}, {129, 161, 193},
+	},
+}
+
+// Palette returns the colors of a built-in palette, in cycling order.
+//
+// Parameters:
+//   - name: The palette name, case-insensitive; see PaletteNames.
+//
+// Returns:
+//   - A copy of the palette's colors.
+//   - An error if no palette has that name.
+func Palette(name string) ([]RGB, error) {
+	colors, ok := palettes[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, fmt.Errorf("invalid palette: %q\nValid options: %s", name, strings.Join(PaletteNames(), ", "))
+	}
+	return append([]RGB(nil), colors...), nil
+}
+
+// PaletteNames returns the names of the built-in palettes, sorted.
+//
+// Returns:
+//   - The palette names.
+func PaletteNames() []string {
+	names := make([]string, 0, len(palettes))
+	for name := range palettes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,54 @@
+package color_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vxanthio/ascii-art-color/internal/color"
+)
+
+func TestPalette(t *testing.T) {
+	tests := []struct {
+		name      string
+		wantLen   int
+		wantFirst color.RGB
+		wantErr   bool
+	}{
+		{"rainbow", 7, color.RGB{255, 0, 0}, false},
+		{"Pride", 6, color.RGB{228, 3, 3}, false},
+		{"pastel", 6, color.RGB{255, 179, 186}, false},
+		{"solarized", 8, color.RGB{181, 137, 0}, false},
+		{"nord", 8, color.RGB{191, 97, 106}, false},
+		{"vaporwave", 0, color.RGB{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := color.Palette(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Palette(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if len(got) != tt.wantLen {
+				t.Fatalf("Palette(%q) has %d colors, want %d", tt.name, len(got), tt.wantLen)
+			}
+			if len(got) > 0 && got[0] != tt.wantFirst {
+				t.Errorf("Palette(%q)[0] = %v, want %v", tt.name, got[0], tt.wantFirst)
+			}
+		})
+	}
+}
+
+func TestPalette_ReturnsCopy(t *testing.T) {
+	first, _ := color.Palette("nord")
+	first[0] = color.RGB{}
+	second, _ := color.Palette("nord")
+	if second[0] == (color.RGB{}) {
+		t.Error("modifying a returned palette changed the built-in palette")
+	}
+}
+
+func TestPaletteNames(t *testing.T) {
+	if got, want := strings.Join(color.PaletteNames(), ","), "nord,pastel,pride,rainbow,solarized"; got != want {
+		t.Errorf("PaletteNames() = %s, want %s", got, want)
+	}
+}
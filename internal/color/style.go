@@ -0,0 +1,127 @@
+package color
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Attr is a set of SGR text attributes, combined with |.
+type Attr uint8
+
+const (
+	// Bold draws text bold or bright.
+	Bold Attr = 1 << iota
+	// Dim draws text faint.
+	Dim
+	// Italic draws text in italics; not every terminal supports it.
+	Italic
+	// Underline underlines text.
+	Underline
+	// Blink makes text blink; many terminals ignore it.
+	Blink
+	// Reverse swaps the foreground and background colors.
+	Reverse
+)
+
+// attrCodes lists the attributes with their names and SGR parameters, in the
+// order they are written.
+var attrCodes = []struct {
+	attr Attr
+	name string
+	sgr  int
+}{
+	{Bold, "bold", 1},
+	{Dim, "dim", 2},
+	{Italic, "italic", 3},
+	{Underline, "underline", 4},
+	{Blink, "blink", 5},
+	{Reverse, "reverse", 7},
+}
+
+// ParseAttrs parses a comma-separated list of attribute names: bold, dim,
+// italic, underline, blink, and reverse.
+//
+// Parameters:
+//   - spec: The attribute list, case-insensitive, such as "bold,underline".
+//
+// Returns:
+//   - The combined attributes.
+//   - An error if a name is empty or unknown.
+func ParseAttrs(spec string) (Attr, error) {
+	var attrs Attr
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		found := false
+		for _, a := range attrCodes {
+			if a.name == name {
+				attrs |= a.attr
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("invalid attribute: %q\nValid options: bold, dim, italic, underline, blink, reverse", name)
+		}
+	}
+	return attrs, nil
+}
+
+// Style combines a foreground color, a background color and text attributes.
+// The zero Style changes nothing.
+type Style struct {
+	// Foreground is the text color; nil keeps the terminal's.
+	Foreground *RGB
+	// Background is the color behind the text; nil keeps the terminal's.
+	Background *RGB
+	// Attrs holds the text attributes.
+	Attrs Attr
+	// Mode is the color depth the style is written for; the zero Mode is
+	// TrueColor.
+	Mode Mode
+}
+
+// ANSI returns the escape sequence that switches the terminal to the style.
+//
+// All parts are written as a single SGR sequence: the attributes first, then
+// the foreground and background colors, downgraded to the nearest color of
+// s.Mode. A TrueColor Style with only a foreground color gives the same
+// sequence as the ANSI function.
+//
+// Returns:
+//   - The escape sequence; empty for the zero Style and in NoColor mode.
+func (s Style) ANSI() string {
+	if s.Mode == NoColor {
+		return ""
+	}
+
+	var params []string
+	for _, a := range attrCodes {
+		if s.Attrs&a.attr != 0 {
+			params = append(params, strconv.Itoa(a.sgr))
+		}
+	}
+	if s.Foreground != nil {
+		params = append(params, s.Mode.colorParams(*s.Foreground, false))
+	}
+	if s.Background != nil {
+		params = append(params, s.Mode.colorParams(*s.Background, true))
+	}
+
+	if len(params) == 0 {
+		return ""
+	}
+	return "\033[" + strings.Join(params, ";") + "m"
+}
+
+// WithForeground returns a copy of s with the given foreground color.
+//
+// Parameters:
+//   - rgb: The foreground color.
+//
+// Returns:
+//   - The new Style.
+func (s Style) WithForeground(rgb RGB) Style {
+	s.Foreground = &rgb
+	return s
+}
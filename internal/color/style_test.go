@@ -0,0 +1,60 @@
+package color_test
+
+import (
+	"testing"
+
+	"github.com/vxanthio/ascii-art-color/internal/color"
+)
+
+func TestParseAttrs(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    color.Attr
+		wantErr bool
+	}{
+		{"single", "bold", color.Bold, false},
+		{"several", "Bold, underline,reverse", color.Bold | color.Underline | color.Reverse, false},
+		{"all", "bold,dim,italic,underline,blink,reverse", color.Bold | color.Dim | color.Italic | color.Underline | color.Blink | color.Reverse, false},
+		{"unknown", "bold,strike", 0, true},
+		{"empty", "", 0, true},
+		{"trailing comma", "bold,", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := color.ParseAttrs(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAttrs(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAttrs(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStyle_ANSI(t *testing.T) {
+	red, navy := color.RGB{255, 0, 0}, color.RGB{0, 0, 128}
+
+	tests := []struct {
+		name  string
+		style color.Style
+		want  string
+	}{
+		{"zero", color.Style{}, ""},
+		{"foreground matches ANSI", color.Style{Foreground: &red}, color.ANSI(red)},
+		{"background", color.Style{Background: &navy}, "\033[48;2;0;0;128m"},
+		{"attributes in SGR order", color.Style{Attrs: color.Reverse | color.Bold}, "\033[1;7m"},
+		{"everything", color.Style{Foreground: &red, Background: &navy, Attrs: color.Underline}, "\033[4;38;2;255;0;0;48;2;0;0;128m"},
+		{"with foreground", color.Style{Attrs: color.Dim}.WithForeground(red), "\033[2;38;2;255;0;0m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.style.ANSI(); got != tt.want {
+				t.Errorf("ANSI() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,16 +3,81 @@
 // The package is responsible for mapping character indexes in the original
 // plain text to column offsets in the rendered ASCII art, allowing substrings
 // in the output to be colorized accurately.
+//
+// Several substrings can be colored independently with a list of Rules; where
+// their matches overlap, the rule listed last wins. Each Rule applies a whole
+// Style, foreground and background colors and text attributes together. A
+// Rule matches its substring exactly by default; a Matcher selects characters
+// by case-insensitive, regular-expression, whole-word or n-th occurrence
+// matching instead.
+//
+// Characters and columns are both counted in runes: text character i is the
+// i-th rune of the text, and a width of n covers the next n runes of each
+// ASCII-art line. Multi-byte characters in the text or in the glyphs therefore
+// never shift the coloring.
 package coloring
 
 import (
 	"strings"
+	"unicode/utf8"
 )
 
 // Reset is the ANSI escape sequence used to reset terminal coloring back
 // to the default style after a colored segment.
 const Reset = "\033[0m"
 
+// Rule colors the occurrences of a substring, or the matches of a Matcher.
+type Rule struct {
+	// Substring is the text to color; empty colors the whole text. It is
+	// ignored when Matcher is set.
+	Substring string
+	// Matcher, when set, selects the characters to color in place of
+	// Substring.
+	Matcher Matcher
+	// Style is the style of the matched characters.
+	Style Style
+	// Shader, when set, styles the matched characters cell by cell in place
+	// of Style, as for a gradient.
+	Shader Shader
+	// Palette, when set and Shader is not, styles the matched characters
+	// with its styles in turn, in place of Style; Cycle selects what
+	// advances to the next style.
+	Palette []Style
+	// Cycle selects whether Palette advances per glyph, word or row.
+	Cycle Cycle
+}
+
+// Style is the look of colored text: its colors and attributes, written to
+// the terminal as an ANSI escape sequence. color.Style implements it, and
+// Code adapts a ready-made escape sequence.
+type Style interface {
+	// ANSI returns the escape sequence that switches the terminal to the
+	// style; empty leaves the text unstyled.
+	ANSI() string
+}
+
+// Code is a Style given directly as its ANSI escape sequence.
+type Code string
+
+// ANSI implements Style.
+func (c Code) ANSI() string {
+	return string(c)
+}
+
+// ansi returns the escape sequence of style; empty for a nil style.
+func ansi(style Style) string {
+	if style == nil {
+		return ""
+	}
+	return style.ANSI()
+}
+
+// Shader returns the style of one cell of rendered ASCII art.
+//
+// row and column locate the cell, counting from zero, in art of rows rows and
+// columns columns, so that a shader can spread a gradient across the art.
+type Shader func(row, column, rows, columns int) Style
+
 // ApplyColor applies ANSI color codes to matching substrings in rendered ASCII art.
 //
 // It determines which characters in the input text should be colored, maps those
@@ -24,7 +89,7 @@ const Reset = "\033[0m"
 //   - text: original plain text used to generate the ASCII art
 //   - substring: substring to colorize; if empty, the entire text is colored
 //   - colorCode: ANSI escape sequence that starts the coloring
-//   - charWidths: column widths corresponding to each character in text
+//   - charWidths: column widths corresponding to each character (rune) in text
 //
 // Returns:
 //   - A new slice of strings containing the colored ASCII art
@@ -39,11 +104,159 @@ func ApplyColor(
 		return asciiArt
 	}
 
-	positions := findPositions(text, substring)
+	return ApplyRules(asciiArt, text, []Rule{{Substring: substring, Style: Code(colorCode)}}, charWidths)
+}
+
+// ApplyRules applies several color rules to rendered ASCII art.
+//
+// Each character of text takes the style of the last rule whose substring
+// covers it, so rules listed later take precedence where matches overlap.
+// Styles are applied whole: at every boundary between styles the previous
+// one is reset before the next starts, as described for ApplyCodes, so a
+// background or attribute never carries over to the next run. The cells of
+// characters taken by a rule with a Shader are colored one by one, with the
+// art measured as len(asciiArt) rows by the sum of charWidths columns. Palette
+// rules cycle through their codes as described for Cycle.
+//
+// Parameters:
+//   - asciiArt: rendered ASCII art lines to be colorized
+//   - text: original plain text used to generate the ASCII art
+//   - rules: the color rules, from lowest to highest precedence
+//   - charWidths: column widths corresponding to each character (rune) in text
+//
+// Returns:
+//   - A new slice of strings containing the colored ASCII art
+func ApplyRules(asciiArt []string, text string, rules []Rule, charWidths []int) []string {
+	if len(asciiArt) == 0 || len(charWidths) == 0 || len(text) == 0 || len(rules) == 0 {
+		return asciiArt
+	}
+
+	owners := owners(text, rules)
+	codes := characterCodes(text, rules, owners)
+	if !hasCellRule(rules) {
+		return ApplyCodes(asciiArt, codes, charWidths)
+	}
+
+	columns := 0
+	for _, width := range charWidths {
+		columns += width
+	}
+
 	result := make([]string, len(asciiArt))
+	for row, line := range asciiArt {
+		result[row] = paintLine(line, charWidths[:min(len(charWidths), len(owners))], func(idx, column int) string {
+			if owners[idx] < 0 {
+				return ""
+			}
+			rule := rules[owners[idx]]
+			switch {
+			case rule.Shader != nil:
+				return ansi(rule.Shader(row, column, len(asciiArt), columns))
+			case len(rule.Palette) > 0 && rule.Cycle == CycleRow:
+				return ansi(rule.Palette[row%len(rule.Palette)])
+			}
+			return codes[idx]
+		})
+	}
+	return result
+}
+
+// Codes resolves color rules to the escape sequence of each character of
+// text. The last rule matching a character wins. Palette rules that cycle per
+// glyph or word give each character its style from the palette; rules that
+// style cell by cell, with a Shader or a palette cycling per row, give their
+// Style.
+//
+// Parameters:
+//   - text: The text the rules apply to.
+//   - rules: The color rules, from lowest to highest precedence.
+//
+// Returns:
+//   - One escape sequence per rune of text; empty for uncolored characters.
+func Codes(text string, rules []Rule) []string {
+	return characterCodes(text, rules, owners(text, rules))
+}
+
+// characterCodes returns the escape sequence of each character of text, given
+// the rule that owns it: its palette style for palettes cycling per glyph or
+// word, and its Style otherwise.
+func characterCodes(text string, rules []Rule, owners []int) []string {
+	codes := make([]string, len(owners))
+	steps := paletteSteps(text, rules, owners)
+	for i, owner := range owners {
+		if owner < 0 {
+			continue
+		}
+		rule := rules[owner]
+		if len(rule.Palette) > 0 && rule.Cycle != CycleRow {
+			codes[i] = ansi(rule.Palette[steps[i]%len(rule.Palette)])
+			continue
+		}
+		codes[i] = ansi(rule.Style)
+	}
+	return codes
+}
+
+// owners returns, for each rune of text, the index of the last rule that
+// covers it, or -1 if no rule does.
+func owners(text string, rules []Rule) []int {
+	owners := make([]int, utf8.RuneCountInString(text))
+	for i := range owners {
+		owners[i] = -1
+	}
+	for r, rule := range rules {
+		for i, matched := range rule.positions(text) {
+			if matched {
+				owners[i] = r
+			}
+		}
+	}
+	return owners
+}
+
+// hasCellRule reports whether any rule colors cell by cell: with a Shader,
+// or with a palette cycling per row.
+func hasCellRule(rules []Rule) bool {
+	for _, rule := range rules {
+		if rule.Shader != nil || (len(rule.Palette) > 0 && rule.Cycle == CycleRow) {
+			return true
+		}
+	}
+	return false
+}
 
+// positions reports which characters of text the rule colors.
+func (r Rule) positions(text string) []bool {
+	if r.Matcher != nil {
+		return Mask(text, r.Matcher)
+	}
+	return Positions(text, r.Substring)
+}
+
+// ApplyCodes colors each character of the rendered ASCII art with its own
+// ANSI escape sequence.
+//
+// codes holds one escape sequence per character (rune) of the original text;
+// an empty string leaves the character uncolored. A sequence is opened where a
+// run of characters with the same code starts, and Reset is written where the
+// run ends, so adjacent characters with different codes are separated by a
+// Reset followed by the next code.
+//
+// Parameters:
+//   - asciiArt: rendered ASCII art lines to be colorized
+//   - codes: ANSI escape sequence for each character in the original text
+//   - charWidths: column widths corresponding to each character (rune) in the text
+//
+// Returns:
+//   - A new slice of strings containing the colored ASCII art
+func ApplyCodes(asciiArt []string, codes []string, charWidths []int) []string {
+	if len(asciiArt) == 0 || len(charWidths) == 0 || len(codes) == 0 {
+		return asciiArt
+	}
+
+	result := make([]string, len(asciiArt))
 	for i, line := range asciiArt {
-		result[i] = colorLine(line, positions, charWidths, colorCode)
+		result[i] = colorLine(line, codes, charWidths)
 	}
 
 	return result
@@ -51,76 +264,88 @@ func ApplyColor(
 
 // colorLine applies ANSI color codes to a single line of ASCII art.
 //
-// It uses the boolean positions slice to determine where coloring should
-// start and end, based on character boundaries defined by charWidths.
-// This function assumes that positions corresponds to indexes in the
-// original text, not byte offsets in the ASCII art.
+// It walks the character boundaries defined by charWidths and switches
+// escape sequences wherever the code of one character differs from the
+// previous one. Codes and charWidths are indexed by the runes of the original
+// text, and widths are measured in runes of line, not bytes.
 //
 // Parameters:
 //   - line: The ASCII art line to colorize.
-//   - positions: Boolean slice marking which characters should be colored.
+//   - codes: ANSI escape sequence for each character; empty means uncolored.
 //   - charWidths: Column widths for each character in the original text.
-//   - colorCode: ANSI escape sequence for the desired color.
 //
 // Returns:
 //   - The colorized line with ANSI color codes inserted.
-func colorLine(
-	line string,
-	positions []bool,
-	charWidths []int,
-	colorCode string,
-) string {
+func colorLine(line string, codes []string, charWidths []int) string {
+	return paintLine(line, charWidths[:min(len(charWidths), len(codes))], func(idx, _ int) string {
+		return codes[idx]
+	})
+}
+
+// paintLine applies an ANSI escape sequence to each cell of a line of ASCII
+// art, switching sequences only where the code changes from one cell to the
+// next. Columns past the characters of charWidths are left uncolored.
+//
+// Parameters:
+//   - line: The ASCII art line to colorize.
+//   - charWidths: Column widths for each character in the original text.
+//   - code: Returns the escape sequence of the cell at column, which belongs
+//     to character idx; empty means uncolored.
+//
+// Returns:
+//   - The colorized line with ANSI color codes inserted.
+func paintLine(line string, charWidths []int, code func(idx, column int) string) string {
 	var builder strings.Builder
+	columns := []rune(line)
 	offset := 0
+	current := ""
 
 	for idx, width := range charWidths {
-		if offset >= len(line) {
+		if offset >= len(columns) {
 			break
 		}
 
-		end := offset + width
-		if end > len(line) {
-			end = len(line)
-		}
-
-		isStart := positions[idx] && (idx == 0 || !positions[idx-1])
-		isEnd := positions[idx] && (idx == len(positions)-1 || !positions[idx+1])
-
-		if isStart {
-			builder.WriteString(colorCode)
-		}
-
-		builder.WriteString(line[offset:end])
-
-		if isEnd {
-			builder.WriteString(Reset)
+		end := min(offset+width, len(columns))
+		for column := offset; column < end; column++ {
+			if next := code(idx, column); next != current {
+				if current != "" {
+					builder.WriteString(Reset)
+				}
+				builder.WriteString(next)
+				current = next
+			}
+			builder.WriteRune(columns[column])
 		}
-
 		offset = end
 	}
 
-	if offset < len(line) {
-		builder.WriteString(line[offset:])
+	if current != "" {
+		builder.WriteString(Reset)
+	}
+
+	if offset < len(columns) {
+		builder.WriteString(string(columns[offset:]))
 	}
 
 	return builder.String()
 }
 
-// findPositions returns a boolean slice indicating which character indexes
-// in text are part of a substring match.
+// Positions reports which characters of text are part of a substring match.
 //
-// Each index set to true represents a character that should be colorized.
-// If substring is empty, all positions in text are marked true, indicating
-// that the entire text should be colored.
+// The result has one entry per rune of text, so multi-byte characters take a
+// single slot. Each index set to true represents a character that should be
+// colorized. Overlapping matches are all marked. If substring is empty, all
+// positions are marked true, indicating that the entire text should be colored.
 //
 // Parameters:
 //   - text: The text to search for substring matches.
 //   - substring: The substring to find; if empty, all positions are marked true.
 //
 // Returns:
-//   - A boolean slice with the same length as text, with true for matched positions.
-func findPositions(text string, substring string) []bool {
-	positions := make([]bool, len(text))
+//   - A boolean slice with one entry per rune of text, true for matched positions.
+func Positions(text string, substring string) []bool {
+	runes := []rune(text)
+	positions := make([]bool, len(runes))
 
 	if len(substring) == 0 {
 		for i := range positions {
@@ -129,22 +354,5 @@ func findPositions(text string, substring string) []bool {
 		return positions
 	}
 
-	for i := 0; i <= len(text)-len(substring); i++ {
-		match := true
-
-		for p := 0; p < len(substring); p++ {
-			if text[i+p] != substring[p] {
-				match = false
-				break
-			}
-		}
-
-		if match {
-			for p := 0; p < len(substring); p++ {
-				positions[i+p] = true
-			}
-		}
-	}
-
-	return positions
+	return Mask(text, Exact(substring))
 }
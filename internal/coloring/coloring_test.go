@@ -1,10 +1,11 @@
 package coloring_test
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
-	"ascii-art-color/internal/coloring"
+	"github.com/vxanthio/ascii-art-color/internal/coloring"
 )
 
 func TestApplyColor_AdvancedCases(t *testing.T) {
@@ -138,3 +139,221 @@ func TestApplyColor_FinalCoverage(t *testing.T) {
 		}
 	})
 }
+
+func TestPositions(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		substring string
+		want      []bool
+	}{
+		{"empty substring marks all", "aé", "", []bool{true, true}},
+		{"ascii match", "abc", "b", []bool{false, true, false}},
+		{"multi-byte text", "Café!", "!", []bool{false, false, false, false, true}},
+		{"multi-byte substring", "Café au lait", "é", []bool{false, false, false, true, false, false, false, false, false, false, false, false}},
+		{"overlapping matches", "€€€", "€€", []bool{true, true, true}},
+		{"no match", "abc", "é", []bool{false, false, false}},
+		{"empty text", "", "a", []bool{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := coloring.Positions(tt.text, tt.substring)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Positions(%q, %q) = %v, want %v", tt.text, tt.substring, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Positions(%q, %q) = %v, want %v", tt.text, tt.substring, got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestApplyColor_MultiByte(t *testing.T) {
+	colorCode := "\033[31m"
+
+	tests := []struct {
+		name      string
+		art       []string
+		text      string
+		substring string
+		widths    []int
+		want      string
+	}{
+		{
+			name:      "multi-byte character before match",
+			art:       []string{"C|é|!"},
+			text:      "Cé!",
+			substring: "!",
+			widths:    []int{1, 3, 1},
+			want:      "C|é|" + colorCode + "!" + coloring.Reset,
+		},
+		{
+			name:      "multi-byte character matched",
+			art:       []string{"C|é|!"},
+			text:      "Cé!",
+			substring: "é",
+			widths:    []int{1, 3, 1},
+			want:      "C" + colorCode + "|é|" + coloring.Reset + "!",
+		},
+		{
+			name:      "multi-byte glyph rows",
+			art:       []string{"╔═╗╔═╗"},
+			text:      "oo",
+			substring: "o",
+			widths:    []int{3, 3},
+			want:      colorCode + "╔═╗╔═╗" + coloring.Reset,
+		},
+		{
+			name:      "second of two multi-byte glyphs",
+			art:       []string{"€€€ab"},
+			text:      "€a",
+			substring: "a",
+			widths:    []int{3, 2},
+			want:      "€€€" + colorCode + "ab" + coloring.Reset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := coloring.ApplyColor(tt.art, tt.text, tt.substring, colorCode, tt.widths)
+			if got[0] != tt.want {
+				t.Errorf("got %q, want %q", got[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyCodes(t *testing.T) {
+	red, green := "\033[31m", "\033[32m"
+
+	tests := []struct {
+		name   string
+		art    []string
+		codes  []string
+		widths []int
+		want   string
+	}{
+		{"no codes", []string{"aabb"}, []string{"", ""}, []int{2, 2}, "aabb"},
+		{"one run", []string{"aabbcc"}, []string{"", red, red}, []int{2, 2, 2}, "aa" + red + "bbcc" + coloring.Reset},
+		{"adjacent runs", []string{"aabb"}, []string{red, green}, []int{2, 2}, red + "aa" + coloring.Reset + green + "bb" + coloring.Reset},
+		{"separated runs", []string{"abc"}, []string{red, "", green}, []int{1, 1, 1}, red + "a" + coloring.Reset + "b" + green + "c" + coloring.Reset},
+		{"closes run cut short", []string{"ab"}, []string{red, red}, []int{1, 5}, red + "ab" + coloring.Reset},
+		{"fewer codes than widths", []string{"abc"}, []string{red}, []int{1, 1, 1}, red + "a" + coloring.Reset + "bc"},
+		{"multi-byte columns", []string{"╔═╗x"}, []string{green, red}, []int{3, 1}, green + "╔═╗" + coloring.Reset + red + "x" + coloring.Reset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := coloring.ApplyCodes(tt.art, tt.codes, tt.widths)
+			if got[0] != tt.want {
+				t.Errorf("got %q, want %q", got[0], tt.want)
+			}
+		})
+	}
+
+	if got := coloring.ApplyCodes([]string{"ab"}, nil, []int{1, 1}); got[0] != "ab" {
+		t.Errorf("expected art unchanged without codes, got %q", got[0])
+	}
+}
+
+func TestCodes(t *testing.T) {
+	red, green := "\033[31m", "\033[32m"
+
+	tests := []struct {
+		name  string
+		text  string
+		rules []coloring.Rule
+		want  []string
+	}{
+		{"no rules", "ab", nil, []string{"", ""}},
+		{"independent substrings", "ERR OK", []coloring.Rule{{Substring: "ERR", Style: coloring.Code(red)}, {Substring: "OK", Style: coloring.Code(green)}}, []string{red, red, red, "", green, green}},
+		{"later rule wins", "abc", []coloring.Rule{{Substring: "", Style: coloring.Code(red)}, {Substring: "b", Style: coloring.Code(green)}}, []string{red, green, red}},
+		{"earlier rule hidden", "abc", []coloring.Rule{{Substring: "b", Style: coloring.Code(green)}, {Substring: "", Style: coloring.Code(red)}}, []string{red, red, red}},
+		{"partial overlap", "abcd", []coloring.Rule{{Substring: "abc", Style: coloring.Code(red)}, {Substring: "cd", Style: coloring.Code(green)}}, []string{red, red, green, green}},
+		{"multi-byte text", "éa", []coloring.Rule{{Substring: "a", Style: coloring.Code(green)}}, []string{"", green}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := coloring.Codes(tt.text, tt.rules)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Codes(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyRules(t *testing.T) {
+	red, green := "\033[31m", "\033[32m"
+	rules := []coloring.Rule{{Substring: "E", Style: coloring.Code(red)}, {Substring: "O", Style: coloring.Code(green)}}
+
+	got := coloring.ApplyRules([]string{"EEOO__", "eeoo__"}, "EO_", rules, []int{2, 2, 2})
+	want := []string{
+		red + "EE" + coloring.Reset + green + "OO" + coloring.Reset + "__",
+		red + "ee" + coloring.Reset + green + "oo" + coloring.Reset + "__",
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	art := []string{"ab"}
+	if got := coloring.ApplyRules(art, "a", nil, []int{2}); got[0] != "ab" {
+		t.Errorf("expected art unchanged without rules, got %q", got[0])
+	}
+}
+
+func TestApplyRules_Shader(t *testing.T) {
+	red := "\033[31m"
+	// The shader encodes each cell's coordinates so the test can check them.
+	shader := func(row, column, rows, columns int) coloring.Style {
+		return coloring.Code(fmt.Sprintf("<%d,%d/%dx%d>", row, column, rows, columns))
+	}
+
+	tests := []struct {
+		name  string
+		rules []coloring.Rule
+		want  []string
+	}{
+		{
+			name:  "whole text",
+			rules: []coloring.Rule{{Shader: shader}},
+			want: []string{
+				"<0,0/2x3>a" + coloring.Reset + "<0,1/2x3>b" + coloring.Reset + "<0,2/2x3>c" + coloring.Reset,
+				"<1,0/2x3>d" + coloring.Reset + "<1,1/2x3>e" + coloring.Reset + "<1,2/2x3>f" + coloring.Reset,
+			},
+		},
+		{
+			name:  "substring keeps art coordinates",
+			rules: []coloring.Rule{{Substring: "Y", Shader: shader}},
+			want: []string{
+				"a<0,1/2x3>b" + coloring.Reset + "<0,2/2x3>c" + coloring.Reset,
+				"d<1,1/2x3>e" + coloring.Reset + "<1,2/2x3>f" + coloring.Reset,
+			},
+		},
+		{
+			name:  "flat rule over shader",
+			rules: []coloring.Rule{{Shader: shader}, {Substring: "Y", Style: coloring.Code(red)}},
+			want: []string{
+				"<0,0/2x3>a" + coloring.Reset + red + "bc" + coloring.Reset,
+				"<1,0/2x3>d" + coloring.Reset + red + "ef" + coloring.Reset,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := coloring.ApplyRules([]string{"abc", "def"}, "XY", tt.rules, []int{1, 2})
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("line %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package coloring
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Match is a run of matched characters of a text, as rune offsets: the
+// characters from Start up to, but not including, End.
+type Match struct {
+	Start int
+	End   int
+}
+
+// Matcher finds the characters of a text that a rule colors.
+//
+// Matchers can be combined: WholeWord and Nth filter the matches of another
+// Matcher, so "the second whole-word, case-insensitive occurrence of go" is
+// Nth(WholeWord(IgnoreCase("go")), 2).
+type Matcher interface {
+	// Find returns the matches in text, ordered by their start.
+	Find(text string) []Match
+}
+
+// Mask reports which characters of text are part of a match of m, in the
+// form ApplyCodes and colorLine consume.
+//
+// Parameters:
+//   - text: The text to search.
+//   - m: The matcher to search with.
+//
+// Returns:
+//   - A boolean slice with one entry per rune of text, true for matched positions.
+func Mask(text string, m Matcher) []bool {
+	mask := make([]bool, utf8.RuneCountInString(text))
+	for _, match := range m.Find(text) {
+		for i := max(match.Start, 0); i < match.End && i < len(mask); i++ {
+			mask[i] = true
+		}
+	}
+	return mask
+}
+
+// exactMatcher matches a substring rune for rune.
+type exactMatcher struct {
+	target []rune
+	equal  func(a, b rune) bool
+}
+
+// Exact returns a Matcher for every occurrence of substring, compared rune for
+// rune. Overlapping occurrences are all matched, as in Positions. An empty
+// substring matches nothing.
+//
+// Parameters:
+//   - substring: The text to find.
+//
+// Returns:
+//   - The Matcher.
+func Exact(substring string) Matcher {
+	return exactMatcher{target: []rune(substring), equal: func(a, b rune) bool { return a == b }}
+}
+
+// IgnoreCase returns a Matcher like Exact that compares characters without
+// regard to case, using Unicode simple case folding: "go" matches "Go", "GO"
+// and "gO".
+//
+// Parameters:
+//   - substring: The text to find.
+//
+// Returns:
+//   - The Matcher.
+func IgnoreCase(substring string) Matcher {
+	return exactMatcher{target: []rune(substring), equal: foldEqual}
+}
+
+// Find implements Matcher.
+func (m exactMatcher) Find(text string) []Match {
+	if len(m.target) == 0 {
+		return nil
+	}
+
+	runes := []rune(text)
+	var matches []Match
+	for i := 0; i <= len(runes)-len(m.target); i++ {
+		match := true
+		for p, want := range m.target {
+			if !m.equal(runes[i+p], want) {
+				match = false
+				break
+			}
+		}
+		if match {
+			matches = append(matches, Match{Start: i, End: i + len(m.target)})
+		}
+	}
+	return matches
+}
+
+// foldEqual reports whether a and b are equal under Unicode simple case
+// folding.
+func foldEqual(a, b rune) bool {
+	if a == b {
+		return true
+	}
+	for r := unicode.SimpleFold(a); r != a; r = unicode.SimpleFold(r) {
+		if r == b {
+			return true
+		}
+	}
+	return false
+}
+
+// regexpMatcher matches a regular expression.
+type regexpMatcher struct {
+	re *regexp.Regexp
+}
+
+// Regexp returns a Matcher for the non-overlapping, non-empty matches of re,
+// as found by re.FindAllStringIndex. Empty matches, such as those of `x*`
+// between two other characters, color nothing and are skipped.
+//
+// Parameters:
+//   - re: The regular expression to match.
+//
+// Returns:
+//   - The Matcher.
+func Regexp(re *regexp.Regexp) Matcher {
+	return regexpMatcher{re: re}
+}
+
+// Find implements Matcher.
+func (m regexpMatcher) Find(text string) []Match {
+	var matches []Match
+	for _, loc := range m.re.FindAllStringIndex(text, -1) {
+		if loc[0] == loc[1] {
+			continue
+		}
+		start := utf8.RuneCountInString(text[:loc[0]])
+		end := start + utf8.RuneCountInString(text[loc[0]:loc[1]])
+		matches = append(matches, Match{Start: start, End: end})
+	}
+	return matches
+}
+
+// wholeWordMatcher keeps the matches that form whole words.
+type wholeWordMatcher struct {
+	m Matcher
+}
+
+// WholeWord returns a Matcher for the matches of m that are not part of a
+// longer word: the characters just before and just after a match, if any, must
+// not be word characters. Letters, digits and the underscore are word
+// characters, so "cat" matches in "cat, dog" but not in "concat" or "cat_1".
+//
+// Parameters:
+//   - m: The matcher whose matches to filter.
+//
+// Returns:
+//   - The Matcher.
+func WholeWord(m Matcher) Matcher {
+	return wholeWordMatcher{m: m}
+}
+
+// Find implements Matcher.
+func (m wholeWordMatcher) Find(text string) []Match {
+	runes := []rune(text)
+	var matches []Match
+	for _, match := range m.m.Find(text) {
+		if match.Start > 0 && isWordRune(runes[match.Start-1]) {
+			continue
+		}
+		if match.End < len(runes) && isWordRune(runes[match.End]) {
+			continue
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+// isWordRune reports whether r is a word character: a letter, a digit, or
+// the underscore.
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// nthMatcher keeps a single match.
+type nthMatcher struct {
+	m Matcher
+	n int
+}
+
+// Nth returns a Matcher for the n-th match of m only, counting from 1. It
+// matches nothing when m has fewer than n matches or n is less than 1.
+//
+// Parameters:
+//   - m: The matcher whose matches to count.
+//   - n: The position of the match to keep, starting at 1.
+//
+// Returns:
+//   - The Matcher.
+func Nth(m Matcher, n int) Matcher {
+	return nthMatcher{m: m, n: n}
+}
+
+// Find implements Matcher.
+func (m nthMatcher) Find(text string) []Match {
+	matches := m.m.Find(text)
+	if m.n < 1 || m.n > len(matches) {
+		return nil
+	}
+	return matches[m.n-1 : m.n]
+}
+
+// MatchMode selects how a rule's pattern is interpreted by NewMatcher.
+type MatchMode int
+
+const (
+	// MatchExact matches the pattern as literal text.
+	MatchExact MatchMode = iota
+	// MatchWord matches the pattern as literal text forming whole words.
+	MatchWord
+	// MatchRegexp matches the pattern as a regular expression in the syntax
+	// of the regexp package.
+	MatchRegexp
+)
+
+// matchModeNames maps the names accepted by ParseMatchMode to match modes.
+var matchModeNames = map[string]MatchMode{
+	"exact": MatchExact,
+	"word":  MatchWord,
+	"regex": MatchRegexp,
+}
+
+// ParseMatchMode parses a match mode name: exact, word, or regex.
+//
+// Parameters:
+//   - name: The mode name, case-insensitive.
+//
+// Returns:
+//   - The match mode.
+//   - An error if the name is not a known mode.
+func ParseMatchMode(name string) (MatchMode, error) {
+	mode, ok := matchModeNames[strings.ToLower(name)]
+	if !ok {
+		return MatchExact, fmt.Errorf("invalid match mode: %q\nValid options: exact, word, regex", name)
+	}
+	return mode, nil
+}
+
+// MatchOptions configures the Matcher built by NewMatcher. The zero value
+// matches every exact, case-sensitive occurrence.
+type MatchOptions struct {
+	// Mode selects how the pattern is interpreted.
+	Mode MatchMode
+	// IgnoreCase matches without regard to case.
+	IgnoreCase bool
+	// Occurrence keeps only the n-th match, counting from 1; zero keeps all.
+	Occurrence int
+}
+
+// NewMatcher builds the Matcher for pattern described by opts.
+//
+// Parameters:
+//   - pattern: The text or regular expression to match.
+//   - opts: The match mode, case sensitivity and occurrence.
+//
+// Returns:
+//   - The Matcher.
+//   - An error if pattern is not a valid regular expression in MatchRegexp
+//     mode, or if opts.Occurrence is negative.
+func NewMatcher(pattern string, opts MatchOptions) (Matcher, error) {
+	if opts.Occurrence < 0 {
+		return nil, fmt.Errorf("invalid occurrence %d: must not be negative", opts.Occurrence)
+	}
+
+	var m Matcher
+	switch opts.Mode {
+	case MatchRegexp:
+		expr := pattern
+		if opts.IgnoreCase {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+		}
+		m = Regexp(re)
+	default:
+		if opts.IgnoreCase {
+			m = IgnoreCase(pattern)
+		} else {
+			m = Exact(pattern)
+		}
+		if opts.Mode == MatchWord {
+			m = WholeWord(m)
+		}
+	}
+
+	if opts.Occurrence > 0 {
+		m = Nth(m, opts.Occurrence)
+	}
+	return m, nil
+}
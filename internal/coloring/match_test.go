@@ -0,0 +1,129 @@
+package coloring_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/vxanthio/ascii-art-color/internal/coloring"
+)
+
+// maskString renders a mask as a string of '^' for matched and '.' for
+// unmatched characters, for readable test failures.
+func maskString(mask []bool) string {
+	b := make([]byte, len(mask))
+	for i, matched := range mask {
+		b[i] = '.'
+		if matched {
+			b[i] = '^'
+		}
+	}
+	return string(b)
+}
+
+func TestMatchers(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		matcher coloring.Matcher
+		want    string
+	}{
+		{"exact", "Go go GO", coloring.Exact("go"), "...^^..."},
+		{"exact overlapping", "aaa", coloring.Exact("aa"), "^^^"},
+		{"exact empty matches nothing", "ab", coloring.Exact(""), ".."},
+		{"ignore case", "Go go GO", coloring.IgnoreCase("go"), "^^.^^.^^"},
+		{"ignore case multi-byte", "ÉCOLE école", coloring.IgnoreCase("éc"), "^^....^^..."},
+		{"regexp digits", "a1b22", coloring.Regexp(regexp.MustCompile(`\d`)), ".^.^^"},
+		{"regexp version", "go v1.22 ok", coloring.Regexp(regexp.MustCompile(`v\d+\.\d+`)), "...^^^^^..."},
+		{"regexp multi-byte offsets", "é12é", coloring.Regexp(regexp.MustCompile(`\d+`)), ".^^."},
+		{"regexp empty matches skipped", "abc", coloring.Regexp(regexp.MustCompile(`x*`)), "..."},
+		{"whole word", "cat concat cat_1 cat.", coloring.WholeWord(coloring.Exact("cat")), "^^^..............^^^."},
+		{"whole word multi-byte neighbor", "écat cat", coloring.WholeWord(coloring.Exact("cat")), ".....^^^"},
+		{"nth", "ab ab ab", coloring.Nth(coloring.Exact("ab"), 2), "...^^..."},
+		{"nth past last", "ab", coloring.Nth(coloring.Exact("ab"), 2), ".."},
+		{"nth zero", "ab", coloring.Nth(coloring.Exact("ab"), 0), ".."},
+		{"nth whole word ignore case", "Go gopher GO go", coloring.Nth(coloring.WholeWord(coloring.IgnoreCase("go")), 2), "..........^^..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := maskString(coloring.Mask(tt.text, tt.matcher)); got != tt.want {
+				t.Errorf("Mask(%q) = %s, want %s", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewMatcher(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		opts    coloring.MatchOptions
+		text    string
+		want    string
+	}{
+		{"zero value is exact", "go", coloring.MatchOptions{}, "Go go", "...^^"},
+		{"ignore case", "go", coloring.MatchOptions{IgnoreCase: true}, "Go go", "^^.^^"},
+		{"word", "go", coloring.MatchOptions{Mode: coloring.MatchWord}, "go gopher", "^^......."},
+		{"regexp", `o+`, coloring.MatchOptions{Mode: coloring.MatchRegexp}, "foo Oo", ".^^..^"},
+		{"regexp ignore case", `o+`, coloring.MatchOptions{Mode: coloring.MatchRegexp, IgnoreCase: true}, "foo Oo", ".^^.^^"},
+		{"occurrence", "o", coloring.MatchOptions{Occurrence: 3}, "foo Oo", ".....^"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := coloring.NewMatcher(tt.pattern, tt.opts)
+			if err != nil {
+				t.Fatalf("NewMatcher(%q) error: %v", tt.pattern, err)
+			}
+			if got := maskString(coloring.Mask(tt.text, m)); got != tt.want {
+				t.Errorf("Mask(%q) = %s, want %s", tt.text, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := coloring.NewMatcher("(", coloring.MatchOptions{Mode: coloring.MatchRegexp}); err == nil {
+		t.Error("expected error for invalid regular expression")
+	}
+	if _, err := coloring.NewMatcher("a", coloring.MatchOptions{Occurrence: -1}); err == nil {
+		t.Error("expected error for negative occurrence")
+	}
+}
+
+func TestParseMatchMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    coloring.MatchMode
+		wantErr bool
+	}{
+		{"exact", coloring.MatchExact, false},
+		{"WORD", coloring.MatchWord, false},
+		{"regex", coloring.MatchRegexp, false},
+		{"glob", coloring.MatchExact, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := coloring.ParseMatchMode(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMatchMode(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMatchMode(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodes_Matcher(t *testing.T) {
+	red := "\033[31m"
+	rules := []coloring.Rule{{Substring: "ignored", Matcher: coloring.IgnoreCase("a"), Style: coloring.Code(red)}}
+
+	got := coloring.Codes("Aba", rules)
+	want := []string{red, "", red}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Codes = %q, want %q", got, want)
+			break
+		}
+	}
+}
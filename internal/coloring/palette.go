@@ -0,0 +1,79 @@
+package coloring
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Cycle selects what advances a palette rule to its next style.
+type Cycle int
+
+const (
+	// CycleGlyph gives each visible character the next style. Spaces take
+	// the style of the character that follows them and do not advance the
+	// cycle.
+	CycleGlyph Cycle = iota
+	// CycleWord gives each word, a run of non-space characters, the next
+	// style.
+	CycleWord
+	// CycleRow gives each row of the rendered art the next style.
+	CycleRow
+)
+
+// cycleNames maps the names accepted by ParseCycle to cycles.
+var cycleNames = map[string]Cycle{
+	"glyph": CycleGlyph,
+	"word":  CycleWord,
+	"row":   CycleRow,
+}
+
+// ParseCycle parses a cycle name: glyph, word, or row.
+//
+// Parameters:
+//   - name: The cycle name, case-insensitive.
+//
+// Returns:
+//   - The cycle.
+//   - An error if the name is not a known cycle.
+func ParseCycle(name string) (Cycle, error) {
+	cycle, ok := cycleNames[strings.ToLower(name)]
+	if !ok {
+		return CycleGlyph, fmt.Errorf("invalid cycle: %q\nValid options: glyph, word, row", name)
+	}
+	return cycle, nil
+}
+
+// paletteSteps returns, for each character of text, how far the palette of
+// the rule that owns it has advanced, counting only the characters that rule
+// owns. Characters owned by no rule, or by a rule without a palette, get 0.
+//
+// A word starts at a non-space character whose previous character is a space
+// or is owned by another rule, so a match inside a word counts as a word.
+func paletteSteps(text string, rules []Rule, owners []int) []int {
+	runes := []rune(text)
+	steps := make([]int, len(owners))
+	glyphs := make([]int, len(rules))
+	words := make([]int, len(rules))
+
+	for i, owner := range owners {
+		if owner < 0 || len(rules[owner].Palette) == 0 || i >= len(runes) {
+			continue
+		}
+
+		space := unicode.IsSpace(runes[i])
+		switch rules[owner].Cycle {
+		case CycleGlyph:
+			steps[i] = glyphs[owner]
+			if !space {
+				glyphs[owner]++
+			}
+		case CycleWord:
+			if !space && (i == 0 || owners[i-1] != owner || unicode.IsSpace(runes[i-1])) {
+				words[owner]++
+			}
+			steps[i] = max(words[owner]-1, 0)
+		}
+	}
+	return steps
+}
@@ -0,0 +1,107 @@
+package coloring_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vxanthio/ascii-art-color/internal/coloring"
+)
+
+func TestParseCycle(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    coloring.Cycle
+		wantErr bool
+	}{
+		{"glyph", coloring.CycleGlyph, false},
+		{"Word", coloring.CycleWord, false},
+		{"row", coloring.CycleRow, false},
+		{"column", coloring.CycleGlyph, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := coloring.ParseCycle(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCycle(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseCycle(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodes_Palette(t *testing.T) {
+	palette := []coloring.Style{coloring.Code("A"), coloring.Code("B"), coloring.Code("C")}
+
+	tests := []struct {
+		name  string
+		text  string
+		rules []coloring.Rule
+		want  string
+	}{
+		{
+			name:  "glyph cycles and wraps",
+			text:  "abcde",
+			rules: []coloring.Rule{{Palette: palette}},
+			want:  "ABCAB",
+		},
+		{
+			name:  "spaces do not advance",
+			text:  "ab cd",
+			rules: []coloring.Rule{{Palette: palette}},
+			want:  "ABCCA",
+		},
+		{
+			name:  "word",
+			text:  "go is fun ok",
+			rules: []coloring.Rule{{Palette: palette, Cycle: coloring.CycleWord}},
+			want:  "AAABBBCCCCAA",
+		},
+		{
+			name:  "substring counts only its matches",
+			text:  "xyxyx",
+			rules: []coloring.Rule{{Substring: "x", Palette: palette}},
+			want:  "A.B.C",
+		},
+		{
+			name:  "later flat rule wins",
+			text:  "abc",
+			rules: []coloring.Rule{{Palette: palette}, {Substring: "b", Style: coloring.Code("Z")}},
+			want:  "AZB",
+		},
+		{
+			name:  "row cycle reports code",
+			text:  "ab",
+			rules: []coloring.Rule{{Palette: palette, Cycle: coloring.CycleRow, Style: coloring.Code("R")}},
+			want:  "RR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codes := coloring.Codes(tt.text, tt.rules)
+			for i, code := range codes {
+				if code == "" {
+					codes[i] = "."
+				}
+			}
+			if got := strings.Join(codes, ""); got != tt.want {
+				t.Errorf("Codes(%q) = %s, want %s", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyRules_PaletteRow(t *testing.T) {
+	rules := []coloring.Rule{{Palette: []coloring.Style{coloring.Code("<A>"), coloring.Code("<B>")}, Cycle: coloring.CycleRow}}
+
+	got := coloring.ApplyRules([]string{"ab", "cd", "ef"}, "xy", rules, []int{1, 1})
+	want := []string{"<A>ab" + coloring.Reset, "<B>cd" + coloring.Reset, "<A>ef" + coloring.Reset}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,225 @@
+// Package export converts rendered ASCII art to document formats.
+//
+// The rows given to the package are those written to a terminal: plain text
+// with the ANSI escape sequences the coloring package inserts. ParseRow
+// decodes a row into cells, each a character with its Style, from the cells
+// of ansi.Split, so rows are scanned as the renderer measures them. Spans
+// groups the cells into runs of one style, which the writers of each format
+// turn into their own markup. Colors and attributes therefore come from the
+// very rules, masks and shaders that color terminal output.
+//
+// Only the sequences written by color.Style in its TrueColor mode are decoded:
+// 24-bit foreground and background colors (38;2 and 48;2), the attributes
+// bold, dim, italic, underline, blink and reverse, and resets. Other escape
+// sequences take no cells and change nothing.
+//
+// Responsibilities of this package:
+//   - Decode styled rows into cells and spans
+//   - Write rendered art as an HTML fragment or document
+//   - Write rendered art as an SVG image
+//   - Draw rendered art with a built-in bitmap font and write it as a PNG image
+//   - Animate the drawn art as a GIF image
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/vxanthio/ascii-art-color/internal/ansi"
+)
+
+// Color is a 24-bit color. The zero Color is not valid and stands for the
+// default color of the page or terminal.
+type Color struct {
+	R, G, B uint8
+	// Valid reports whether the color is set.
+	Valid bool
+}
+
+// Hex returns the color in the form #rrggbb.
+//
+// Returns:
+//   - The hexadecimal color.
+func (c Color) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// Attr is a set of text attributes, combined with |.
+type Attr uint8
+
+const (
+	// Bold draws text bold.
+	Bold Attr = 1 << iota
+	// Dim draws text faint.
+	Dim
+	// Italic draws text in italics.
+	Italic
+	// Underline underlines text.
+	Underline
+	// Blink makes text blink.
+	Blink
+	// Reverse swaps the foreground and background colors.
+	Reverse
+)
+
+// sgrAttrs maps SGR parameters to the attributes they set.
+var sgrAttrs = map[int]Attr{1: Bold, 2: Dim, 3: Italic, 4: Underline, 5: Blink, 7: Reverse}
+
+// Style is the look of a cell. The zero Style is plain text.
+type Style struct {
+	// Foreground is the text color.
+	Foreground Color
+	// Background is the color behind the text.
+	Background Color
+	// Attrs holds the text attributes.
+	Attrs Attr
+}
+
+// Cell is one column of a rendered row.
+type Cell struct {
+	// Rune is the character drawn in the cell.
+	Rune rune
+	// Style is the style of the character.
+	Style Style
+}
+
+// ParseRow decodes a rendered row into its cells.
+//
+// Parameters:
+//   - row: A rendered row, possibly containing ANSI escape sequences.
+//
+// Returns:
+//   - One cell per visible column of row.
+func ParseRow(row string) []Cell {
+	split := ansi.Split(row)
+	cells := make([]Cell, len(split))
+	styles := map[string]Style{}
+	for i, cell := range split {
+		style, ok := styles[cell.SGR]
+		if !ok {
+			style = parseSGR(cell.SGR)
+			styles[cell.SGR] = style
+		}
+		r, _ := utf8.DecodeRuneInString(cell.Text)
+		cells[i] = Cell{Rune: r, Style: style}
+	}
+	return cells
+}
+
+// parseSGR returns the style set by a run of SGR sequences, as held by an
+// ansi.Cell.
+func parseSGR(sgr string) Style {
+	var style Style
+	for i := 0; i < len(sgr); {
+		n, params, ok := ansi.Escape(sgr[i:])
+		if n == 0 {
+			break
+		}
+		if ok {
+			style = style.apply(params)
+		}
+		i += n
+	}
+	return style
+}
+
+// apply returns s changed by the parameters of an SGR sequence.
+func (s Style) apply(params string) Style {
+	list := strings.Split(params, ";")
+	for i := 0; i < len(list); i++ {
+		code, err := strconv.Atoi(list[i])
+		if list[i] == "" {
+			code, err = 0, nil
+		}
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case code == 0:
+			s = Style{}
+		case sgrAttrs[code] != 0:
+			s.Attrs |= sgrAttrs[code]
+		case code == 22:
+			s.Attrs &^= Bold | Dim
+		case code >= 23 && code <= 27 && sgrAttrs[code-20] != 0:
+			s.Attrs &^= sgrAttrs[code-20]
+		case code == 39:
+			s.Foreground = Color{}
+		case code == 49:
+			s.Background = Color{}
+		case code == 38 || code == 48:
+			c, used := extendedColor(list[i+1:])
+			i += used
+			if code == 38 {
+				s.Foreground = c
+			} else {
+				s.Background = c
+			}
+		}
+	}
+	return s
+}
+
+// extendedColor decodes the parameters after 38 or 48: 2;R;G;B for a 24-bit
+// color, or 5;N for a palette color, which is not decoded.
+//
+// Returns:
+//   - The color; invalid for palette colors and malformed parameters.
+//   - The number of parameters used.
+func extendedColor(params []string) (Color, int) {
+	if len(params) == 0 {
+		return Color{}, 0
+	}
+	switch params[0] {
+	case "5":
+		return Color{}, min(2, len(params))
+	case "2":
+		if len(params) < 4 {
+			return Color{}, len(params)
+		}
+		var c [3]uint8
+		for i := range c {
+			v, err := strconv.ParseUint(params[i+1], 10, 8)
+			if err != nil {
+				return Color{}, 4
+			}
+			c[i] = uint8(v)
+		}
+		return Color{R: c[0], G: c[1], B: c[2], Valid: true}, 4
+	}
+	return Color{}, 1
+}
+
+// Span is a run of characters of one style.
+type Span struct {
+	// Text holds the characters of the run.
+	Text string
+	// Style is their style.
+	Style Style
+}
+
+// Spans groups cells into runs of one style.
+//
+// Parameters:
+//   - cells: The cells of a row.
+//
+// Returns:
+//   - The runs, in order; none for no cells.
+func Spans(cells []Cell) []Span {
+	var spans []Span
+	var text strings.Builder
+	for i, cell := range cells {
+		if i > 0 && cell.Style != cells[i-1].Style {
+			spans = append(spans, Span{Text: text.String(), Style: cells[i-1].Style})
+			text.Reset()
+		}
+		text.WriteRune(cell.Rune)
+	}
+	if len(cells) > 0 {
+		spans = append(spans, Span{Text: text.String(), Style: cells[len(cells)-1].Style})
+	}
+	return spans
+}
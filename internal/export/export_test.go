@@ -0,0 +1,102 @@
+package export_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vxanthio/ascii-art-color/internal/export"
+)
+
+func TestParseRow(t *testing.T) {
+	red := export.Color{R: 255, Valid: true}
+	navy := export.Color{B: 128, Valid: true}
+
+	tests := []struct {
+		name string
+		row  string
+		want []export.Cell
+	}{
+		{
+			name: "plain text",
+			row:  "a|",
+			want: []export.Cell{{Rune: 'a'}, {Rune: '|'}},
+		},
+		{
+			name: "foreground and reset",
+			row:  "\033[38;2;255;0;0mab\033[0mc",
+			want: []export.Cell{
+				{Rune: 'a', Style: export.Style{Foreground: red}},
+				{Rune: 'b', Style: export.Style{Foreground: red}},
+				{Rune: 'c'},
+			},
+		},
+		{
+			name: "attributes and background in one sequence",
+			row:  "\033[1;4;38;2;255;0;0;48;2;0;0;128mé",
+			want: []export.Cell{
+				{Rune: 'é', Style: export.Style{Foreground: red, Background: navy, Attrs: export.Bold | export.Underline}},
+			},
+		},
+		{
+			name: "attributes turned off",
+			row:  "\033[1;3mx\033[22;23my",
+			want: []export.Cell{
+				{Rune: 'x', Style: export.Style{Attrs: export.Bold | export.Italic}},
+				{Rune: 'y'},
+			},
+		},
+		{
+			name: "palette colors and other sequences are skipped",
+			row:  "\033[38;5;196;7mx\033[2Ky\033[m",
+			want: []export.Cell{
+				{Rune: 'x', Style: export.Style{Attrs: export.Reverse}},
+				{Rune: 'y', Style: export.Style{Attrs: export.Reverse}},
+			},
+		},
+		{
+			name: "default colors",
+			row:  "\033[38;2;255;0;0;48;2;0;0;128mx\033[39my\033[49mz",
+			want: []export.Cell{
+				{Rune: 'x', Style: export.Style{Foreground: red, Background: navy}},
+				{Rune: 'y', Style: export.Style{Background: navy}},
+				{Rune: 'z'},
+			},
+		},
+		{
+			name: "empty row",
+			row:  "",
+			want: []export.Cell{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := export.ParseRow(tt.row); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRow(%q) = %+v, want %+v", tt.row, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpans(t *testing.T) {
+	red := export.Style{Foreground: export.Color{R: 255, Valid: true}}
+	got := export.Spans(export.ParseRow("ab\033[38;2;255;0;0mcd\033[0me"))
+	want := []export.Span{
+		{Text: "ab"},
+		{Text: "cd", Style: red},
+		{Text: "e"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Spans() = %+v, want %+v", got, want)
+	}
+
+	if got := export.Spans(nil); got != nil {
+		t.Errorf("Spans(nil) = %+v, want nil", got)
+	}
+}
+
+func TestColor_Hex(t *testing.T) {
+	if got := (export.Color{R: 255, G: 8, B: 171, Valid: true}).Hex(); got != "#ff08ab" {
+		t.Errorf("Hex() = %q, want #ff08ab", got)
+	}
+}
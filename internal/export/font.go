@@ -0,0 +1,128 @@
+package export
+
+import "strings"
+
+const (
+	// GlyphWidth is the width of a cell of the bitmap font, in pixels.
+	GlyphWidth = 6
+	// GlyphHeight is the height of a cell of the bitmap font, in pixels.
+	GlyphHeight = 8
+)
+
+// font is the bitmap font of raster images: one glyph per printable ASCII
+// character, drawn as GlyphHeight rows of GlyphWidth pixels separated by
+// spaces, with '#' for a set pixel. Letters and digits are 5 by 7 pixels with
+// descenders in the last row; the characters banners draw lines with fill the
+// whole cell where the line reaches its edge, so that '|' joins the '|' of
+// the rows above and below, and '_' and '-' join their neighbors.
+var font = map[rune]string{
+	' ':  "...... ...... ...... ...... ...... ...... ...... ......",
+	'!':  "..#... ..#... ..#... ..#... ..#... ...... ..#... ......",
+	'"':  ".#.#.. .#.#.. ...... ...... ...... ...... ...... ......",
+	'#':  ".#.#.. .#.#.. #####. .#.#.. #####. .#.#.. .#.#.. ......",
+	'$':  "..#... .####. #.#... .###.. ..#.#. ####.. ..#... ......",
+	'%':  "##.... ##..#. ...#.. ..#... .#.... #..##. ...##. ......",
+	'&':  ".##... #..#.. #.#... .#.... #.#.#. #..#.. .##.#. ......",
+	'\'': "..#... ..#... ...... ...... ...... ...... ...... ......",
+	'(':  "...#.. ..#... .#.... .#.... .#.... ..#... ...#.. ......",
+	')':  ".#.... ..#... ...#.. ...#.. ...#.. ..#... .#.... ......",
+	'*':  "...... ..#... #.#.#. .###.. #.#.#. ..#... ...... ......",
+	'+':  "...... ..#... ..#... #####. ..#... ..#... ...... ......",
+	',':  "...... ...... ...... ...... ...... ..#... ..#... .#....",
+	'-':  "...... ...... ...... ###### ...... ...... ...... ......",
+	'.':  "...... ...... ...... ...... ...... ...... ..#... ......",
+	'/':  ".....# ....#. ....#. ...#.. ..#... .#.... .#.... #.....",
+	'0':  ".###.. #...#. #..##. #.#.#. ##..#. #...#. .###.. ......",
+	'1':  "..#... .##... ..#... ..#... ..#... ..#... .###.. ......",
+	'2':  ".###.. #...#. ....#. ...#.. ..#... .#.... #####. ......",
+	'3':  "#####. ...#.. ..#... ...#.. ....#. #...#. .###.. ......",
+	'4':  "...#.. ..##.. .#.#.. #..#.. #####. ...#.. ...#.. ......",
+	'5':  "#####. #..... ####.. ....#. ....#. #...#. .###.. ......",
+	'6':  "..##.. .#.... #..... ####.. #...#. #...#. .###.. ......",
+	'7':  "#####. ....#. ...#.. ..#... .#.... .#.... .#.... ......",
+	'8':  ".###.. #...#. #...#. .###.. #...#. #...#. .###.. ......",
+	'9':  ".###.. #...#. #...#. .####. ....#. ...#.. .##... ......",
+	':':  "...... ...... ..#... ...... ...... ..#... ...... ......",
+	';':  "...... ...... ..#... ...... ...... ..#... ..#... .#....",
+	'<':  "...#.. ..#... .#.... #..... .#.... ..#... ...#.. ......",
+	'=':  "...... ...... ###### ...... ###### ...... ...... ......",
+	'>':  ".#.... ..#... ...#.. ....#. ...#.. ..#... .#.... ......",
+	'?':  ".###.. #...#. ....#. ...#.. ..#... ...... ..#... ......",
+	'@':  ".###.. #...#. #.###. #.#.#. #.###. #..... .###.. ......",
+	'A':  ".###.. #...#. #...#. #####. #...#. #...#. #...#. ......",
+	'B':  "####.. #...#. #...#. ####.. #...#. #...#. ####.. ......",
+	'C':  ".###.. #...#. #..... #..... #..... #...#. .###.. ......",
+	'D':  "####.. #...#. #...#. #...#. #...#. #...#. ####.. ......",
+	'E':  "#####. #..... #..... ####.. #..... #..... #####. ......",
+	'F':  "#####. #..... #..... ####.. #..... #..... #..... ......",
+	'G':  ".###.. #...#. #..... #.###. #...#. #...#. .####. ......",
+	'H':  "#...#. #...#. #...#. #####. #...#. #...#. #...#. ......",
+	'I':  ".###.. ..#... ..#... ..#... ..#... ..#... .###.. ......",
+	'J':  "..###. ...#.. ...#.. ...#.. ...#.. #..#.. .##... ......",
+	'K':  "#...#. #..#.. #.#... ##.... #.#... #..#.. #...#. ......",
+	'L':  "#..... #..... #..... #..... #..... #..... #####. ......",
+	'M':  "#...#. ##.##. #.#.#. #.#.#. #...#. #...#. #...#. ......",
+	'N':  "#...#. #...#. ##..#. #.#.#. #..##. #...#. #...#. ......",
+	'O':  ".###.. #...#. #...#. #...#. #...#. #...#. .###.. ......",
+	'P':  "####.. #...#. #...#. ####.. #..... #..... #..... ......",
+	'Q':  ".###.. #...#. #...#. #...#. #.#.#. #..#.. .##.#. ......",
+	'R':  "####.. #...#. #...#. ####.. #.#... #..#.. #...#. ......",
+	'S':  ".####. #..... #..... .###.. ....#. ....#. ####.. ......",
+	'T':  "#####. ..#... ..#... ..#... ..#... ..#... ..#... ......",
+	'U':  "#...#. #...#. #...#. #...#. #...#. #...#. .###.. ......",
+	'V':  "#...#. #...#. #...#. #...#. #...#. .#.#.. ..#... ......",
+	'W':  "#...#. #...#. #...#. #.#.#. #.#.#. #.#.#. .#.#.. ......",
+	'X':  "#...#. #...#. .#.#.. ..#... .#.#.. #...#. #...#. ......",
+	'Y':  "#...#. #...#. .#.#.. ..#... ..#... ..#... ..#... ......",
+	'Z':  "#####. ....#. ...#.. ..#... .#.... #..... #####. ......",
+	'[':  ".###.. .#.... .#.... .#.... .#.... .#.... .###.. ......",
+	'\\': "#..... .#.... .#.... ..#... ...#.. ....#. ....#. .....#",
+	']':  ".###.. ...#.. ...#.. ...#.. ...#.. ...#.. .###.. ......",
+	'^':  "..#... .#.#.. #...#. ...... ...... ...... ...... ......",
+	'_':  "...... ...... ...... ...... ...... ...... ...... ######",
+	'`':  ".#.... ..#... ...... ...... ...... ...... ...... ......",
+	'a':  "...... ...... .###.. ....#. .####. #...#. .####. ......",
+	'b':  "#..... #..... #.##.. ##..#. #...#. #...#. ####.. ......",
+	'c':  "...... ...... .###.. #..... #..... #...#. .###.. ......",
+	'd':  "....#. ....#. .##.#. #..##. #...#. #...#. .####. ......",
+	'e':  "...... ...... .###.. #...#. #####. #..... .###.. ......",
+	'f':  "..##.. .#..#. .#.... ###... .#.... .#.... .#.... ......",
+	'g':  "...... ...... .####. #...#. #...#. .####. ....#. .###..",
+	'h':  "#..... #..... #.##.. ##..#. #...#. #...#. #...#. ......",
+	'i':  "..#... ...... .##... ..#... ..#... ..#... .###.. ......",
+	'j':  "...#.. ...... ..##.. ...#.. ...#.. ...#.. #..#.. .##...",
+	'k':  "#..... #..... #..#.. #.#... ##.... #.#... #..#.. ......",
+	'l':  ".##... ..#... ..#... ..#... ..#... ..#... .###.. ......",
+	'm':  "...... ...... ##.#.. #.#.#. #.#.#. #...#. #...#. ......",
+	'n':  "...... ...... #.##.. ##..#. #...#. #...#. #...#. ......",
+	'o':  "...... ...... .###.. #...#. #...#. #...#. .###.. ......",
+	'p':  "...... ...... ####.. #...#. #...#. ####.. #..... #.....",
+	'q':  "...... ...... .####. #...#. #...#. .####. ....#. ....#.",
+	'r':  "...... ...... #.##.. ##..#. #..... #..... #..... ......",
+	's':  "...... ...... .####. #..... .###.. ....#. ####.. ......",
+	't':  ".#.... .#.... ###... .#.... .#.... .#..#. ..##.. ......",
+	'u':  "...... ...... #...#. #...#. #...#. #..##. .##.#. ......",
+	'v':  "...... ...... #...#. #...#. #...#. .#.#.. ..#... ......",
+	'w':  "...... ...... #...#. #...#. #.#.#. #.#.#. .#.#.. ......",
+	'x':  "...... ...... #...#. .#.#.. ..#... .#.#.. #...#. ......",
+	'y':  "...... ...... #...#. #...#. #...#. .####. ....#. .###..",
+	'z':  "...... ...... #####. ...#.. ..#... .#.... #####. ......",
+	'{':  "...#.. ..#... ..#... .#.... ..#... ..#... ...#.. ......",
+	'|':  "..#... ..#... ..#... ..#... ..#... ..#... ..#... ..#...",
+	'}':  ".#.... ..#... ..#... ...#.. ..#... ..#... .#.... ......",
+	'~':  "...... ...... .#.... #.#.#. ...#.. ...... ...... ......",
+}
+
+// missingGlyph is drawn for characters the font lacks: the outline of a box.
+const missingGlyph = "#####. #...#. #...#. #...#. #...#. #...#. #####. ......"
+
+// glyph returns the pixels of the glyph of r, row by row.
+func glyph(r rune) [GlyphHeight]string {
+	bitmap, ok := font[r]
+	if !ok {
+		bitmap = missingGlyph
+	}
+	var rows [GlyphHeight]string
+	copy(rows[:], strings.Fields(bitmap))
+	return rows
+}
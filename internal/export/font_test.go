@@ -0,0 +1,34 @@
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFont(t *testing.T) {
+	for r := rune(' '); r <= '~'; r++ {
+		bitmap, ok := font[r]
+		if !ok {
+			t.Errorf("font has no glyph for %q", r)
+			continue
+		}
+		rows := strings.Fields(bitmap)
+		if len(rows) != GlyphHeight {
+			t.Errorf("glyph %q has %d rows, want %d", r, len(rows), GlyphHeight)
+		}
+		for _, row := range rows {
+			if len(row) != GlyphWidth || strings.Trim(row, "#.") != "" {
+				t.Errorf("glyph %q has malformed row %q", r, row)
+			}
+		}
+	}
+	if len(font) != '~'-' '+1 {
+		t.Errorf("font has %d glyphs, want only printable ASCII", len(font))
+	}
+}
+
+func TestGlyph_Missing(t *testing.T) {
+	if got, want := glyph('é'), glyph(0); got != want || got[0] != "#####." {
+		t.Errorf("glyph('é') = %q, want the box", got)
+	}
+}
@@ -0,0 +1,343 @@
+package export
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// Effect is the animation of a GIF image.
+type Effect int
+
+const (
+	// EffectTypewriter reveals the art one character at a time.
+	EffectTypewriter Effect = iota
+	// EffectMarquee scrolls the art from right to left across the image.
+	EffectMarquee
+	// EffectRainbow sweeps the colors of the rainbow across the art.
+	EffectRainbow
+	// EffectBlink hides and shows the blinking characters, or the whole art
+	// when no character blinks.
+	EffectBlink
+)
+
+// effectNames lists the effect names, indexed by Effect.
+var effectNames = []string{"typewriter", "marquee", "rainbow", "blink"}
+
+// String returns the name of the effect.
+func (e Effect) String() string {
+	if e < 0 || int(e) >= len(effectNames) {
+		return fmt.Sprintf("Effect(%d)", int(e))
+	}
+	return effectNames[e]
+}
+
+// ParseEffect converts an effect name to an Effect.
+//
+// Parameters:
+//   - name: The effect name, case-insensitive: typewriter, marquee, rainbow,
+//     or blink.
+//
+// Returns:
+//   - The effect.
+//   - An error if the name is not recognized.
+func ParseEffect(name string) (Effect, error) {
+	for i, effect := range effectNames {
+		if strings.EqualFold(name, effect) {
+			return Effect(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid effect: %q\nValid options: %s", name, strings.Join(effectNames, ", "))
+}
+
+const (
+	// DefaultDelay is the time each frame of a GIF image shows when
+	// GIFOptions.Delay is zero.
+	DefaultDelay = 100 * time.Millisecond
+	// typewriterHold is how many frame delays the last frame of the
+	// typewriter effect shows, so the complete art can be read before the
+	// animation starts over.
+	typewriterHold = 10
+	// rainbowSteps is the number of frames of one rainbow cycle.
+	rainbowSteps = 12
+)
+
+// GIFOptions configures the output of GIF.
+type GIFOptions struct {
+	// ImageOptions sets the scale, padding and colors of the frames.
+	ImageOptions
+	// Effect is the animation.
+	Effect Effect
+	// Delay is the time each frame shows, in steps of 10ms; zero means
+	// DefaultDelay.
+	Delay time.Duration
+	// Loops is the number of times the animation plays; zero repeats it
+	// forever.
+	Loops int
+	// Width and Height are the size of the image in pixels; zero sizes it to
+	// the art and its padding. The art is centered in a larger image and
+	// cropped in a smaller one.
+	Width, Height int
+	// Reveal lists, in typing order, the cells of every character for the
+	// typewriter effect, as rectangles in columns and rows. Without it,
+	// the art is revealed one column at a time.
+	Reveal []image.Rectangle
+}
+
+// frame is one image of an animation: the cells to draw, where to draw
+// them, and for how many delays.
+type frame struct {
+	grid   [][]Cell
+	origin image.Point
+	delays int
+}
+
+// GIF writes rendered rows as an animated GIF image, every frame drawn with
+// the bitmap font as Image draws it.
+//
+// The typewriter effect adds the cells of one character per frame and holds
+// the complete art for ten delays. The marquee effect moves the art one column
+// per frame, from just beyond the right edge of the image until it has left
+// on the left. The rainbow effect recolors every character by its column,
+// shifting the hues by a twelfth of the spectrum per frame. The blink effect
+// alternates the art with and without its blinking characters.
+//
+// Parameters:
+//   - w: The destination of the image.
+//   - rows: The rendered rows, possibly containing ANSI escape sequences.
+//   - opts: The output options.
+//
+// Returns:
+//   - An error if the image cannot be encoded or written.
+func GIF(w io.Writer, rows []string, opts GIFOptions) error {
+	if opts.Scale <= 0 {
+		opts.Scale = DefaultScale
+	}
+	opts.Padding = max(opts.Padding, 0)
+	opts.Foreground = textColor(opts.Foreground, opts.Background)
+	delay := opts.Delay
+	if delay <= 0 {
+		delay = DefaultDelay
+	}
+
+	grid, columns := parseGrid(rows)
+	art := artSize(columns, len(grid), opts.Scale, opts.Padding)
+	size := art
+	if opts.Width > 0 {
+		size.X = opts.Width
+	}
+	if opts.Height > 0 {
+		size.Y = opts.Height
+	}
+	origin := size.Sub(art).Div(2).Add(image.Pt(opts.Padding, opts.Padding))
+
+	var frames []frame
+	switch opts.Effect {
+	case EffectTypewriter:
+		frames = typewriterFrames(grid, columns, opts.Reveal, origin)
+	case EffectMarquee:
+		frames = marqueeFrames(grid, columns, size.X, origin, opts)
+	case EffectRainbow:
+		frames = rainbowFrames(grid, columns, origin)
+	case EffectBlink:
+		frames = blinkFrames(grid, origin)
+	}
+
+	anim := &gif.GIF{LoopCount: loopCount(opts.Loops)}
+	for _, f := range frames {
+		img := newCanvas(size, opts.Background)
+		drawGrid(img, f.grid, f.origin, opts.Scale, opts.ImageOptions)
+		anim.Image = append(anim.Image, paletted(img))
+		anim.Delay = append(anim.Delay, f.delays*hundredths(delay))
+		anim.Disposal = append(anim.Disposal, gif.DisposalBackground)
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// typewriterFrames reveals the cells of reveal one rectangle per frame, or
+// one column per frame without reveal. Cells outside every rectangle stay
+// hidden to the end.
+func typewriterFrames(grid [][]Cell, columns int, reveal []image.Rectangle, origin image.Point) []frame {
+	if len(reveal) == 0 {
+		for column := 0; column < columns; column++ {
+			reveal = append(reveal, image.Rect(column, 0, column+1, len(grid)))
+		}
+	}
+
+	frames := make([]frame, 0, len(reveal))
+	for n := 1; n <= len(reveal); n++ {
+		shown := mapCells(grid, func(row, column int, cell Cell) Cell {
+			p := image.Pt(column, row)
+			for _, r := range reveal[:n] {
+				if p.In(r) {
+					return cell
+				}
+			}
+			return Cell{Rune: ' '}
+		})
+		frames = append(frames, frame{grid: shown, origin: origin, delays: 1})
+	}
+	if len(frames) == 0 {
+		frames = append(frames, frame{grid: grid, origin: origin})
+	}
+	frames[len(frames)-1].delays = typewriterHold
+	return frames
+}
+
+// marqueeFrames moves the art one column per frame, from the right edge of
+// an image width pixels wide until it has left on the left.
+func marqueeFrames(grid [][]Cell, columns, width int, origin image.Point, opts GIFOptions) []frame {
+	step := GlyphWidth * opts.Scale
+	start := width + opts.Padding
+	end := -(columns*step + opts.Padding)
+	var frames []frame
+	for x := start; x > end; x -= step {
+		frames = append(frames, frame{grid: grid, origin: image.Pt(x, origin.Y), delays: 1})
+	}
+	return frames
+}
+
+// rainbowFrames recolors the visible characters with a hue that follows
+// their column, shifted by one step of the cycle per frame.
+func rainbowFrames(grid [][]Cell, columns int, origin image.Point) []frame {
+	frames := make([]frame, rainbowSteps)
+	for i := range frames {
+		shift := float64(i) / rainbowSteps
+		frames[i] = frame{origin: origin, delays: 1, grid: mapCells(grid, func(_, column int, cell Cell) Cell {
+			if cell.Rune != ' ' {
+				position := float64(column) / float64(max(columns, 1))
+				cell.Style.Foreground = hue(position - shift)
+			}
+			return cell
+		})}
+	}
+	return frames
+}
+
+// blinkFrames alternates the art with the art without its blinking
+// characters; when no character blinks, every character does. Hidden
+// characters keep their background.
+func blinkFrames(grid [][]Cell, origin image.Point) []frame {
+	blinking := false
+	for _, cells := range grid {
+		for _, cell := range cells {
+			blinking = blinking || cell.Style.Attrs&Blink != 0
+		}
+	}
+	hidden := mapCells(grid, func(_, _ int, cell Cell) Cell {
+		if !blinking || cell.Style.Attrs&Blink != 0 {
+			cell.Rune = ' '
+			cell.Style.Attrs &^= Underline
+		}
+		return cell
+	})
+	return []frame{{grid: grid, origin: origin, delays: 1}, {grid: hidden, origin: origin, delays: 1}}
+}
+
+// mapCells returns a copy of grid with every cell replaced by fn.
+func mapCells(grid [][]Cell, fn func(row, column int, cell Cell) Cell) [][]Cell {
+	mapped := make([][]Cell, len(grid))
+	for i, cells := range grid {
+		mapped[i] = make([]Cell, len(cells))
+		for j, cell := range cells {
+			mapped[i][j] = fn(i, j, cell)
+		}
+	}
+	return mapped
+}
+
+// hue returns the fully saturated color of hue h, in turns: 0 is red, 1/3
+// green and 2/3 blue. Values outside [0, 1) wrap around.
+func hue(h float64) Color {
+	h = (h - math.Floor(h)) * 6
+	x := uint8(math.Round(255 * (1 - math.Abs(math.Mod(h, 2)-1))))
+	switch int(h) {
+	case 0:
+		return Color{R: 255, G: x, Valid: true}
+	case 1:
+		return Color{R: x, G: 255, Valid: true}
+	case 2:
+		return Color{G: 255, B: x, Valid: true}
+	case 3:
+		return Color{G: x, B: 255, Valid: true}
+	case 4:
+		return Color{R: x, B: 255, Valid: true}
+	}
+	return Color{R: 255, B: x, Valid: true}
+}
+
+// loopCount converts a number of plays to the LoopCount of image/gif, which
+// counts the repetitions after the first play and uses -1 for none.
+func loopCount(loops int) int {
+	if loops <= 0 {
+		return 0
+	}
+	if loops == 1 {
+		return -1
+	}
+	return loops - 1
+}
+
+// hundredths converts a frame delay to the hundredths of a second of GIF
+// images, never less than one.
+func hundredths(d time.Duration) int {
+	return max(int((d+5*time.Millisecond)/(10*time.Millisecond)), 1)
+}
+
+// paletted converts a frame to a paletted image. Index 0 is transparent, for
+// pixels that are less than half opaque; the other pixels become opaque. A
+// frame of at most 255 colors keeps its exact colors; others are mapped to
+// the web-safe palette.
+func paletted(img *image.RGBA) *image.Paletted {
+	bounds := img.Bounds()
+	colors := color.Palette{color.RGBA{}}
+	index := map[color.RGBA]uint8{}
+	for y := bounds.Min.Y; y < bounds.Max.Y && len(colors) <= 256; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c, ok := opaque(img.RGBAAt(x, y))
+			if _, seen := index[c]; ok && !seen {
+				index[c] = uint8(len(colors))
+				colors = append(colors, c)
+			}
+		}
+	}
+	exact := len(colors) <= 256
+	if !exact {
+		colors = append(color.Palette{color.RGBA{}}, palette.WebSafe...)
+	}
+
+	out := image.NewPaletted(bounds, colors)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c, ok := opaque(img.RGBAAt(x, y))
+			switch {
+			case !ok:
+				out.SetColorIndex(x, y, 0)
+			case exact:
+				out.SetColorIndex(x, y, index[c])
+			default:
+				out.SetColorIndex(x, y, uint8(1+color.Palette(palette.WebSafe).Index(c)))
+			}
+		}
+	}
+	return out
+}
+
+// opaque returns the opaque color of a premultiplied pixel, and whether the
+// pixel is at least half opaque.
+func opaque(c color.RGBA) (color.RGBA, bool) {
+	if c.A < 128 {
+		return color.RGBA{}, false
+	}
+	if c.A == 255 {
+		return c, true
+	}
+	unmultiply := func(v uint8) uint8 { return uint8(uint32(v) * 255 / uint32(c.A)) }
+	return color.RGBA{R: unmultiply(c.R), G: unmultiply(c.G), B: unmultiply(c.B), A: 255}, true
+}
@@ -0,0 +1,178 @@
+package export_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+	"time"
+
+	"github.com/vxanthio/ascii-art-color/internal/export"
+)
+
+func TestParseEffect(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    export.Effect
+		wantErr bool
+	}{
+		{"typewriter", export.EffectTypewriter, false},
+		{"Marquee", export.EffectMarquee, false},
+		{"RAINBOW", export.EffectRainbow, false},
+		{"blink", export.EffectBlink, false},
+		{"fade", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := export.ParseEffect(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseEffect(%q) = %v, %v; want %v, error %t", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+	if got, want := export.EffectMarquee.String(), "marquee"; got != want {
+		t.Errorf("Marquee.String() = %q, want %q", got, want)
+	}
+}
+
+// decodeGIF encodes rows with GIF and decodes the result.
+func decodeGIF(t *testing.T, rows []string, opts export.GIFOptions) *gif.GIF {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := export.GIF(&buf, rows, opts); err != nil {
+		t.Fatalf("GIF() error = %v", err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("gif.DecodeAll() error = %v", err)
+	}
+	return anim
+}
+
+// at returns the color of a pixel of a decoded frame.
+func at(img *image.Paletted, x, y int) color.RGBA {
+	return color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+}
+
+func TestGIF_Frames(t *testing.T) {
+	tests := []struct {
+		name       string
+		rows       []string
+		opts       export.GIFOptions
+		wantFrames int
+		wantDelays []int
+	}{
+		{"typewriter by column", []string{"|-|"}, export.GIFOptions{}, 3, []int{10, 10, 100}},
+		{"typewriter by reveal", []string{"|-|"}, export.GIFOptions{Reveal: []image.Rectangle{image.Rect(0, 0, 2, 1), image.Rect(2, 0, 3, 1)}}, 2, []int{10, 100}},
+		{"marquee", []string{"ab"}, export.GIFOptions{ImageOptions: export.ImageOptions{Scale: 1}, Effect: export.EffectMarquee}, 4, []int{10, 10, 10, 10}},
+		{"marquee across a wider image", []string{"ab"}, export.GIFOptions{ImageOptions: export.ImageOptions{Scale: 1}, Effect: export.EffectMarquee, Width: 24}, 6, nil},
+		{"rainbow", []string{"ab"}, export.GIFOptions{Effect: export.EffectRainbow, Delay: 50 * time.Millisecond}, 12, []int{5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5}},
+		{"blink", []string{"ab"}, export.GIFOptions{Effect: export.EffectBlink, Delay: time.Second}, 2, []int{100, 100}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anim := decodeGIF(t, tt.rows, tt.opts)
+			if len(anim.Image) != tt.wantFrames {
+				t.Fatalf("frames = %d, want %d", len(anim.Image), tt.wantFrames)
+			}
+			if tt.wantDelays != nil && !equalInts(anim.Delay, tt.wantDelays) {
+				t.Errorf("delays = %v, want %v", anim.Delay, tt.wantDelays)
+			}
+		})
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestGIF_LoopCount(t *testing.T) {
+	tests := []struct {
+		loops int
+		want  int
+	}{
+		{0, 0},
+		{1, -1},
+		{3, 2},
+	}
+	for _, tt := range tests {
+		if got := decodeGIF(t, []string{"ab"}, export.GIFOptions{Loops: tt.loops}).LoopCount; got != tt.want {
+			t.Errorf("Loops %d: LoopCount = %d, want %d", tt.loops, got, tt.want)
+		}
+	}
+}
+
+func TestGIF_Size(t *testing.T) {
+	tests := []struct {
+		name string
+		opts export.GIFOptions
+		want image.Rectangle
+	}{
+		{"sized to the art", export.GIFOptions{ImageOptions: export.ImageOptions{Scale: 1, Padding: 2}}, image.Rect(0, 0, 16, 12)},
+		{"canvas size", export.GIFOptions{ImageOptions: export.ImageOptions{Scale: 1}, Width: 40, Height: 10}, image.Rect(0, 0, 40, 10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anim := decodeGIF(t, []string{"ab"}, tt.opts)
+			if got := anim.Image[0].Bounds(); got != tt.want {
+				t.Errorf("bounds = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGIF_Pixels(t *testing.T) {
+	canvas := export.Color{R: 255, G: 255, B: 255, Valid: true}
+
+	t.Run("typewriter reveals left to right", func(t *testing.T) {
+		anim := decodeGIF(t, []string{"||"}, export.GIFOptions{ImageOptions: export.ImageOptions{Scale: 1}})
+		if got := at(anim.Image[0], 2, 0); got != black {
+			t.Errorf("frame 0 first glyph = %v, want %v", got, black)
+		}
+		if got := at(anim.Image[0], 8, 0); got != clear {
+			t.Errorf("frame 0 second glyph = %v, want %v", got, clear)
+		}
+		if got := at(anim.Image[1], 8, 0); got != black {
+			t.Errorf("frame 1 second glyph = %v, want %v", got, black)
+		}
+	})
+
+	t.Run("blink hides blinking characters", func(t *testing.T) {
+		anim := decodeGIF(t, []string{"|\033[5m|\033[0m"}, export.GIFOptions{ImageOptions: export.ImageOptions{Scale: 1, Background: canvas}, Effect: export.EffectBlink})
+		want := [][2]color.RGBA{{black, black}, {black, white}}
+		for i, pair := range want {
+			for j, x := range []int{2, 8} {
+				if got := at(anim.Image[i], x, 0); got != pair[j] {
+					t.Errorf("frame %d pixel (%d,0) = %v, want %v", i, x, got, pair[j])
+				}
+			}
+		}
+	})
+
+	t.Run("rainbow starts red", func(t *testing.T) {
+		anim := decodeGIF(t, []string{"|"}, export.GIFOptions{ImageOptions: export.ImageOptions{Scale: 1}, Effect: export.EffectRainbow})
+		if got, want := at(anim.Image[0], 2, 0), (color.RGBA{R: 255, A: 255}); got != want {
+			t.Errorf("frame 0 = %v, want %v", got, want)
+		}
+		if got, want := at(anim.Image[4], 2, 0), (color.RGBA{B: 255, A: 255}); got != want {
+			t.Errorf("frame 4 = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("marquee enters from the right", func(t *testing.T) {
+		anim := decodeGIF(t, []string{"|"}, export.GIFOptions{ImageOptions: export.ImageOptions{Scale: 1}, Effect: export.EffectMarquee, Width: 12})
+		if got := at(anim.Image[1], 8, 0); got != black {
+			t.Errorf("frame 1 pixel (8,0) = %v, want %v", got, black)
+		}
+		if got := at(anim.Image[2], 2, 0); got != black {
+			t.Errorf("frame 2 pixel (2,0) = %v, want %v", got, black)
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package export
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// htmlClass is the class of the <pre> element that holds the art.
+const htmlClass = "ascii-art"
+
+// HTMLOptions configures the output of HTML.
+type HTMLOptions struct {
+	// Document writes a complete HTML document instead of a fragment.
+	Document bool
+	// Classes styles the spans with CSS classes, defined in a <style>
+	// element, instead of inline style attributes.
+	Classes bool
+	// Title is the title of the document; ignored for fragments.
+	Title string
+}
+
+// HTML writes rendered rows as a <pre> element, with every styled run of
+// characters in a <span>. The text is HTML-escaped.
+//
+// Inline styles are the default: <span style="color:#ff0000">. With
+// opts.Classes, each distinct style gets a class, aa-s1, aa-s2 and so on, in
+// order of first use, defined in a <style> element that precedes the <pre>
+// element, or sits in the <head> of a document.
+//
+// Parameters:
+//   - rows: The rendered rows, possibly containing ANSI escape sequences.
+//   - opts: The output options.
+//
+// Returns:
+//   - The HTML, ending with a newline.
+func HTML(rows []string, opts HTMLOptions) string {
+	var body strings.Builder
+	classes := map[Style]string{}
+	var styles []Style
+
+	fmt.Fprintf(&body, "<pre class=%q>", htmlClass)
+	for _, row := range rows {
+		for _, span := range Spans(ParseRow(row)) {
+			text := html.EscapeString(span.Text)
+			if span.Style == (Style{}) {
+				body.WriteString(text)
+				continue
+			}
+			if !opts.Classes {
+				fmt.Fprintf(&body, "<span style=\"%s\">%s</span>", css(span.Style), text)
+				continue
+			}
+			class, ok := classes[span.Style]
+			if !ok {
+				styles = append(styles, span.Style)
+				class = fmt.Sprintf("aa-s%d", len(styles))
+				classes[span.Style] = class
+			}
+			fmt.Fprintf(&body, "<span class=%q>%s</span>", class, text)
+		}
+		body.WriteString("\n")
+	}
+	body.WriteString("</pre>\n")
+
+	var styleSheet strings.Builder
+	if opts.Classes {
+		styleSheet.WriteString("<style>\n")
+		for i, style := range styles {
+			fmt.Fprintf(&styleSheet, ".aa-s%d { %s }\n", i+1, css(style))
+		}
+		styleSheet.WriteString("</style>\n")
+	}
+
+	if !opts.Document {
+		return styleSheet.String() + body.String()
+	}
+
+	var doc strings.Builder
+	doc.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&doc, "<title>%s</title>\n", html.EscapeString(opts.Title))
+	doc.WriteString(styleSheet.String())
+	doc.WriteString("</head>\n<body>\n")
+	doc.WriteString(body.String())
+	doc.WriteString("</body>\n</html>\n")
+	return doc.String()
+}
+
+// css returns the CSS declarations of a style. Reverse video swaps the
+// colors, using the page's own colors, Canvas and CanvasText, where a color
+// is not set.
+func css(s Style) string {
+	foreground, background := cssColor(s.Foreground, ""), cssColor(s.Background, "")
+	if s.Attrs&Reverse != 0 {
+		foreground, background = cssColor(s.Background, "Canvas"), cssColor(s.Foreground, "CanvasText")
+	}
+
+	var decls []string
+	if foreground != "" {
+		decls = append(decls, "color:"+foreground)
+	}
+	if background != "" {
+		decls = append(decls, "background-color:"+background)
+	}
+	if s.Attrs&Bold != 0 {
+		decls = append(decls, "font-weight:bold")
+	}
+	if s.Attrs&Dim != 0 {
+		decls = append(decls, "opacity:0.5")
+	}
+	if s.Attrs&Italic != 0 {
+		decls = append(decls, "font-style:italic")
+	}
+
+	var lines []string
+	if s.Attrs&Underline != 0 {
+		lines = append(lines, "underline")
+	}
+	if s.Attrs&Blink != 0 {
+		lines = append(lines, "blink")
+	}
+	if len(lines) > 0 {
+		decls = append(decls, "text-decoration:"+strings.Join(lines, " "))
+	}
+	return strings.Join(decls, ";")
+}
+
+// cssColor returns c as a CSS color, or fallback when c is not set.
+func cssColor(c Color, fallback string) string {
+	if !c.Valid {
+		return fallback
+	}
+	return c.Hex()
+}
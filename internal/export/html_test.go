@@ -0,0 +1,82 @@
+package export_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vxanthio/ascii-art-color/internal/export"
+)
+
+func TestHTML(t *testing.T) {
+	rows := []string{
+		"<a>\033[38;2;255;0;0m&b\033[0m",
+		"\033[1;48;2;0;0;128m \033[0m\033[38;2;255;0;0m\"\033[0m",
+	}
+
+	tests := []struct {
+		name string
+		opts export.HTMLOptions
+		want string
+	}{
+		{
+			name: "inline fragment",
+			want: "<pre class=\"ascii-art\">&lt;a&gt;<span style=\"color:#ff0000\">&amp;b</span>\n" +
+				"<span style=\"background-color:#000080;font-weight:bold\"> </span><span style=\"color:#ff0000\">&#34;</span>\n" +
+				"</pre>\n",
+		},
+		{
+			name: "classes fragment",
+			opts: export.HTMLOptions{Classes: true},
+			want: "<style>\n" +
+				".aa-s1 { color:#ff0000 }\n" +
+				".aa-s2 { background-color:#000080;font-weight:bold }\n" +
+				"</style>\n" +
+				"<pre class=\"ascii-art\">&lt;a&gt;<span class=\"aa-s1\">&amp;b</span>\n" +
+				"<span class=\"aa-s2\"> </span><span class=\"aa-s1\">&#34;</span>\n" +
+				"</pre>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := export.HTML(rows, tt.opts); got != tt.want {
+				t.Errorf("HTML() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTML_Document(t *testing.T) {
+	got := export.HTML([]string{"\033[38;2;0;255;0mx\033[0m"}, export.HTMLOptions{Document: true, Classes: true, Title: "a<b"})
+
+	for _, want := range []string{
+		"<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>a&lt;b</title>\n<style>\n.aa-s1 { color:#00ff00 }\n</style>\n</head>\n<body>\n",
+		"<pre class=\"ascii-art\"><span class=\"aa-s1\">x</span>\n</pre>\n</body>\n</html>\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("HTML() =\n%s\nwant it to contain\n%s", got, want)
+		}
+	}
+}
+
+func TestHTML_Attributes(t *testing.T) {
+	tests := []struct {
+		name string
+		row  string
+		want string
+	}{
+		{"reverse without colors", "\033[7mx", "color:Canvas;background-color:CanvasText"},
+		{"reverse with a foreground", "\033[7;38;2;255;0;0mx", "color:Canvas;background-color:#ff0000"},
+		{"dim and italic", "\033[2;3mx", "opacity:0.5;font-style:italic"},
+		{"underline and blink", "\033[4;5mx", "text-decoration:underline blink"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := export.HTML([]string{tt.row}, export.HTMLOptions{})
+			if !strings.Contains(got, "style=\""+tt.want+"\"") {
+				t.Errorf("HTML() = %q, want style %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package export
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+const (
+	// DefaultScale is the scale of raster images when ImageOptions.Scale is
+	// zero.
+	DefaultScale = 2
+	// dimAlpha is the opacity of dim text, out of 255.
+	dimAlpha = 128
+)
+
+// ImageOptions configures the output of Image and PNG.
+type ImageOptions struct {
+	// Scale is the size of a pixel of the bitmap font, in image pixels; zero
+	// means DefaultScale.
+	Scale int
+	// Padding is the width of the margin around the art, in image pixels.
+	Padding int
+	// Background fills the whole image; transparent when not valid.
+	Background Color
+	// Foreground is the color of text without a color of its own. When it
+	// is not valid, text is black, or white on a dark Background.
+	Foreground Color
+}
+
+// Image draws rendered rows with the built-in bitmap font.
+//
+// Every cell is GlyphWidth by GlyphHeight font pixels, each drawn as a
+// square of opts.Scale image pixels, so the image is as wide as the longest
+// row and as high as the number of rows, plus the padding on every side.
+// Colored characters are drawn in their color and text backgrounds fill
+// their cells. Bold text is drawn twice, one pixel apart; dim text is drawn
+// half transparent; italic text leans right; underlined text has its last
+// pixel row filled. Blinking is not drawn. Characters outside printable ASCII
+// are drawn as boxes.
+//
+// Parameters:
+//   - rows: The rendered rows, possibly containing ANSI escape sequences.
+//   - opts: The output options.
+//
+// Returns:
+//   - The image.
+func Image(rows []string, opts ImageOptions) *image.RGBA {
+	scale := opts.Scale
+	if scale <= 0 {
+		scale = DefaultScale
+	}
+	padding := max(opts.Padding, 0)
+	opts.Foreground = textColor(opts.Foreground, opts.Background)
+
+	grid, columns := parseGrid(rows)
+	img := newCanvas(artSize(columns, len(grid), scale, padding), opts.Background)
+	drawGrid(img, grid, image.Pt(padding, padding), scale, opts)
+	return img
+}
+
+// parseGrid decodes rendered rows into cells.
+//
+// Returns:
+//   - The cells of every row.
+//   - The number of columns of the longest row.
+func parseGrid(rows []string) ([][]Cell, int) {
+	grid := make([][]Cell, len(rows))
+	columns := 0
+	for i, row := range rows {
+		grid[i] = ParseRow(row)
+		columns = max(columns, len(grid[i]))
+	}
+	return grid, columns
+}
+
+// artSize returns the size in image pixels of columns by rows cells drawn at
+// scale, with padding on every side.
+func artSize(columns, rows, scale, padding int) image.Point {
+	return image.Pt(columns*GlyphWidth*scale+2*padding, rows*GlyphHeight*scale+2*padding)
+}
+
+// newCanvas returns an image of the given size filled with background, or
+// transparent when background is not valid.
+func newCanvas(size image.Point, background Color) *image.RGBA {
+	img := image.NewRGBA(image.Rectangle{Max: size})
+	if background.Valid {
+		fill(img, img.Bounds(), rgba(background))
+	}
+	return img
+}
+
+// drawGrid draws cells with the top left corner of the first cell at origin;
+// cells outside the image are clipped.
+func drawGrid(img *image.RGBA, grid [][]Cell, origin image.Point, scale int, opts ImageOptions) {
+	for i, cells := range grid {
+		for column, cell := range cells {
+			drawCell(img, origin.Add(image.Pt(column*GlyphWidth*scale, i*GlyphHeight*scale)), scale, cell, opts)
+		}
+	}
+}
+
+// PNG writes rendered rows as a PNG image drawn by Image.
+//
+// Parameters:
+//   - w: The destination of the image.
+//   - rows: The rendered rows, possibly containing ANSI escape sequences.
+//   - opts: The output options.
+//
+// Returns:
+//   - An error if the image cannot be encoded or written.
+func PNG(w io.Writer, rows []string, opts ImageOptions) error {
+	return png.Encode(w, Image(rows, opts))
+}
+
+// drawCell draws one cell with its top left corner at origin.
+func drawCell(img *image.RGBA, origin image.Point, scale int, cell Cell, opts ImageOptions) {
+	foreground, background := imageColors(cell.Style, opts)
+	if background.Valid {
+		fill(img, image.Rectangle{Min: origin, Max: origin.Add(image.Pt(GlyphWidth*scale, GlyphHeight*scale))}, rgba(background))
+	}
+	if cell.Rune == ' ' && cell.Style.Attrs&Underline == 0 {
+		return
+	}
+
+	ink := rgba(foreground)
+	if cell.Style.Attrs&Dim != 0 {
+		ink = color.RGBA{R: ink.R, G: ink.G, B: ink.B, A: dimAlpha}
+	}
+	rows := glyph(cell.Rune)
+	if cell.Style.Attrs&Underline != 0 {
+		rows[GlyphHeight-1] = "######"
+	}
+	for y, row := range rows {
+		shift := 0
+		if cell.Style.Attrs&Italic != 0 {
+			shift = (GlyphHeight - 1 - y) / 4
+		}
+		for x := 0; x < GlyphWidth; x++ {
+			if !inked(row, x) && !(cell.Style.Attrs&Bold != 0 && inked(row, x-1)) {
+				continue
+			}
+			if x+shift >= GlyphWidth {
+				continue
+			}
+			pixel := origin.Add(image.Pt((x+shift)*scale, y*scale))
+			fill(img, image.Rectangle{Min: pixel, Max: pixel.Add(image.Pt(scale, scale))}, ink)
+		}
+	}
+}
+
+// inked reports whether pixel x of a glyph row is set.
+func inked(row string, x int) bool {
+	return x >= 0 && x < len(row) && row[x] == '#'
+}
+
+// imageColors returns the text and background colors of a style. The
+// background is not valid where the image shows through. Reverse video swaps
+// the colors, taking the image's default colors where a color is not set.
+func imageColors(s Style, opts ImageOptions) (foreground, background Color) {
+	foreground, background = s.Foreground, s.Background
+	if !foreground.Valid {
+		foreground = opts.Foreground
+	}
+	if s.Attrs&Reverse == 0 {
+		return foreground, background
+	}
+	if !background.Valid {
+		background = opts.Background
+		if !background.Valid {
+			background = Color{R: 255, G: 255, B: 255, Valid: true}
+		}
+	}
+	return background, foreground
+}
+
+// textColor returns the default text color of an image: foreground when it
+// is set, otherwise black, or white on a dark background.
+func textColor(foreground, background Color) Color {
+	if foreground.Valid {
+		return foreground
+	}
+	if background.Valid && isDark(background) {
+		return Color{R: 255, G: 255, B: 255, Valid: true}
+	}
+	return Color{Valid: true}
+}
+
+// rgba returns c as an opaque image color.
+func rgba(c Color) color.RGBA {
+	return color.RGBA{R: c.R, G: c.G, B: c.B, A: 255}
+}
+
+// fill paints the rectangle r of img with c, blending c over the existing
+// pixels when it is not opaque.
+func fill(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	r = r.Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if c.A == 255 {
+				img.SetRGBA(x, y, c)
+				continue
+			}
+			img.SetRGBA(x, y, over(c, img.RGBAAt(x, y)))
+		}
+	}
+}
+
+// over composites the non-premultiplied color c over the pixel dst.
+func over(c, dst color.RGBA) color.RGBA {
+	a := uint32(c.A)
+	blend := func(src, dst uint8) uint8 {
+		return uint8((uint32(src)*a + uint32(dst)*(255-a)) / 255)
+	}
+	return color.RGBA{R: blend(c.R, dst.R), G: blend(c.G, dst.G), B: blend(c.B, dst.B), A: blend(255, dst.A)}
+}
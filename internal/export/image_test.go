@@ -0,0 +1,103 @@
+package export_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/vxanthio/ascii-art-color/internal/export"
+)
+
+var (
+	black = color.RGBA{A: 255}
+	white = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	clear = color.RGBA{}
+)
+
+func TestImage_Size(t *testing.T) {
+	tests := []struct {
+		name string
+		rows []string
+		opts export.ImageOptions
+		want image.Rectangle
+	}{
+		{"default scale", []string{"ab", "c"}, export.ImageOptions{}, image.Rect(0, 0, 24, 32)},
+		{"scale and padding", []string{"ab", "c"}, export.ImageOptions{Scale: 3, Padding: 5}, image.Rect(0, 0, 46, 58)},
+		{"escapes take no cells", []string{"\033[38;2;255;0;0mab\033[0m"}, export.ImageOptions{Scale: 1}, image.Rect(0, 0, 12, 8)},
+		{"no rows", nil, export.ImageOptions{Padding: 4}, image.Rect(0, 0, 8, 8)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := export.Image(tt.rows, tt.opts).Bounds(); got != tt.want {
+				t.Errorf("Image().Bounds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImage_Pixels(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	blue := color.RGBA{B: 255, A: 255}
+	navy := export.Color{B: 128, Valid: true}
+
+	tests := []struct {
+		name  string
+		row   string
+		opts  export.ImageOptions
+		point image.Point
+		want  color.RGBA
+	}{
+		{"transparent canvas", "|", export.ImageOptions{}, image.Pt(0, 0), clear},
+		{"glyph pixel", "|", export.ImageOptions{}, image.Pt(2, 7), black},
+		{"canvas", "|", export.ImageOptions{Background: export.Color{R: 255, G: 255, B: 255, Valid: true}}, image.Pt(0, 0), white},
+		{"white text on dark canvas", "|", export.ImageOptions{Background: navy}, image.Pt(2, 0), white},
+		{"foreground option", "|", export.ImageOptions{Foreground: export.Color{R: 255, Valid: true}}, image.Pt(2, 0), red},
+		{"padding", "|", export.ImageOptions{Padding: 1}, image.Pt(3, 1), black},
+		{"colored text", "\033[38;2;255;0;0m|\033[0m", export.ImageOptions{}, image.Pt(2, 3), red},
+		{"text background", "\033[48;2;0;0;255m \033[0m", export.ImageOptions{}, image.Pt(5, 7), blue},
+		{"reverse", "\033[7m \033[0m", export.ImageOptions{}, image.Pt(0, 0), black},
+		{"reverse text", "\033[7m|\033[0m", export.ImageOptions{}, image.Pt(2, 0), white},
+		{"bold", "\033[1m|\033[0m", export.ImageOptions{}, image.Pt(3, 0), black},
+		{"not bold", "|", export.ImageOptions{}, image.Pt(3, 0), clear},
+		{"underline", "\033[4m \033[0m", export.ImageOptions{}, image.Pt(0, 7), black},
+		{"italic", "\033[3m|\033[0m", export.ImageOptions{}, image.Pt(3, 0), black},
+		{"dim", "\033[2m|\033[0m", export.ImageOptions{Background: export.Color{R: 255, G: 255, B: 255, Valid: true}}, image.Pt(2, 0), color.RGBA{R: 127, G: 127, B: 127, A: 255}},
+		{"missing glyph", "é", export.ImageOptions{}, image.Pt(0, 0), black},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Scale = 1
+			if got := export.Image([]string{tt.row}, tt.opts).RGBAAt(tt.point.X, tt.point.Y); got != tt.want {
+				t.Errorf("pixel %v = %v, want %v", tt.point, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImage_Scale(t *testing.T) {
+	img := export.Image([]string{"|"}, export.ImageOptions{Scale: 3})
+	for _, p := range []image.Point{{6, 0}, {8, 2}, {8, 23}} {
+		if got := img.RGBAAt(p.X, p.Y); got != black {
+			t.Errorf("pixel %v = %v, want %v", p, got, black)
+		}
+	}
+	if got := img.RGBAAt(9, 0); got != clear {
+		t.Errorf("pixel (9,0) = %v, want %v", got, clear)
+	}
+}
+
+func TestPNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := export.PNG(&buf, []string{"ab"}, export.ImageOptions{Padding: 2}); err != nil {
+		t.Fatalf("PNG() error = %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+	if got, want := img.Bounds(), image.Rect(0, 0, 28, 20); got != want {
+		t.Errorf("decoded bounds = %v, want %v", got, want)
+	}
+}
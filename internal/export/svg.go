@@ -0,0 +1,187 @@
+package export
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultFontSize is the font size of SVG images, in pixels, when
+	// SVGOptions.FontSize is zero.
+	DefaultFontSize = 14
+	// cellAspect is the width of a monospace cell relative to the font size.
+	cellAspect = 0.6
+	// lineSpacing is the height of a row relative to the font size.
+	lineSpacing = 1.2
+	// baselineOffset places the baseline of a row relative to its top, as a
+	// fraction of the font size.
+	baselineOffset = 0.95
+)
+
+// SVGOptions configures the output of SVG.
+type SVGOptions struct {
+	// FontSize is the font size in pixels; zero means DefaultFontSize.
+	FontSize float64
+	// Background fills the whole image; transparent when not valid.
+	Background Color
+	// Foreground is the color of text without a color of its own. When it
+	// is not valid, text is black, or white on a dark Background.
+	Foreground Color
+	// Cells writes every non-blank cell as its own positioned <tspan>,
+	// instead of one <text> element per row with a <tspan> per styled run.
+	Cells bool
+}
+
+// SVG writes rendered rows as an SVG image in a monospace font.
+//
+// The image is sized to the art: each cell is 0.6 times the font size wide
+// and each row 1.2 times the font size high, so the image is as wide as the
+// longest row and as high as the number of rows. Rows are stretched to their
+// exact width with textLength, whatever the metrics of the font. Colored
+// runs are filled with their color, and text backgrounds are drawn as
+// rectangles behind the text.
+//
+// Parameters:
+//   - rows: The rendered rows, possibly containing ANSI escape sequences.
+//   - opts: The output options.
+//
+// Returns:
+//   - The SVG document, ending with a newline.
+func SVG(rows []string, opts SVGOptions) string {
+	size := opts.FontSize
+	if size <= 0 {
+		size = DefaultFontSize
+	}
+	cellWidth, rowHeight := size*cellAspect, size*lineSpacing
+
+	grid := make([][]Cell, len(rows))
+	columns := 0
+	for i, row := range rows {
+		grid[i] = ParseRow(row)
+		columns = max(columns, len(grid[i]))
+	}
+	width, height := float64(columns)*cellWidth, float64(len(rows))*rowHeight
+	opts.Foreground = textColor(opts.Foreground, opts.Background)
+
+	var b strings.Builder
+	b.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%s\" height=\"%s\" viewBox=\"0 0 %s %s\" font-family=\"monospace\" font-size=\"%s\" fill=\"%s\" xml:space=\"preserve\">\n",
+		num(width), num(height), num(width), num(height), num(size), opts.Foreground.Hex())
+	if opts.Background.Valid {
+		fmt.Fprintf(&b, "<rect width=\"100%%\" height=\"100%%\" fill=\"%s\"/>\n", opts.Background.Hex())
+	}
+
+	for i, cells := range grid {
+		top := float64(i) * rowHeight
+		column := 0
+		for _, span := range Spans(cells) {
+			length := utf8.RuneCountInString(span.Text)
+			if _, background := svgColors(span.Style, opts); background != "" {
+				fmt.Fprintf(&b, "<rect x=\"%s\" y=\"%s\" width=\"%s\" height=\"%s\" fill=\"%s\"/>\n",
+					num(float64(column)*cellWidth), num(top), num(float64(length)*cellWidth), num(rowHeight), background)
+			}
+			column += length
+		}
+	}
+
+	for i, cells := range grid {
+		if strings.TrimSpace(string(runes(cells))) == "" && !opts.Cells {
+			continue
+		}
+		baseline := float64(i)*rowHeight + size*baselineOffset
+		if opts.Cells {
+			writeCells(&b, cells, baseline, cellWidth, opts)
+			continue
+		}
+		fmt.Fprintf(&b, "<text y=\"%s\" textLength=\"%s\" lengthAdjust=\"spacingAndGlyphs\">",
+			num(baseline), num(float64(len(cells))*cellWidth))
+		for _, span := range Spans(cells) {
+			text := html.EscapeString(span.Text)
+			if attrs := svgAttrs(span.Style, opts); attrs != "" {
+				fmt.Fprintf(&b, "<tspan%s>%s</tspan>", attrs, text)
+			} else {
+				b.WriteString(text)
+			}
+		}
+		b.WriteString("</text>\n")
+	}
+
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// writeCells writes the non-blank cells of a row as one <text> element with
+// a <tspan> per cell, each placed at its own column.
+func writeCells(b *strings.Builder, cells []Cell, baseline, cellWidth float64, opts SVGOptions) {
+	written := false
+	for column, cell := range cells {
+		if cell.Rune == ' ' {
+			continue
+		}
+		if !written {
+			fmt.Fprintf(b, "<text y=\"%s\">", num(baseline))
+			written = true
+		}
+		fmt.Fprintf(b, "<tspan x=\"%s\"%s>%s</tspan>",
+			num(float64(column)*cellWidth), svgAttrs(cell.Style, opts), html.EscapeString(string(cell.Rune)))
+	}
+	if written {
+		b.WriteString("</text>\n")
+	}
+}
+
+// svgAttrs returns the presentation attributes of a style, each preceded by
+// a space; empty for plain text.
+func svgAttrs(s Style, opts SVGOptions) string {
+	var attrs strings.Builder
+	if fill, _ := svgColors(s, opts); fill != "" {
+		fmt.Fprintf(&attrs, " fill=\"%s\"", fill)
+	}
+	if s.Attrs&Bold != 0 {
+		attrs.WriteString(" font-weight=\"bold\"")
+	}
+	if s.Attrs&Dim != 0 {
+		attrs.WriteString(" fill-opacity=\"0.5\"")
+	}
+	if s.Attrs&Italic != 0 {
+		attrs.WriteString(" font-style=\"italic\"")
+	}
+	if s.Attrs&Underline != 0 {
+		attrs.WriteString(" text-decoration=\"underline\"")
+	}
+	return attrs.String()
+}
+
+// svgColors returns the text and background colors of a style as SVG
+// colors; empty where the default applies. Reverse video swaps them, taking
+// the image's default colors where a color is not set.
+func svgColors(s Style, opts SVGOptions) (fill, background string) {
+	if s.Attrs&Reverse == 0 {
+		return cssColor(s.Foreground, ""), cssColor(s.Background, "")
+	}
+	return cssColor(s.Background, cssColor(opts.Background, "#ffffff")), cssColor(s.Foreground, opts.Foreground.Hex())
+}
+
+// isDark reports whether c is closer to black than to white, by its relative
+// luminance.
+func isDark(c Color) bool {
+	return 0.2126*float64(c.R)+0.7152*float64(c.G)+0.0722*float64(c.B) < 128
+}
+
+// runes returns the characters of cells.
+func runes(cells []Cell) []rune {
+	r := make([]rune, len(cells))
+	for i, cell := range cells {
+		r[i] = cell.Rune
+	}
+	return r
+}
+
+// num formats a length with at most two decimals.
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
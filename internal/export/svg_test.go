@@ -0,0 +1,77 @@
+package export_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vxanthio/ascii-art-color/internal/export"
+)
+
+func TestSVG(t *testing.T) {
+	rows := []string{
+		"<\033[38;2;255;0;0mab\033[0m ",
+		"    ",
+		"\033[1;48;2;0;0;128mc\033[0m&",
+	}
+
+	got := export.SVG(rows, export.SVGOptions{FontSize: 10})
+	want := "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+		"<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"24\" height=\"36\" viewBox=\"0 0 24 36\" font-family=\"monospace\" font-size=\"10\" fill=\"#000000\" xml:space=\"preserve\">\n" +
+		"<rect x=\"0\" y=\"24\" width=\"6\" height=\"12\" fill=\"#000080\"/>\n" +
+		"<text y=\"9.5\" textLength=\"24\" lengthAdjust=\"spacingAndGlyphs\">&lt;<tspan fill=\"#ff0000\">ab</tspan> </text>\n" +
+		"<text y=\"33.5\" textLength=\"12\" lengthAdjust=\"spacingAndGlyphs\"><tspan font-weight=\"bold\">c</tspan>&amp;</text>\n" +
+		"</svg>\n"
+	if got != want {
+		t.Errorf("SVG() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSVG_Cells(t *testing.T) {
+	got := export.SVG([]string{"a \033[38;2;0;255;0mb\033[0m", "  "}, export.SVGOptions{FontSize: 10, Cells: true})
+	want := "<text y=\"9.5\"><tspan x=\"0\">a</tspan><tspan x=\"12\" fill=\"#00ff00\">b</tspan></text>\n</svg>\n"
+	if !strings.HasSuffix(got, want) {
+		t.Errorf("SVG() =\n%s\nwant it to end with\n%s", got, want)
+	}
+}
+
+func TestSVG_Background(t *testing.T) {
+	tests := []struct {
+		name string
+		opts export.SVGOptions
+		rows []string
+		want []string
+	}{
+		{
+			name: "dark background gets white text",
+			opts: export.SVGOptions{Background: export.Color{B: 64, Valid: true}},
+			rows: []string{"x"},
+			want: []string{" font-size=\"14\" fill=\"#ffffff\"", "<rect width=\"100%\" height=\"100%\" fill=\"#000040\"/>"},
+		},
+		{
+			name: "light background keeps black text",
+			opts: export.SVGOptions{Background: export.Color{R: 250, G: 250, B: 250, Valid: true}},
+			rows: []string{"x"},
+			want: []string{" fill=\"#000000\""},
+		},
+		{
+			name: "reverse video uses the image colors",
+			opts: export.SVGOptions{Foreground: export.Color{R: 1, G: 2, B: 3, Valid: true}},
+			rows: []string{"\033[7mx"},
+			want: []string{
+				"<rect x=\"0\" y=\"0\" width=\"8.4\" height=\"16.8\" fill=\"#010203\"/>",
+				"<tspan fill=\"#ffffff\">x</tspan>",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := export.SVG(tt.rows, tt.opts)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("SVG() =\n%s\nwant it to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
@@ -1,79 +1,265 @@
-// Package flagparser validates command-line arguments for the ascii-art-color program.
+// Package flagparser parses command-line options and positional arguments for
+// the ascii-art-color program.
 //
-// Its responsibility is limited to validating input format and structure.
-// It does NOT perform rendering or color validation logic.
+// Options are declared as a list of Option values, each with a long name and
+// an optional one-letter short name. The same list drives parsing and the
+// generated help text, so a new option needs a single declaration.
 //
-// It ensures:
-//   - the correct number of arguments is provided
-//   - the --color flag (if present) appears in the correct position
-//   - only one --color flag is used
-//   - the --color flag contains a non-empty value
+// Syntax rules:
+//   - Long options take their value after an equals sign: --width=40. The
+//     next argument is never a long option's value, so --width 40 is an
+//     error.
+//   - Short options take their value attached or as the next argument:
+//     -w40 or -w 40. Short switches can be grouped: -hv.
+//   - Options and positional arguments may appear in any order.
+//   - "--" ends the options; every later argument is positional.
+//   - A lone "-" is a positional argument (conventionally standard input),
+//     and so is an argument whose first character after "-" is not a short
+//     option, such as the negative number -5.
+//   - An option given several times keeps every value, in order.
 //
-// Any invalid input results in a usage error.
+// Its responsibility is limited to syntax. It does NOT interpret option
+// values or positional arguments; unknown options, missing values and values
+// given to switches are reported as errors.
 package flagparser
 
 import (
-	"errors"
+	"fmt"
 	"strings"
+	"unicode/utf8"
 )
 
-// Argument count boundaries according to the project specification.
-const (
-	minimumArgs = 2
-	maximumArgs = 5
-)
+// Option declares a command-line option.
+type Option struct {
+	// Name is the long name, used as --Name.
+	Name string
+	// Short is the one-letter name, used as -Short; zero for none.
+	Short rune
+	// Value names the option's value in the help text, such as "COLUMNS".
+	// Empty declares a switch, which takes no value.
+	Value string
+	// Usage is the one-line description shown in the help text.
+	Usage string
+}
+
+// takesValue reports whether the option requires a value.
+func (o Option) takesValue() bool {
+	return o.Value != ""
+}
 
-// errUsage is the single user-facing error returned for any invalid CLI input.
-// This keeps command-line output consistent and predictable.
+// Result holds the parsed options and positional arguments.
+type Result struct {
+	values map[string][]string
+	// Args holds the positional arguments, in order.
+	Args []string
+}
+
+// Value returns the last value given for the option with the given long name.
+//
+// Parameters:
+//   - name: The long name of the option.
 //
-//nolint:staticcheck // ST1005: capitalized per project specification
-var errUsage = errors.New("Usage: go run . [OPTION] [STRING]\n\nEX: go run . --color=<color> <substring to be colored> \"something\"")
+// Returns:
+//   - The value; empty for switches.
+//   - false if the option was not given.
+func (r *Result) Value(name string) (string, bool) {
+	values := r.values[name]
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[len(values)-1], true
+}
 
-// ParseArgs validates the provided command-line arguments.
+// Values returns every value given for the option with the given long name,
+// in command-line order.
 //
-// The function checks argument count boundaries, flag syntax, flag position,
-// and ensures the --color flag (if present) contains a non-empty value.
+// Parameters:
+//   - name: The long name of the option.
+//
+// Returns:
+//   - The values; nil if the option was not given.
+func (r *Result) Values(name string) []string {
+	return r.values[name]
+}
+
+// IsSet reports whether the option with the given long name was given.
 //
 // Parameters:
-//   - args: The command-line arguments including the program name (os.Args).
+//   - name: The long name of the option.
 //
 // Returns:
-//   - An error if the arguments are invalid, nil otherwise.
-func ParseArgs(args []string) error {
-	colorFlagCount := 0
+//   - true if the option appears on the command line, false otherwise.
+func (r *Result) IsSet(name string) bool {
+	return len(r.values[name]) > 0
+}
 
-	if len(args) < minimumArgs || len(args) > maximumArgs {
-		return errUsage
+// Parse parses args against the declared options.
+//
+// Parameters:
+//   - options: The options the command accepts.
+//   - args: The command-line arguments, without the program name.
+//
+// Returns:
+//   - The parsed options and positional arguments.
+//   - An error for an unknown option, a missing value, or a value given to a
+//     switch.
+func Parse(options []Option, args []string) (*Result, error) {
+	p := parser{
+		options: options,
+		result:  &Result{values: make(map[string][]string)},
 	}
 
-	if strings.HasPrefix(args[1], "-") && !strings.HasPrefix(args[1], "--color=") {
-		return errUsage
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		var err error
+		switch {
+		case arg == "--":
+			p.result.Args = append(p.result.Args, args[i+1:]...)
+			return p.result, nil
+		case strings.HasPrefix(arg, "--"):
+			err = p.parseLong(arg)
+		case p.isShortGroup(arg):
+			var consumed bool
+			consumed, err = p.parseShort(arg, args[i+1:])
+			if consumed {
+				i++
+			}
+		default:
+			p.result.Args = append(p.result.Args, arg)
+		}
+		if err != nil {
+			return nil, err
+		}
 	}
+	return p.result, nil
+}
 
-	for i := 1; i < len(args); i++ {
-		if strings.HasPrefix(args[i], "--color=") {
-			colorFlagCount++
+// parser carries the state of a single Parse call.
+type parser struct {
+	options []Option
+	result  *Result
+}
 
-			if colorFlagCount > 1 {
-				return errUsage
-			}
+// parseLong parses a --name or --name=value argument.
+func (p *parser) parseLong(arg string) error {
+	name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+	option, ok := p.lookupLong(name)
+	if !ok {
+		return fmt.Errorf("unknown option %q", arg)
+	}
 
-			if i != 1 {
-				return errUsage
-			}
+	switch {
+	case option.takesValue() && !hasValue:
+		return fmt.Errorf("missing value for --%s=<%s>: long options take their value after \"=\"", option.Name, strings.ToLower(option.Value))
+	case option.takesValue() && value == "":
+		return fmt.Errorf("empty value for --%s=<%s>", option.Name, strings.ToLower(option.Value))
+	case !option.takesValue() && hasValue:
+		return fmt.Errorf("option --%s does not take a value", option.Name)
+	}
+	p.set(option, value)
+	return nil
+}
+
+// isShortGroup reports whether arg is a group of short options: a "-"
+// followed by a declared short option. Other arguments starting with "-",
+// such as -5, are positional.
+func (p *parser) isShortGroup(arg string) bool {
+	if len(arg) < 2 || arg[0] != '-' {
+		return false
+	}
+	short, _ := utf8.DecodeRuneInString(arg[1:])
+	_, ok := p.lookupShort(short)
+	return ok
+}
+
+// parseShort parses a group of short options such as -h, -hv, -w40 or -w.
+// In the last form the value is taken from the next argument, and consumed
+// reports that it was used.
+func (p *parser) parseShort(arg string, next []string) (consumed bool, err error) {
+	group := strings.TrimPrefix(arg, "-")
+	for group != "" {
+		short, size := utf8.DecodeRuneInString(group)
+		group = group[size:]
+
+		option, ok := p.lookupShort(short)
+		if !ok {
+			return false, fmt.Errorf("unknown option \"-%c\" in %q", short, arg)
+		}
+		if !option.takesValue() {
+			p.set(option, "")
+			continue
+		}
+
+		switch {
+		case group != "":
+			p.set(option, group)
+		case len(next) > 0:
+			p.set(option, next[0])
+			consumed = true
+		default:
+			return false, fmt.Errorf("missing value for -%c <%s>", short, strings.ToLower(option.Value))
+		}
+		return consumed, nil
+	}
+	return false, nil
+}
+
+// set records a value for option.
+func (p *parser) set(option Option, value string) {
+	p.result.values[option.Name] = append(p.result.values[option.Name], value)
+}
+
+// lookupLong finds the option with the given long name.
+func (p *parser) lookupLong(name string) (Option, bool) {
+	for _, option := range p.options {
+		if option.Name == name {
+			return option, true
 		}
 	}
+	return Option{}, false
+}
 
-	if strings.HasPrefix(args[1], "--color=") && len(args) < 3 {
-		return errUsage
+// lookupShort finds the option with the given short name.
+func (p *parser) lookupShort(short rune) (Option, bool) {
+	for _, option := range p.options {
+		if option.Short != 0 && option.Short == short {
+			return option, true
+		}
 	}
+	return Option{}, false
+}
 
-	if strings.HasPrefix(args[1], "--color=") {
-		_, color, found := strings.Cut(args[1], "=")
-		if !found || color == "" {
-			return errUsage
+// Help formats the option list of a help text, one option per line, with the
+// descriptions aligned in a column:
+//
+//	-w, --width=COLUMNS  Maximum output width
+//	    --fallback=CHAR  Character drawn for missing glyphs
+//
+// Parameters:
+//   - options: The options to describe, in the order to list them.
+//
+// Returns:
+//   - The formatted option list, ending with a newline.
+func Help(options []Option) string {
+	labels := make([]string, len(options))
+	column := 0
+	for i, option := range options {
+		label := "    "
+		if option.Short != 0 {
+			label = fmt.Sprintf("-%c, ", option.Short)
 		}
+		label += "--" + option.Name
+		if option.takesValue() {
+			label += "=" + option.Value
+		}
+		labels[i] = label
+		column = max(column, utf8.RuneCountInString(label))
 	}
 
-	return nil
+	var b strings.Builder
+	for i, option := range options {
+		padding := column - utf8.RuneCountInString(labels[i]) + 2
+		fmt.Fprintf(&b, "  %s%s%s\n", labels[i], strings.Repeat(" ", padding), option.Usage)
+	}
+	return b.String()
 }
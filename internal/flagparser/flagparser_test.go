@@ -1,94 +1,185 @@
 package flagparser_test
 
 import (
+	"strings"
 	"testing"
 
-	"ascii-art-color/internal/flagparser"
+	"github.com/vxanthio/ascii-art-color/internal/flagparser"
 )
 
-func TestParseArgs(t *testing.T) {
+var testOptions = []flagparser.Option{
+	{Name: "color", Short: 'c', Value: "COLOR", Usage: "Color the text"},
+	{Name: "width", Short: 'w', Value: "COLUMNS", Usage: "Wrap output"},
+	{Name: "fallback", Value: "CHAR", Usage: "Fallback character"},
+	{Name: "help", Short: 'h', Usage: "Show help"},
+	{Name: "verbose", Short: 'v', Usage: "Verbose output"},
+}
+
+func TestParse(t *testing.T) {
 	tests := []struct {
-		name    string
-		args    []string
-		wantErr bool
+		name     string
+		args     []string
+		wantArgs []string
+		want     map[string][]string
 	}{
 		{
-			name:    "no arguments",
-			args:    []string{"program"},
-			wantErr: true,
+			name:     "positional only",
+			args:     []string{"hello", "shadow"},
+			wantArgs: []string{"hello", "shadow"},
+		},
+		{
+			name:     "long option with value",
+			args:     []string{"--color=red", "hello"},
+			wantArgs: []string{"hello"},
+			want:     map[string][]string{"color": {"red"}},
 		},
 		{
-			name:    "too many arguments",
-			args:    []string{"program", "banner", "--color=red", "substring", "some text", "EXTRA"},
-			wantErr: true,
+			name:     "value containing equals sign",
+			args:     []string{"--color=a=b"},
+			want:     map[string][]string{"color": {"a=b"}},
+			wantArgs: nil,
 		},
 		{
-			name:    "invalid color flag prefix",
-			args:    []string{"program", "-color:black", "some text"},
-			wantErr: true,
+			name:     "options in any position",
+			args:     []string{"sub", "--width=40", "text", "--color=red"},
+			wantArgs: []string{"sub", "text"},
+			want:     map[string][]string{"color": {"red"}, "width": {"40"}},
 		},
 		{
-			name:    "invalid color flag format without equals",
-			args:    []string{"program", "--color:red", "some text"},
-			wantErr: true,
+			name:     "short option with separate value",
+			args:     []string{"-w", "40", "hello"},
+			wantArgs: []string{"hello"},
+			want:     map[string][]string{"width": {"40"}},
 		},
 		{
-			name:    "valid single string",
-			args:    []string{"program", "text"},
-			wantErr: false,
+			name:     "dash arguments that are not short options",
+			args:     []string{"-5", "-x", "-1.5"},
+			wantArgs: []string{"-5", "-x", "-1.5"},
 		},
 		{
-			name:    "color flag with string and no substring",
-			args:    []string{"program", "--color=red", "text"},
-			wantErr: false,
+			name:     "short option with attached value",
+			args:     []string{"-w40", "hello"},
+			wantArgs: []string{"hello"},
+			want:     map[string][]string{"width": {"40"}},
 		},
 		{
-			name:    "color flag with string and substring",
-			args:    []string{"program", "--color=red", "text", "substring"},
-			wantErr: false,
+			name:     "grouped switches",
+			args:     []string{"-vh"},
+			wantArgs: nil,
+			want:     map[string][]string{"verbose": {""}, "help": {""}},
 		},
 		{
-			name:    "missing string after color flag",
-			args:    []string{"program", "--color=red"},
-			wantErr: true,
+			name:     "switch grouped with value option",
+			args:     []string{"-vw40"},
+			wantArgs: nil,
+			want:     map[string][]string{"verbose": {""}, "width": {"40"}},
 		},
 		{
-			name:    "multiple color flags",
-			args:    []string{"program", "--color=red", "--color=blue", "text"},
-			wantErr: true,
+			name:     "long switch",
+			args:     []string{"hello", "--help"},
+			wantArgs: []string{"hello"},
+			want:     map[string][]string{"help": {""}},
 		},
 		{
-			name:    "invalid color flag position",
-			args:    []string{"program", "text", "--color=red"},
-			wantErr: true,
+			name:     "repeated option keeps every value",
+			args:     []string{"--color=red", "-c", "blue"},
+			wantArgs: nil,
+			want:     map[string][]string{"color": {"red", "blue"}},
 		},
 		{
-			name:    "empty color value",
-			args:    []string{"program", "--color=", "text"},
-			wantErr: true,
+			name:     "double dash ends options",
+			args:     []string{"--width=40", "--", "--color=red", "-w"},
+			wantArgs: []string{"--color=red", "-w"},
+			want:     map[string][]string{"width": {"40"}},
 		},
 		{
-			name:    "valid banner with color",
-			args:    []string{"program", "--color=red", "text", "standard"},
-			wantErr: false,
+			name:     "lone dash is positional",
+			args:     []string{"-", "shadow"},
+			wantArgs: []string{"-", "shadow"},
 		},
 		{
-			name:    "valid banner without color",
-			args:    []string{"program", "text", "standard"},
-			wantErr: false,
+			name:     "multi-byte value",
+			args:     []string{"--fallback=¿"},
+			want:     map[string][]string{"fallback": {"¿"}},
+			wantArgs: nil,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := flagparser.ParseArgs(tt.args)
+			result, err := flagparser.Parse(testOptions, tt.args)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(result.Args, "|") != strings.Join(tt.wantArgs, "|") || len(result.Args) != len(tt.wantArgs) {
+				t.Errorf("Args = %q, want %q", result.Args, tt.wantArgs)
+			}
+			for _, option := range testOptions {
+				got := result.Values(option.Name)
+				want := tt.want[option.Name]
+				if strings.Join(got, "|") != strings.Join(want, "|") || len(got) != len(want) {
+					t.Errorf("Values(%q) = %q, want %q", option.Name, got, want)
+				}
+				if result.IsSet(option.Name) != (len(want) > 0) {
+					t.Errorf("IsSet(%q) = %t, want %t", option.Name, result.IsSet(option.Name), len(want) > 0)
+				}
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"unknown long option", []string{"--colour=red"}, "unknown option"},
+		{"malformed long option", []string{"--color:red", "hello"}, "unknown option"},
+		{"long option without value", []string{"--color", "red"}, "missing value"},
+		{"long option with empty value", []string{"--color=", "red"}, "empty value"},
+		{"switch with value", []string{"--help=yes"}, "does not take a value"},
+		{"unknown option in a short group", []string{"-hx"}, "unknown option"},
+		{"short option without value", []string{"hello", "-w"}, "missing value"},
+	}
 
-			if tt.wantErr && err == nil {
-				t.Errorf("expected error, got nil")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := flagparser.Parse(testOptions, tt.args)
+			if err == nil {
+				t.Fatalf("expected error, got nil")
 			}
-			if !tt.wantErr && err != nil {
-				t.Errorf("unexpected error: %v", err)
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q does not contain %q", err, tt.want)
 			}
 		})
 	}
 }
+
+func TestResult_Value(t *testing.T) {
+	result, err := flagparser.Parse(testOptions, []string{"--color=red", "--color=blue"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if value, ok := result.Value("color"); !ok || value != "blue" {
+		t.Errorf("Value(color) = %q, %t; want last value %q", value, ok, "blue")
+	}
+	if value, ok := result.Value("width"); ok || value != "" {
+		t.Errorf("Value(width) = %q, %t; want unset", value, ok)
+	}
+}
+
+func TestHelp(t *testing.T) {
+	got := flagparser.Help(testOptions)
+	want := "" +
+		"  -c, --color=COLOR    Color the text\n" +
+		"  -w, --width=COLUMNS  Wrap output\n" +
+		"      --fallback=CHAR  Fallback character\n" +
+		"  -h, --help           Show help\n" +
+		"  -v, --verbose        Verbose output\n"
+
+	if got != want {
+		t.Errorf("Help() =\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -1,8 +1,18 @@
 // Package parser provides functionality for loading and parsing ASCII art banner files.
 //
 // The parser reads banner files containing ASCII art representations for printable
-// characters (range 32-126). Each banner file follows a strict format: 8 lines per
-// character definition plus 1 separator line, totaling 855 lines for 95 characters.
+// characters (range 32-126). Each banner file follows a strict format: every character
+// definition is one separator line followed by a fixed number of glyph rows. The
+// standard, shadow and thinkertoy banners use 8 rows, totaling 855 lines for 95
+// characters; the glyph height of any other banner is derived from its line count.
+//
+// A banner may define additional characters after the 95 standard ones. Each
+// extra definition replaces the blank separator line with a code-point tag such
+// as "U+00E9", optionally followed by a space and a description, and is followed
+// by the same number of glyph rows:
+//
+//	U+20AC EURO SIGN
+//	<Height glyph rows>
 //
 // Responsibilities of this package:
 //   - Read banner files from the provided filesystem
@@ -17,42 +27,53 @@ import (
 	"bytes"
 	"fmt"
 	"io/fs"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
 const (
 	firstPrintable rune = 32  // ASCII 32 (space)
 	lastPrintable  rune = 126 // ASCII 126 (tilde)
 	totalChars          = 95
-	expectedLines       = 855 // 95 chars × 9 lines (8 glyph + 1 separator)
-	linesPerGlyph       = 8
-	linesPerChar        = 9 // 8 glyph + 1 separator
+
+	codePointPrefix = "U+" // prefix of the separator line of an extra character
 )
 
 // Banner represents the ASCII-art data for all supported characters.
-type Banner map[rune][]string
+type Banner struct {
+	// Glyphs maps each character to its ASCII-art rows.
+	Glyphs map[rune][]string
+	// Height is the number of rows of every glyph.
+	Height int
+	// Baseline is the number of rows from the top of a glyph down to and
+	// including the row the letters stand on; rows below it hold descenders.
+	Baseline int
+}
 
 // LoadBanner reads a banner file from the provided filesystem and returns its parsed
 // representation as a Banner map.
 //
-// The function reads the specified banner file, validates its format (855 lines total),
-// and constructs a map associating each printable ASCII character (32-126) with its
-// 8-line ASCII art representation.
+// The function reads the specified banner file, validates its format (one separator
+// line plus Height glyph rows per character), and constructs a map associating each
+// printable ASCII character (32-126), and every extra character tagged with its
+// code point, with its ASCII art representation.
 //
 // Parameters:
 //   - fsys: The filesystem to read from (can be embed.FS, os.DirFS, or any fs.FS).
 //   - path: The file path within the filesystem (e.g., "testdata/standard.txt").
 //
 // Returns:
-//   - A Banner map containing all character definitions.
+//   - A Banner containing all character definitions and their height.
 //   - An error if the file cannot be read or the format is invalid.
 func LoadBanner(fsys fs.FS, path string) (Banner, error) {
 	lines, err := readLines(fsys, path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read banner file %q: %w", path, err)
+		return Banner{}, fmt.Errorf("failed to read banner file %q: %w", path, err)
 	}
 	banner, err := buildBanner(lines)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse banner %q: %w", path, err)
+		return Banner{}, fmt.Errorf("failed to parse banner %q: %w", path, err)
 	}
 	return banner, nil
 }
@@ -88,63 +109,158 @@ func readLines(fsys fs.FS, path string) ([]string, error) {
 	return lines, nil
 }
 
-// buildBanner constructs a Banner map from the raw lines read from a banner file.
+// buildBanner constructs a Banner from the raw lines read from a banner file.
 //
-// It validates the format (855 total lines, 95 characters with 8 lines each plus
-// 1 separator) and creates a mapping from each printable ASCII character (32-126)
-// to its 8-line ASCII art representation.
+// The standard section ends at the first code-point tag line (or at the end of
+// the file). Its glyph height is derived from its line count: 95 characters each
+// take one separator line plus Height glyph rows, so it has 95 × (Height + 1)
+// lines. It maps each printable ASCII character (32-126) to its ASCII art
+// representation, then adds the tagged extra characters that follow.
 //
 // Parameters:
 //   - lines: The raw lines from a banner file.
 //
 // Returns:
-//   - A Banner map containing all character definitions.
+//   - A Banner containing all character definitions.
 //   - An error if the format is invalid or incomplete.
 func buildBanner(lines []string) (Banner, error) {
 	if len(lines) == 0 {
-		return nil, fmt.Errorf("empty banner file")
+		return Banner{}, fmt.Errorf("empty banner file")
+	}
+
+	standardLines := len(lines)
+	for i, line := range lines {
+		if strings.HasPrefix(line, codePointPrefix) {
+			standardLines = i
+			break
+		}
 	}
-	if len(lines) != expectedLines {
-		return nil, fmt.Errorf("invalid format: expected %d lines, got %d",
-			expectedLines, len(lines))
+	if standardLines%totalChars != 0 || standardLines < 2*totalChars {
+		return Banner{}, fmt.Errorf("invalid format: expected %d × (height + 1) lines, got %d",
+			totalChars, standardLines)
 	}
 
-	banner := make(Banner)
+	linesPerChar := standardLines / totalChars
+	height := linesPerChar - 1
+
+	glyphs := make(map[rune][]string, totalChars)
 	runeCode := firstPrintable
 	i := 1
 
-	for i+linesPerGlyph <= len(lines) && runeCode <= lastPrintable {
-		block := lines[i : i+linesPerGlyph]
-		banner[runeCode] = block
+	for i+height <= standardLines && runeCode <= lastPrintable {
+		glyphs[runeCode] = lines[i : i+height]
 		runeCode++
 		i += linesPerChar
 	}
 
-	if len(banner) != totalChars {
-		return nil, fmt.Errorf("incomplete banner: got %d chars, expected %d",
-			len(banner), totalChars)
+	if len(glyphs) != totalChars {
+		return Banner{}, fmt.Errorf("incomplete banner: got %d chars, expected %d",
+			len(glyphs), totalChars)
 	}
-	return banner, nil
+
+	if err := addTaggedGlyphs(glyphs, lines[standardLines:], height); err != nil {
+		return Banner{}, err
+	}
+	return Banner{Glyphs: glyphs, Height: height, Baseline: findBaseline(glyphs, height)}, nil
+}
+
+// addTaggedGlyphs adds the extra characters that follow the standard section of
+// a banner file to glyphs.
+//
+// Parameters:
+//   - glyphs: The character map to extend.
+//   - lines: The lines after the standard section, each block a code-point tag
+//     followed by height glyph rows.
+//   - height: The number of rows of every glyph.
+//
+// Returns:
+//   - An error if a tag is malformed, a block is truncated, or a character is
+//     defined twice.
+func addTaggedGlyphs(glyphs map[rune][]string, lines []string, height int) error {
+	for i := 0; i < len(lines); i += height + 1 {
+		code, err := parseCodePoint(lines[i])
+		if err != nil {
+			return fmt.Errorf("line %d of extra characters: %w", i+1, err)
+		}
+		if i+height >= len(lines) {
+			return fmt.Errorf("incomplete glyph for %s", lines[i])
+		}
+		if _, exists := glyphs[code]; exists {
+			return fmt.Errorf("duplicate glyph for U+%04X", code)
+		}
+		glyphs[code] = lines[i+1 : i+1+height]
+	}
+	return nil
+}
+
+// parseCodePoint parses a code-point tag line such as "U+00E9" or
+// "U+20AC EURO SIGN".
+//
+// Parameters:
+//   - line: The separator line of an extra character.
+//
+// Returns:
+//   - The tagged character.
+//   - An error if the line is not a valid code-point tag.
+func parseCodePoint(line string) (rune, error) {
+	tag, _, _ := strings.Cut(line, " ")
+	digits, ok := strings.CutPrefix(tag, codePointPrefix)
+	if !ok || len(digits) < 4 || len(digits) > 6 {
+		return 0, fmt.Errorf("invalid code point tag %q", line)
+	}
+	value, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil || !utf8.ValidRune(rune(value)) {
+		return 0, fmt.Errorf("invalid code point tag %q", line)
+	}
+	return rune(value), nil
+}
+
+// findBaseline estimates the baseline of a banner without one declared: the
+// row below the lowest row any capital letter draws in. Banners without
+// visible capital letters get a baseline at the bottom row.
+//
+// Parameters:
+//   - glyphs: The glyph rows of each character.
+//   - height: The number of rows of every glyph.
+//
+// Returns:
+//   - The baseline, counted in rows from the top of a glyph.
+func findBaseline(glyphs map[rune][]string, height int) int {
+	baseline := 0
+	for char := 'A'; char <= 'Z'; char++ {
+		for row, line := range glyphs[char] {
+			if strings.TrimSpace(line) != "" {
+				baseline = max(baseline, row+1)
+			}
+		}
+	}
+	if baseline == 0 {
+		return height
+	}
+	return baseline
 }
 
 // CharWidths returns the column width of each character in text based on the
-// provided Banner glyph data. Each width corresponds to len(glyph[0]) for the
+// provided Banner glyph data.
+//
+// The result has one entry per rune of text, so multi-byte characters take a
+// single slot. Each width is the number of runes in the first row of the
 // character's ASCII art representation. Unknown characters get width 0.
 //
 // Parameters:
 //   - text: The input string whose character widths are needed.
-//   - banner: The loaded Banner map containing glyph data.
+//   - banner: The loaded Banner containing glyph data.
 //
 // Returns:
-//   - A slice of integers with one width per character in text.
+//   - A slice of integers with one width per rune in text.
 func CharWidths(text string, banner Banner) []int {
-	widths := make([]int, len(text))
-	for i, char := range text {
-		glyph := banner[char]
-		if glyph == nil {
-			continue
+	widths := make([]int, 0, utf8.RuneCountInString(text))
+	for _, char := range text {
+		width := 0
+		if glyph := banner.Glyphs[char]; len(glyph) > 0 {
+			width = utf8.RuneCountInString(glyph[0])
 		}
-		widths[i] = len(glyph[0])
+		widths = append(widths, width)
 	}
 	return widths
 }
@@ -0,0 +1,327 @@
+package parser
+
+import (
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	figletSignature = "flf2a"
+
+	// Number of numeric parameters on the header line that every font must
+	// provide (height, baseline, max length, old layout, comment lines).
+	figletRequiredParams = 5
+)
+
+// figletDeutsch lists the seven Deutsch characters that follow the printable
+// ASCII range in a FIGlet font, in the order the specification defines them.
+var figletDeutsch = []rune{196, 214, 220, 228, 246, 252, 223}
+
+// FIGletHeader holds the parameters declared on the first line of a FIGlet
+// (.flf) font file.
+//
+// Optional parameters that a font omits are left at zero, except FullLayout,
+// which is derived from OldLayout as the FIGlet specification requires.
+type FIGletHeader struct {
+	Hardblank      rune
+	Height         int
+	Baseline       int
+	MaxLength      int
+	OldLayout      int
+	CommentLines   int
+	PrintDirection int
+	FullLayout     int
+	CodetagCount   int
+}
+
+// FIGletFont is a parsed FIGlet font.
+//
+// Glyph rows are stored exactly as drawn in the font file with the endmarks
+// removed, so hardblank characters are still present. Use Banner to obtain a
+// representation the renderer can use directly.
+type FIGletFont struct {
+	Header  FIGletHeader
+	Comment string
+	Glyphs  map[rune][]string
+}
+
+// LoadFIGlet reads a FIGlet font file from the provided filesystem and returns
+// it as a Banner.
+//
+// It is a convenience wrapper around LoadFIGletFont followed by
+// FIGletFont.Banner.
+//
+// Parameters:
+//   - fsys: The filesystem to read from (can be embed.FS, os.DirFS, or any fs.FS).
+//   - path: The file path within the filesystem (e.g., "fonts/slant.flf").
+//
+// Returns:
+//   - A Banner containing every character the font defines.
+//   - An error if the file cannot be read or the font is malformed.
+func LoadFIGlet(fsys fs.FS, path string) (Banner, error) {
+	font, err := LoadFIGletFont(fsys, path)
+	if err != nil {
+		return Banner{}, err
+	}
+	return font.Banner(), nil
+}
+
+// LoadFIGletFont reads and parses a FIGlet font file from the provided filesystem.
+//
+// The function parses the flf2a header, skips the comment block, and reads the
+// required printable ASCII characters (32-126), the optional Deutsch characters
+// and any code-tagged characters that follow them.
+//
+// Parameters:
+//   - fsys: The filesystem to read from (can be embed.FS, os.DirFS, or any fs.FS).
+//   - path: The file path within the filesystem.
+//
+// Returns:
+//   - The parsed font.
+//   - An error if the file cannot be read or the font is malformed.
+func LoadFIGletFont(fsys fs.FS, path string) (*FIGletFont, error) {
+	lines, err := readLines(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read FIGlet font %q: %w", path, err)
+	}
+	font, err := parseFIGlet(lines)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse FIGlet font %q: %w", path, err)
+	}
+	return font, nil
+}
+
+// Banner converts the font into a Banner.
+//
+// Hardblanks are replaced by spaces and every row of a glyph is padded to the
+// glyph's widest row. The Banner takes its height and baseline from the header.
+//
+// Returns:
+//   - A Banner containing every character the font defines.
+func (f *FIGletFont) Banner() Banner {
+	return f.toBanner(" ")
+}
+
+// RawBanner converts the font into a Banner like Banner does, but leaves
+// hardblanks in place.
+//
+// Use it when the glyphs will be fitted or smushed together, which must not
+// remove the blank columns a hardblank protects; the renderer then needs
+// Header.Hardblank to print them as spaces.
+//
+// Returns:
+//   - A Banner containing every character the font defines.
+func (f *FIGletFont) RawBanner() Banner {
+	return f.toBanner(string(f.Header.Hardblank))
+}
+
+// toBanner converts the font into a Banner, replacing hardblanks with the
+// given string.
+func (f *FIGletFont) toBanner(hardblank string) Banner {
+	glyphs := make(map[rune][]string, len(f.Glyphs))
+
+	for char, rows := range f.Glyphs {
+		glyph := make([]string, len(rows))
+		width := 0
+		for i, row := range rows {
+			glyph[i] = strings.ReplaceAll(row, string(f.Header.Hardblank), hardblank)
+			width = max(width, utf8.RuneCountInString(glyph[i]))
+		}
+		for i := range glyph {
+			glyph[i] += strings.Repeat(" ", width-utf8.RuneCountInString(glyph[i]))
+		}
+		glyphs[char] = glyph
+	}
+	return Banner{Glyphs: glyphs, Height: f.Header.Height, Baseline: f.Header.Baseline}
+}
+
+// parseFIGlet builds a FIGletFont from the raw lines of a font file.
+//
+// Parameters:
+//   - lines: The raw lines from a FIGlet font file.
+//
+// Returns:
+//   - The parsed font.
+//   - An error if the header or any character definition is malformed.
+func parseFIGlet(lines []string) (*FIGletFont, error) {
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("empty font file")
+	}
+
+	header, err := parseFIGletHeader(lines[0])
+	if err != nil {
+		return nil, err
+	}
+
+	i := 1 + header.CommentLines
+	if i > len(lines) {
+		return nil, fmt.Errorf("truncated comment block: expected %d lines", header.CommentLines)
+	}
+
+	font := &FIGletFont{
+		Header:  header,
+		Comment: strings.Join(lines[1:i], "\n"),
+		Glyphs:  make(map[rune][]string),
+	}
+
+	for char := firstPrintable; char <= lastPrintable; char++ {
+		if i+header.Height > len(lines) {
+			return nil, fmt.Errorf("incomplete font: missing character %q (ASCII %d)", char, char)
+		}
+		font.Glyphs[char] = readFIGletGlyph(lines[i : i+header.Height])
+		i += header.Height
+	}
+
+	for _, char := range figletDeutsch {
+		if i+header.Height > len(lines) {
+			return font, nil
+		}
+		font.Glyphs[char] = readFIGletGlyph(lines[i : i+header.Height])
+		i += header.Height
+	}
+
+	for i < len(lines) {
+		if strings.TrimSpace(lines[i]) == "" {
+			i++
+			continue
+		}
+		code, err := parseCodeTag(lines[i])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if i+1+header.Height > len(lines) {
+			return nil, fmt.Errorf("line %d: incomplete definition for code %d", i+1, code)
+		}
+		// Negative codes identify translation-table entries rather than
+		// characters that can appear in the input, and codes beyond Unicode
+		// or in the surrogate range name no character, so, as figlet does,
+		// their glyphs are skipped.
+		if utf8.ValidRune(rune(code)) {
+			font.Glyphs[rune(code)] = readFIGletGlyph(lines[i+1 : i+1+header.Height])
+		}
+		i += 1 + header.Height
+	}
+
+	return font, nil
+}
+
+// parseFIGletHeader parses the flf2a header line of a FIGlet font.
+//
+// Parameters:
+//   - line: The first line of the font file.
+//
+// Returns:
+//   - The parsed header.
+//   - An error if the signature is missing or a parameter is invalid.
+func parseFIGletHeader(line string) (FIGletHeader, error) {
+	line = strings.TrimRight(line, "\r")
+	if !strings.HasPrefix(line, figletSignature) || len(line) <= len(figletSignature) {
+		return FIGletHeader{}, fmt.Errorf("missing %s signature", figletSignature)
+	}
+
+	fields := strings.Fields(line[len(figletSignature):])
+	if len(fields) < 1+figletRequiredParams {
+		return FIGletHeader{}, fmt.Errorf("header has %d parameters, expected at least %d",
+			len(fields)-1, figletRequiredParams)
+	}
+
+	params := make([]int, 0, len(fields)-1)
+	for _, field := range fields[1:] {
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			return FIGletHeader{}, fmt.Errorf("invalid header parameter %q: %w", field, err)
+		}
+		params = append(params, value)
+	}
+
+	header := FIGletHeader{
+		Hardblank:    []rune(fields[0])[0],
+		Height:       params[0],
+		Baseline:     params[1],
+		MaxLength:    params[2],
+		OldLayout:    params[3],
+		CommentLines: params[4],
+		FullLayout:   fullLayoutFromOld(params[3]),
+	}
+	if len(params) > 5 {
+		header.PrintDirection = params[5]
+	}
+	if len(params) > 6 {
+		header.FullLayout = params[6]
+	}
+	if len(params) > 7 {
+		header.CodetagCount = params[7]
+	}
+
+	if header.Height < 1 {
+		return FIGletHeader{}, fmt.Errorf("invalid height %d", header.Height)
+	}
+	if header.Baseline < 1 || header.Baseline > header.Height {
+		return FIGletHeader{}, fmt.Errorf("invalid baseline %d for height %d", header.Baseline, header.Height)
+	}
+	if header.CommentLines < 0 {
+		return FIGletHeader{}, fmt.Errorf("invalid comment line count %d", header.CommentLines)
+	}
+	return header, nil
+}
+
+// fullLayoutFromOld derives the full_layout value from an old_layout value for
+// fonts whose header does not declare one.
+//
+// An old_layout of -1 means full width, 0 means kerning, and a positive value
+// lists the smushing rules to apply.
+func fullLayoutFromOld(oldLayout int) int {
+	const (
+		horizontalKerning  = 64
+		horizontalSmushing = 128
+	)
+	switch {
+	case oldLayout < 0:
+		return 0
+	case oldLayout == 0:
+		return horizontalKerning
+	default:
+		return (oldLayout & 63) | horizontalSmushing
+	}
+}
+
+// parseCodeTag parses the character code at the start of a code tag line.
+//
+// Codes may be written in decimal, in hexadecimal with a 0x prefix, or in
+// octal with a leading 0, and may be negative.
+func parseCodeTag(line string) (int64, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("missing code tag")
+	}
+	code, err := strconv.ParseInt(fields[0], 0, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid code tag %q: %w", fields[0], err)
+	}
+	if code == -1 {
+		return 0, fmt.Errorf("code tag -1 is reserved")
+	}
+	return code, nil
+}
+
+// readFIGletGlyph strips the endmarks from the rows of one FIGcharacter.
+//
+// The endmark is the last character of a row, ignoring trailing whitespace
+// as figlet does; the final row of a character usually repeats it, so every
+// trailing occurrence is removed.
+func readFIGletGlyph(rows []string) []string {
+	glyph := make([]string, len(rows))
+	for i, row := range rows {
+		row = strings.TrimRightFunc(row, unicode.IsSpace)
+		if row == "" {
+			continue
+		}
+		endmark, _ := utf8.DecodeLastRuneInString(row)
+		glyph[i] = strings.TrimRight(row, string(endmark))
+	}
+	return glyph
+}
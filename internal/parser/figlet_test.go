@@ -0,0 +1,267 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// buildFIGletFont returns the contents of a minimal FIGlet font whose glyphs
+// are a hardblank followed by the character itself.
+func buildFIGletFont(header string, height int, extra string) string {
+	var b strings.Builder
+	b.WriteString(header + "\n")
+	b.WriteString("test font\n")
+	b.WriteString("second comment line\n")
+	for char := firstPrintable; char <= lastPrintable; char++ {
+		for row := 0; row < height; row++ {
+			end := "@"
+			if row == height-1 {
+				end = "@@"
+			}
+			if char == ' ' {
+				fmt.Fprintf(&b, "$$%s\n", end)
+				continue
+			}
+			fmt.Fprintf(&b, "$%c%s\n", char, end)
+		}
+	}
+	b.WriteString(extra)
+	return b.String()
+}
+
+func TestLoadFIGletFont_Header(t *testing.T) {
+	fsys := fstest.MapFS{
+		"mini.flf": {Data: []byte(buildFIGletFont("flf2a$ 3 2 4 -1 2 0 0 5", 3, ""))},
+	}
+
+	font, err := LoadFIGletFont(fsys, "mini.flf")
+	if err != nil {
+		t.Fatalf("LoadFIGletFont failed: %v", err)
+	}
+
+	want := FIGletHeader{
+		Hardblank:      '$',
+		Height:         3,
+		Baseline:       2,
+		MaxLength:      4,
+		OldLayout:      -1,
+		CommentLines:   2,
+		PrintDirection: 0,
+		FullLayout:     0,
+		CodetagCount:   5,
+	}
+	if font.Header != want {
+		t.Errorf("header = %+v, want %+v", font.Header, want)
+	}
+	if font.Comment != "test font\nsecond comment line" {
+		t.Errorf("comment = %q", font.Comment)
+	}
+	if len(font.Glyphs) != totalChars {
+		t.Errorf("expected %d glyphs, got %d", totalChars, len(font.Glyphs))
+	}
+	if got := font.Glyphs['A']; strings.Join(got, "|") != "$A|$A|$A" {
+		t.Errorf("glyph A = %q, want endmarks stripped and hardblanks kept", got)
+	}
+}
+
+func TestLoadFIGletFont_FullLayoutFromOldLayout(t *testing.T) {
+	tests := []struct {
+		name      string
+		oldLayout int
+		want      int
+	}{
+		{"full width", -1, 0},
+		{"kerning", 0, 64},
+		{"smushing rules", 15, 15 | 128},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := fmt.Sprintf("flf2a$ 3 2 4 %d 2", tt.oldLayout)
+			fsys := fstest.MapFS{"f.flf": {Data: []byte(buildFIGletFont(header, 3, ""))}}
+
+			font, err := LoadFIGletFont(fsys, "f.flf")
+			if err != nil {
+				t.Fatalf("LoadFIGletFont failed: %v", err)
+			}
+			if font.Header.FullLayout != tt.want {
+				t.Errorf("FullLayout = %d, want %d", font.Header.FullLayout, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFIGletFont_DeutschAndCodeTagged(t *testing.T) {
+	var extra strings.Builder
+	for _, char := range figletDeutsch {
+		fmt.Fprintf(&extra, "%c@\n%c@\n%c@@\n", char, char, char)
+	}
+	extra.WriteString("0x20AC  EURO SIGN\nE@\nE@\nE@@\n")
+	extra.WriteString("0351\ne@\ne@\ne@@\n")
+	extra.WriteString("-255  translation entry\nx@\nx@\nx@@\n")
+	extra.WriteString("0x110000  beyond Unicode\nx@\nx@\nx@@\n")
+	extra.WriteString("0xD800  surrogate\nx@\nx@\nx@@\n")
+
+	fsys := fstest.MapFS{
+		"f.flf": {Data: []byte(buildFIGletFont("flf2a$ 3 2 4 0 2 0 64 2", 3, extra.String()))},
+	}
+
+	font, err := LoadFIGletFont(fsys, "f.flf")
+	if err != nil {
+		t.Fatalf("LoadFIGletFont failed: %v", err)
+	}
+
+	for _, char := range figletDeutsch {
+		if _, ok := font.Glyphs[char]; !ok {
+			t.Errorf("missing Deutsch character %q", char)
+		}
+	}
+	if got := font.Glyphs[0x20AC]; len(got) != 3 || got[0] != "E" {
+		t.Errorf("hex code tag glyph = %q", got)
+	}
+	if got := font.Glyphs[0351]; len(got) != 3 || got[0] != "e" {
+		t.Errorf("octal code tag glyph = %q", got)
+	}
+	if len(font.Glyphs) != totalChars+len(figletDeutsch)+2 {
+		t.Errorf("expected negative and invalid code tags to be skipped, got %d glyphs", len(font.Glyphs))
+	}
+}
+
+func TestLoadFIGletFont_TrailingWhitespaceAfterEndmark(t *testing.T) {
+	extra := strings.Repeat("d@\nd@\nd@@\n", len(figletDeutsch)) + "0x20AC\nE@  \nE@\t\r\nE@@ \t\n"
+	fsys := fstest.MapFS{
+		"f.flf": {Data: []byte(buildFIGletFont("flf2a$ 3 2 4 0 2", 3, extra))},
+	}
+
+	font, err := LoadFIGletFont(fsys, "f.flf")
+	if err != nil {
+		t.Fatalf("LoadFIGletFont failed: %v", err)
+	}
+	if got := font.Glyphs[0x20AC]; strings.Join(got, "|") != "E|E|E" {
+		t.Errorf("glyph = %q, want the endmarks and trailing whitespace stripped", got)
+	}
+}
+
+func TestLoadFIGletFont_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty file", ""},
+		{"missing signature", "flf2 $ 3 2 4 0 0\n"},
+		{"too few parameters", "flf2a$ 3 2 4\n"},
+		{"non-numeric parameter", "flf2a$ x 2 4 0 0\n"},
+		{"invalid height", "flf2a$ 0 1 4 0 0\n"},
+		{"baseline above height", "flf2a$ 3 4 4 0 0\n"},
+		{"truncated comments", "flf2a$ 3 2 4 0 10\ncomment\n"},
+		{"truncated characters", "flf2a$ 3 2 4 0 0\n $@\n $@\n $@@\n"},
+		{"reserved code tag", buildFIGletFont("flf2a$ 3 2 4 0 2", 3, strings.Repeat("d@\nd@\nd@@\n", 7)+"-1\nx@\nx@\nx@@\n")},
+		{"bad code tag", buildFIGletFont("flf2a$ 3 2 4 0 2", 3, strings.Repeat("d@\nd@\nd@@\n", 7)+"zz\nx@\nx@\nx@@\n")},
+		{"truncated code tag", buildFIGletFont("flf2a$ 3 2 4 0 2", 3, strings.Repeat("d@\nd@\nd@@\n", 7)+"200\nx@\n")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{"f.flf": {Data: []byte(tt.data)}}
+			if _, err := LoadFIGletFont(fsys, "f.flf"); err == nil {
+				t.Errorf("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestLoadFIGlet_Banner(t *testing.T) {
+	fsys := fstest.MapFS{
+		"mini.flf": {Data: []byte(buildFIGletFont("flf2a$ 3 2 4 -1 2", 3, ""))},
+	}
+
+	banner, err := LoadFIGlet(fsys, "mini.flf")
+	if err != nil {
+		t.Fatalf("LoadFIGlet failed: %v", err)
+	}
+
+	if banner.Height != 3 || banner.Baseline != 2 {
+		t.Errorf("height/baseline = %d/%d, want 3/2", banner.Height, banner.Baseline)
+	}
+	glyph := banner.Glyphs['A']
+	expected := []string{" A", " A", " A"}
+	if len(glyph) != len(expected) {
+		t.Fatalf("expected %d rows, got %d", len(expected), len(glyph))
+	}
+	for i, row := range glyph {
+		if row != expected[i] {
+			t.Errorf("row %d: expected %q, got %q", i, expected[i], row)
+		}
+	}
+	if banner.Glyphs[' '][0] != "  " {
+		t.Errorf("expected hardblanks replaced by spaces, got %q", banner.Glyphs[' '][0])
+	}
+}
+
+func TestLoadFIGlet_TallFont(t *testing.T) {
+	fsys := fstest.MapFS{
+		"tall.flf": {Data: []byte(buildFIGletFont("flf2a$ 12 10 4 -1 2", 12, ""))},
+	}
+
+	banner, err := LoadFIGlet(fsys, "tall.flf")
+	if err != nil {
+		t.Fatalf("LoadFIGlet failed: %v", err)
+	}
+	if banner.Height != 12 || len(banner.Glyphs['Z']) != 12 {
+		t.Errorf("expected 12-row glyphs, got height %d and %d rows",
+			banner.Height, len(banner.Glyphs['Z']))
+	}
+}
+
+func TestLoadFIGlet_MultiByteRowsPaddedByRune(t *testing.T) {
+	extra := "0x00FC\nü@\nüü@\nü@@\n"
+	fsys := fstest.MapFS{
+		"f.flf": {Data: []byte(buildFIGletFont("flf2a$ 3 2 4 -1 2", 3, strings.Repeat("d@\nd@\nd@@\n", 7)+extra))},
+	}
+
+	banner, err := LoadFIGlet(fsys, "f.flf")
+	if err != nil {
+		t.Fatalf("LoadFIGlet failed: %v", err)
+	}
+	if got := strings.Join(banner.Glyphs['ü'], "|"); got != "ü |üü|ü " {
+		t.Errorf("glyph ü = %q, want rows padded to two characters", got)
+	}
+}
+
+func TestLoadFIGlet_MissingFile(t *testing.T) {
+	if _, err := LoadFIGlet(os.DirFS("../../cmd/ascii-art/testdata"), "nope.flf"); err == nil {
+		t.Error("expected error for missing file, got nil")
+	}
+}
+
+func TestLoadFIGlet_Fixture(t *testing.T) {
+	banner, err := LoadFIGlet(os.DirFS("../../cmd/ascii-art/testdata"), "boxed.flf")
+	if err != nil {
+		t.Fatalf("LoadFIGlet failed: %v", err)
+	}
+	if len(banner.Glyphs) < totalChars {
+		t.Errorf("expected at least %d chars, got %d", totalChars, len(banner.Glyphs))
+	}
+}
+
+func TestFIGletFont_RawBannerKeepsHardblanks(t *testing.T) {
+	fsys := fstest.MapFS{
+		"mini.flf": {Data: []byte(buildFIGletFont("flf2a$ 3 2 4 -1 2", 3, ""))},
+	}
+
+	font, err := LoadFIGletFont(fsys, "mini.flf")
+	if err != nil {
+		t.Fatalf("LoadFIGletFont failed: %v", err)
+	}
+	banner := font.RawBanner()
+
+	if banner.Glyphs['A'][0] != "$A" {
+		t.Errorf("expected hardblank kept, got %q", banner.Glyphs['A'][0])
+	}
+	if banner.Height != 3 {
+		t.Errorf("expected height 3, got %d", banner.Height)
+	}
+}
@@ -2,11 +2,16 @@ package parser
 
 import (
 	"os"
+	"strings"
 	"testing"
+	"testing/fstest"
 )
 
+// standardHeight is the glyph height of the standard, shadow and thinkertoy banners.
+const standardHeight = 8
+
 func TestLoadBannerSpaceChar(t *testing.T) {
-	banner, err := LoadBanner(os.DirFS("../../cmd/ascii-art/testdata"), "shadow.txt")
+	banner, err := LoadBanner(os.DirFS("../banners"), "shadow.txt")
 	if err != nil {
 		t.Fatalf("LoadBanner failed: %v", err)
 	}
@@ -23,7 +28,7 @@ func TestLoadBannerSpaceChar(t *testing.T) {
 		"      ",
 	}
 
-	actual, ok := banner[spaceChar]
+	actual, ok := banner.Glyphs[spaceChar]
 	if !ok {
 		t.Errorf("banner does not contain space character")
 	}
@@ -47,7 +52,7 @@ func TestLoadBannerMissingFile(t *testing.T) {
 }
 
 func TestLoadBannerExclamationChar(t *testing.T) {
-	banner, err := LoadBanner(os.DirFS("../../cmd/ascii-art/testdata"), "shadow.txt")
+	banner, err := LoadBanner(os.DirFS("../banners"), "shadow.txt")
 	if err != nil {
 		t.Fatalf("LoadBanner Failed: %v", err)
 	}
@@ -62,7 +67,7 @@ func TestLoadBannerExclamationChar(t *testing.T) {
 		"   ",
 		"   ",
 	}
-	actual, ok := banner[char]
+	actual, ok := banner.Glyphs[char]
 	if !ok {
 		t.Errorf("banner does not contain '!' character")
 	}
@@ -77,13 +82,13 @@ func TestLoadBannerExclamationChar(t *testing.T) {
 }
 
 func TestLoadBannerStandardSpace(t *testing.T) {
-	banner, err := LoadBanner(os.DirFS("../../cmd/ascii-art/testdata"), "standard.txt")
+	banner, err := LoadBanner(os.DirFS("../banners"), "standard.txt")
 	if err != nil {
 		t.Fatalf("loadBanner failed: %v", err)
 	}
 	space := ' '
 
-	actual, ok := banner[space]
+	actual, ok := banner.Glyphs[space]
 	if !ok {
 		t.Fatalf("banner does not contain space character")
 	}
@@ -108,13 +113,13 @@ func TestLoadBannerStandardSpace(t *testing.T) {
 }
 
 func TestLoadBannerShadowA(t *testing.T) {
-	banner, err := LoadBanner(os.DirFS("../../cmd/ascii-art/testdata"), "shadow.txt")
+	banner, err := LoadBanner(os.DirFS("../banners"), "shadow.txt")
 	if err != nil {
 		t.Fatalf("LoadBanner failed: %v", err)
 	}
 
 	ch := 'A'
-	actual, ok := banner[ch]
+	actual, ok := banner.Glyphs[ch]
 	if !ok {
 		t.Fatalf("banner does not contain 'A' character")
 	}
@@ -162,64 +167,66 @@ func TestLoadBannerOversizedFile(t *testing.T) {
 }
 
 func TestLoadBannerThinkertoy(t *testing.T) {
-	banner, err := LoadBanner(os.DirFS("../../cmd/ascii-art/testdata"), "thinkertoy.txt")
+	banner, err := LoadBanner(os.DirFS("../banners"), "thinkertoy.txt")
 	if err != nil {
 		t.Fatalf("thinkertoy failed: %v", err)
 	}
-	if len(banner) != totalChars {
-		t.Errorf("expected %d chars, got %d", totalChars, len(banner))
+	if len(banner.Glyphs) != totalChars {
+		t.Errorf("expected %d chars, got %d", totalChars, len(banner.Glyphs))
 	}
 }
 
 func TestLoadBannerNumbers(t *testing.T) {
-	banner, err := LoadBanner(os.DirFS("../../cmd/ascii-art/testdata"), "standard.txt")
+	banner, err := LoadBanner(os.DirFS("../banners"), "standard.txt")
 	if err != nil {
 		t.Fatalf("LoadBanner failed: %v", err)
 	}
 
 	for r := '0'; r <= '9'; r++ {
-		lines, ok := banner[r]
+		lines, ok := banner.Glyphs[r]
 		if !ok {
 			t.Errorf("missing digit %c", r)
 			continue
 		}
-		if len(lines) != linesPerGlyph {
+		if len(lines) != standardHeight {
 			t.Errorf("digit %c has %d lines, expected %d",
-				r, len(lines), linesPerGlyph)
+				r, len(lines), standardHeight)
 		}
 	}
 }
 
 func TestLoadBannerCompleteCharacterSet(t *testing.T) {
-	banner, err := LoadBanner(os.DirFS("../../cmd/ascii-art/testdata"), "standard.txt")
+	banner, err := LoadBanner(os.DirFS("../banners"), "standard.txt")
 	if err != nil {
 		t.Fatalf("LoadBanner failed: %v", err)
 	}
 
-	if len(banner) != totalChars {
-		t.Fatalf("expected %d chars, got %d", totalChars, len(banner))
+	if len(banner.Glyphs) != totalChars {
+		t.Fatalf("expected %d chars, got %d", totalChars, len(banner.Glyphs))
 	}
 
 	for r := firstPrintable; r <= lastPrintable; r++ {
-		lines, ok := banner[r]
+		lines, ok := banner.Glyphs[r]
 		if !ok {
 			t.Errorf("missing char %c (ASCII %d)", r, r)
 			continue
 		}
-		if len(lines) != linesPerGlyph {
+		if len(lines) != standardHeight {
 			t.Errorf("char %c (ASCII %d) has %d lines, expected %d",
-				r, r, len(lines), linesPerGlyph)
+				r, r, len(lines), standardHeight)
 		}
 	}
 }
 
 func TestCharWidths(t *testing.T) {
-	banner := Banner{
+	banner := Banner{Height: 8, Glyphs: map[rune][]string{
 		'H': {"_    _ ", "_|  |_ ", "_|  |_ ", "|_  _| ", " |  |  ", " |  |  ", "       ", "       "},
 		'i': {"   ", "   ", " _ ", "| |", "| |", "|_|", "   ", "   "},
 		' ': {"      ", "      ", "      ", "      ", "      ", "      ", "      ", "      "},
 		'!': {"_ ", "| ", "| ", "| ", "  ", "| ", "  ", "  "},
-	}
+		'é': {" / ", "/_\\", "\\__", "   ", "   ", "   ", "   ", "   "},
+		'€': {" ╔═", "═╬═", " ╚═", "   ", "   ", "   ", "   ", "   "},
+	}}
 
 	tests := []struct {
 		name string
@@ -281,6 +288,16 @@ func TestCharWidths(t *testing.T) {
 			text: "!",
 			want: []int{2},
 		},
+		{
+			name: "multi-byte text char takes one slot",
+			text: "Hé!",
+			want: []int{7, 3, 2},
+		},
+		{
+			name: "multi-byte glyph rows counted in runes",
+			text: "€i",
+			want: []int{3, 3},
+		},
 	}
 
 	for _, tt := range tests {
@@ -322,10 +339,10 @@ func TestCharWidths_RealBanners(t *testing.T) {
 		{"thinkertoy", "thinkertoy.txt"},
 	}
 
-	testdataFS := os.DirFS("../../cmd/ascii-art/testdata")
+	bannerFS := os.DirFS("../banners")
 	for _, bf := range bannerFiles {
 		t.Run(bf.name, func(t *testing.T) {
-			banner, err := LoadBanner(testdataFS, bf.path)
+			banner, err := LoadBanner(bannerFS, bf.path)
 			if err != nil {
 				t.Fatalf("LoadBanner(%s) failed: %v", bf.name, err)
 			}
@@ -338,7 +355,7 @@ func TestCharWidths_RealBanners(t *testing.T) {
 			}
 
 			for i, ch := range text {
-				glyph := banner[ch]
+				glyph := banner.Glyphs[ch]
 				if glyph == nil {
 					t.Errorf("char %c missing from %s banner", ch, bf.name)
 					continue
@@ -362,16 +379,16 @@ func TestCharWidths_ConsistentGlyphLines(t *testing.T) {
 		{"thinkertoy", "thinkertoy.txt"},
 	}
 
-	testdataFS := os.DirFS("../../cmd/ascii-art/testdata")
+	bannerFS := os.DirFS("../banners")
 	for _, bf := range bannerFiles {
 		t.Run(bf.name, func(t *testing.T) {
-			banner, err := LoadBanner(testdataFS, bf.path)
+			banner, err := LoadBanner(bannerFS, bf.path)
 			if err != nil {
 				t.Fatalf("LoadBanner(%s) failed: %v", bf.name, err)
 			}
 
 			for r := firstPrintable; r <= lastPrintable; r++ {
-				glyph := banner[r]
+				glyph := banner.Glyphs[r]
 				if glyph == nil {
 					t.Errorf("char %c missing", r)
 					continue
@@ -389,7 +406,7 @@ func TestCharWidths_ConsistentGlyphLines(t *testing.T) {
 }
 
 func TestLoadBannerAllSpecialCharacters(t *testing.T) {
-	banner, err := LoadBanner(os.DirFS("../../cmd/ascii-art/testdata"), "standard.txt")
+	banner, err := LoadBanner(os.DirFS("../banners"), "standard.txt")
 	if err != nil {
 		t.Fatalf("LoadBanner failed: %v", err)
 	}
@@ -397,14 +414,154 @@ func TestLoadBannerAllSpecialCharacters(t *testing.T) {
 	specials := `!"#$%&'()*+,-./:;<=>?@[\]^_{|}~` + "`"
 
 	for _, ch := range specials {
-		lines, ok := banner[ch]
+		lines, ok := banner.Glyphs[ch]
 		if !ok {
 			t.Errorf("missing special character %q (ASCII %d)", ch, ch)
 			continue
 		}
-		if len(lines) != linesPerGlyph {
+		if len(lines) != standardHeight {
 			t.Errorf("special char %q has %d lines, expected %d",
-				ch, len(lines), linesPerGlyph)
+				ch, len(lines), standardHeight)
+		}
+	}
+}
+
+func TestLoadBannerHeightAndBaseline(t *testing.T) {
+	bannerFS := os.DirFS("../banners")
+	for _, name := range []string{"standard.txt", "shadow.txt", "thinkertoy.txt"} {
+		banner, err := LoadBanner(bannerFS, name)
+		if err != nil {
+			t.Fatalf("LoadBanner(%s) failed: %v", name, err)
+		}
+		if banner.Height != standardHeight {
+			t.Errorf("%s: height = %d, want %d", name, banner.Height, standardHeight)
 		}
+		if banner.Baseline != 6 {
+			t.Errorf("%s: baseline = %d, want 6", name, banner.Baseline)
+		}
+	}
+}
+
+func TestLoadBannerVariableHeight(t *testing.T) {
+	tests := []struct {
+		name         string
+		height       int
+		wantBaseline int
+	}{
+		{"small three-row banner", 3, 2},
+		{"tall twelve-row banner", 12, 11},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data strings.Builder
+			for char := firstPrintable; char <= lastPrintable; char++ {
+				data.WriteString("\n")
+				for row := 0; row < tt.height; row++ {
+					if row < tt.wantBaseline && char != ' ' {
+						data.WriteString(string(char) + "\n")
+					} else {
+						data.WriteString(" \n")
+					}
+				}
+			}
+			fsys := fstest.MapFS{"banner.txt": {Data: []byte(data.String())}}
+
+			banner, err := LoadBanner(fsys, "banner.txt")
+			if err != nil {
+				t.Fatalf("LoadBanner failed: %v", err)
+			}
+			if banner.Height != tt.height {
+				t.Errorf("height = %d, want %d", banner.Height, tt.height)
+			}
+			if banner.Baseline != tt.wantBaseline {
+				t.Errorf("baseline = %d, want %d", banner.Baseline, tt.wantBaseline)
+			}
+			if got := banner.Glyphs['Q']; len(got) != tt.height || got[0] != "Q" {
+				t.Errorf("glyph Q = %q", got)
+			}
+		})
+	}
+}
+
+func TestLoadBannerBlankBaseline(t *testing.T) {
+	fsys := fstest.MapFS{"blank.txt": {Data: []byte(strings.Repeat("\n", totalChars*4))}}
+
+	banner, err := LoadBanner(fsys, "blank.txt")
+	if err != nil {
+		t.Fatalf("LoadBanner failed: %v", err)
+	}
+	if banner.Height != 3 || banner.Baseline != 3 {
+		t.Errorf("height/baseline = %d/%d, want 3/3", banner.Height, banner.Baseline)
+	}
+}
+
+func TestLoadBannerTooFewLines(t *testing.T) {
+	fsys := fstest.MapFS{"short.txt": {Data: []byte(strings.Repeat("\n", totalChars))}}
+
+	if _, err := LoadBanner(fsys, "short.txt"); err == nil {
+		t.Error("expected error for banner without glyph rows, got nil")
+	}
+}
+
+// standardWithExtras returns the standard banner followed by extra tagged blocks.
+func standardWithExtras(t *testing.T, extras string) fstest.MapFS {
+	t.Helper()
+	data, err := os.ReadFile("../banners/standard.txt")
+	if err != nil {
+		t.Fatalf("failed to read standard banner: %v", err)
+	}
+	return fstest.MapFS{"extended.txt": {Data: append(data, extras...)}}
+}
+
+func TestLoadBannerCodePointExtension(t *testing.T) {
+	euro := "U+20AC EURO SIGN\n" + strings.Repeat("EUR\n", standardHeight)
+	eAcute := "U+00e9\n" + strings.Repeat("e'\n", standardHeight)
+
+	banner, err := LoadBanner(standardWithExtras(t, euro+eAcute), "extended.txt")
+	if err != nil {
+		t.Fatalf("LoadBanner failed: %v", err)
+	}
+
+	if banner.Height != standardHeight {
+		t.Errorf("height = %d, want %d", banner.Height, standardHeight)
+	}
+	if len(banner.Glyphs) != totalChars+2 {
+		t.Errorf("expected %d chars, got %d", totalChars+2, len(banner.Glyphs))
+	}
+	if got := banner.Glyphs['€']; len(got) != standardHeight || got[0] != "EUR" {
+		t.Errorf("glyph € = %q", got)
+	}
+	if got := banner.Glyphs['é']; len(got) != standardHeight || got[7] != "e'" {
+		t.Errorf("glyph é = %q", got)
+	}
+	if got := banner.Glyphs['~']; len(got) != standardHeight {
+		t.Errorf("standard glyphs changed by extension: ~ = %q", got)
+	}
+}
+
+func TestLoadBannerCodePointExtensionErrors(t *testing.T) {
+	glyph := strings.Repeat("x\n", standardHeight)
+
+	tests := []struct {
+		name   string
+		extras string
+	}{
+		{"short tag", "U+E9\n" + glyph},
+		{"non-hex tag", "U+00G9\n" + glyph},
+		{"surrogate code point", "U+D800\n" + glyph},
+		{"out of range code point", "U+110000\n" + glyph},
+		{"truncated glyph", "U+00E9\nx\nx\n"},
+		{"missing tag", "U+00E9\n" + glyph + "\n" + glyph},
+		{"duplicate character", "U+00E9\n" + glyph + "U+00E9\n" + glyph},
+		{"redefined ASCII character", "U+0041\n" + glyph},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadBanner(standardWithExtras(t, tt.extras), "extended.txt"); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
 	}
 }
@@ -0,0 +1,260 @@
+// Package recognize recovers the text of rendered ASCII art, undoing the
+// full-width rendering of the renderer package.
+//
+// Art is read as the renderer writes it: every line of text is a block of
+// glyph rows, each row the glyphs of the line side by side, and an empty line
+// of text is a single empty row. Recognition matches slices of columns of a
+// block against every glyph of the banner. Where several glyphs match, such as
+// a narrow glyph that is the left part of a wider one, or a blank row that may
+// be an empty line or the first row of a block, the alternatives are tried in
+// turn, backtracking out of any choice that leaves the rest of the art
+// unmatched.
+//
+// Art that has been through an editor or a terminal is accepted too: ANSI
+// escape sequences and carriage returns are ignored, and spaces missing at the
+// end of a row, or blank rows missing at the end of the art, count as spaces.
+//
+// Responsibilities of this package:
+//   - Split art into blocks of glyph rows and empty lines
+//   - Match the columns of each block against the glyphs of a banner
+package recognize
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/vxanthio/ascii-art-color/internal/ansi"
+)
+
+// MismatchError reports art that no sequence of glyphs matches.
+type MismatchError struct {
+	// Row and Column locate, from 1, the first cell of the art that no glyph
+	// matches along the path that got furthest.
+	Row, Column int
+}
+
+// Error describes where recognition failed.
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("no glyph matches the art at row %d, column %d", e.Row, e.Column)
+}
+
+// candidate is a glyph that recognition may match, its rows padded with
+// spaces to the same width.
+type candidate struct {
+	char  rune
+	rows  [][]rune
+	width int
+}
+
+// recognizer holds the state of one recognition: the art, the glyphs, and
+// the positions known not to lead to a match.
+type recognizer struct {
+	rows       [][]rune
+	height     int
+	candidates []candidate
+	// failedRows holds the rows from which the rest of the art is known not
+	// to match.
+	failedRows map[int]bool
+	// furthest is the furthest cell, as row and column, at which no glyph
+	// matched.
+	furthest [2]int
+}
+
+// Text recognizes rendered ASCII art and returns the text it was rendered
+// from.
+//
+// Parameters:
+//   - art: The rendered rows, separated by newlines.
+//   - glyphs: The banner the art was rendered with, mapping each character to
+//     its rows.
+//   - height: The number of rows of every glyph; glyphs of another height are
+//     ignored.
+//
+// Returns:
+//   - The text, its lines separated by newlines, without a trailing newline.
+//   - A *MismatchError if the art cannot be made of the glyphs, or an error
+//     if height is not positive or no glyph has columns.
+func Text(art string, glyphs map[rune][]string, height int) (string, error) {
+	if height <= 0 {
+		return "", fmt.Errorf("invalid glyph height: %d", height)
+	}
+	r := &recognizer{rows: splitRows(art), height: height, failedRows: map[int]bool{}}
+	r.candidates = candidates(glyphs, height)
+	if len(r.candidates) == 0 {
+		return "", fmt.Errorf("banner has no glyphs of height %d", height)
+	}
+
+	lines, ok := r.lines(0)
+	if !ok {
+		return "", &MismatchError{Row: r.furthest[0] + 1, Column: r.furthest[1] + 1}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// splitRows splits art into rows of characters, without escape sequences,
+// carriage returns or the final newline.
+func splitRows(art string) [][]rune {
+	art = strings.TrimSuffix(strings.ReplaceAll(art, "\r", ""), "\n")
+	if art == "" {
+		return nil
+	}
+	lines := strings.Split(art, "\n")
+	rows := make([][]rune, len(lines))
+	for i, line := range lines {
+		rows[i] = []rune(ansi.Strip(line))
+	}
+	return rows
+}
+
+// candidates returns the glyphs of the given height that have columns, the
+// widest first and, among glyphs of the same width, by character, so that
+// recognition is deterministic.
+func candidates(glyphs map[rune][]string, height int) []candidate {
+	var list []candidate
+	for char, glyph := range glyphs {
+		if len(glyph) != height {
+			continue
+		}
+		width := 0
+		for _, row := range glyph {
+			width = max(width, utf8.RuneCountInString(row))
+		}
+		if width == 0 {
+			continue
+		}
+		rows := make([][]rune, height)
+		for i, row := range glyph {
+			rows[i] = []rune(row + strings.Repeat(" ", width-utf8.RuneCountInString(row)))
+		}
+		list = append(list, candidate{char: char, rows: rows, width: width})
+	}
+	slices.SortFunc(list, func(a, b candidate) int {
+		return cmp.Or(cmp.Compare(b.width, a.width), cmp.Compare(a.char, b.char))
+	})
+	return list
+}
+
+// lines recognizes the art from row start to the end.
+//
+// A row that is empty, not even holding spaces, is taken as an empty line
+// first; any other row as the first row of a block. The other reading is
+// tried when the first leads nowhere, for a blank row.
+//
+// Returns:
+//   - The lines of text.
+//   - Whether the rest of the art was recognized.
+func (r *recognizer) lines(start int) ([]string, bool) {
+	if start >= len(r.rows) {
+		return []string{}, true
+	}
+	if r.failedRows[start] {
+		return nil, false
+	}
+
+	empty := func() ([]string, bool) {
+		if !isBlank(r.rows[start]) {
+			return nil, false
+		}
+		rest, ok := r.lines(start + 1)
+		return append([]string{""}, rest...), ok
+	}
+	block := func() ([]string, bool) {
+		line, ok := r.block(start)
+		if !ok {
+			return nil, false
+		}
+		rest, ok := r.lines(start + r.height)
+		return append([]string{line}, rest...), ok
+	}
+
+	first, second := block, empty
+	if len(r.rows[start]) == 0 {
+		first, second = empty, block
+	}
+	if lines, ok := first(); ok {
+		return lines, true
+	}
+	if lines, ok := second(); ok {
+		return lines, true
+	}
+	r.failedRows[start] = true
+	return nil, false
+}
+
+// block recognizes the line of text of the block of glyph rows starting at
+// row start.
+//
+// Returns:
+//   - The line of text.
+//   - Whether the whole block was recognized.
+func (r *recognizer) block(start int) (string, bool) {
+	rows := make([][]rune, r.height)
+	width := 0
+	for i := range rows {
+		if start+i < len(r.rows) {
+			rows[i] = r.rows[start+i]
+		}
+		width = max(width, len(rows[i]))
+	}
+	if width == 0 {
+		return "", false
+	}
+
+	failed := make([]bool, width)
+	var match func(column int) ([]rune, bool)
+	match = func(column int) ([]rune, bool) {
+		if column >= width {
+			return []rune{}, true
+		}
+		if failed[column] {
+			return nil, false
+		}
+		for _, c := range r.candidates {
+			if !fits(rows, column, c) {
+				continue
+			}
+			if rest, ok := match(column + c.width); ok {
+				return append([]rune{c.char}, rest...), true
+			}
+		}
+		failed[column] = true
+		if furthest := [2]int{start, column}; r.furthest[0] < start ||
+			(r.furthest[0] == start && r.furthest[1] < column) {
+			r.furthest = furthest
+		}
+		return nil, false
+	}
+
+	text, ok := match(0)
+	return string(text), ok
+}
+
+// fits reports whether glyph c matches the columns of rows starting at
+// column; cells past the end of a row count as spaces.
+func fits(rows [][]rune, column int, c candidate) bool {
+	for i, glyphRow := range c.rows {
+		for x, want := range glyphRow {
+			got := ' '
+			if column+x < len(rows[i]) {
+				got = rows[i][column+x]
+			}
+			if got != want {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isBlank reports whether a row holds nothing but spaces.
+func isBlank(row []rune) bool {
+	for _, r := range row {
+		if r != ' ' {
+			return false
+		}
+	}
+	return true
+}
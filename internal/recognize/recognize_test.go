@@ -0,0 +1,84 @@
+package recognize_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vxanthio/ascii-art-color/internal/recognize"
+)
+
+// glyphs is a banner of two-row glyphs in which "ab" is also the left part of
+// "abx": 'w' draws what 'a' and the left column of 'b' draw side by side.
+var glyphs = map[rune][]string{
+	' ': {"  ", "  "},
+	'a': {"a", "c"},
+	'b': {"bx", "dy"},
+	'w': {"ab", "cd"},
+	'u': {"  ", "uu"},
+	't': {"tt", "  "},
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name string
+		art  string
+		want string
+	}{
+		{"empty", "", ""},
+		{"one glyph", "ab\ncd\n", "w"},
+		{"backtracks out of a wider glyph", "abx\ncdy\n", "ab"},
+		{"space", "ab  a\ncd  c\n", "w a"},
+		{"empty line between blocks", "a\nc\n\nab\ncd\n", "a\n\nw"},
+		{"leading empty lines", "\n\na\nc\n", "\n\na"},
+		{"blank first row", "  \nuu\n", "u"},
+		{"trimmed rows", "\nuu\n\n\nuu\n", "u\n\nu"},
+		{"missing blank rows at the end", "tt", "t"},
+		{"blank last row", "tt\n\na\nc\n", "t\na"},
+		{"escape sequences", "\033[31mab\033[0m\n\033[1;31mc\033[0md\n", "w"},
+		{"carriage returns", "a\r\nc\r\n", "a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := recognize.Text(tt.art, glyphs, 2)
+			if err != nil {
+				t.Fatalf("Text(%q) error = %v", tt.art, err)
+			}
+			if got != tt.want {
+				t.Errorf("Text(%q) = %q, want %q", tt.art, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestText_Mismatch(t *testing.T) {
+	tests := []struct {
+		name string
+		art  string
+		want recognize.MismatchError
+	}{
+		{"unknown glyph", "ab?\ncd?\n", recognize.MismatchError{Row: 1, Column: 3}},
+		{"second block", "a\nc\nz\nz\n", recognize.MismatchError{Row: 3, Column: 1}},
+		{"shifted rows", "a\nc\na\n", recognize.MismatchError{Row: 3, Column: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := recognize.Text(tt.art, glyphs, 2)
+			var mismatch *recognize.MismatchError
+			if !errors.As(err, &mismatch) {
+				t.Fatalf("Text(%q) error = %v, want a *MismatchError", tt.art, err)
+			}
+			if *mismatch != tt.want {
+				t.Errorf("Text(%q) error = %+v, want %+v", tt.art, *mismatch, tt.want)
+			}
+		})
+	}
+}
+
+func TestText_InvalidBanner(t *testing.T) {
+	if _, err := recognize.Text("a\nc\n", glyphs, 0); err == nil {
+		t.Error("Text() with height 0: expected an error")
+	}
+	if _, err := recognize.Text("a\nc\n", glyphs, 3); err == nil {
+		t.Error("Text() without glyphs of the height: expected an error")
+	}
+}
@@ -0,0 +1,201 @@
+package renderer
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/vxanthio/ascii-art-color/internal/ansi"
+)
+
+// Align selects how rendered blocks are positioned within the target width.
+type Align int
+
+const (
+	// AlignLeft leaves blocks as rendered, against the left edge.
+	AlignLeft Align = iota
+	// AlignCenter pads blocks equally on both sides.
+	AlignCenter
+	// AlignRight pads blocks on the left.
+	AlignRight
+	// AlignJustify spreads the extra columns across the space glyphs between
+	// words. Blocks without such spaces are left as rendered.
+	AlignJustify
+)
+
+var alignNames = map[string]Align{
+	"left":    AlignLeft,
+	"center":  AlignCenter,
+	"right":   AlignRight,
+	"justify": AlignJustify,
+}
+
+// ParseAlign converts an alignment name to an Align.
+//
+// Valid names are left, center, right, and justify.
+//
+// Parameters:
+//   - name: The alignment name to resolve.
+//
+// Returns:
+//   - The matching Align.
+//   - An error if the name is unknown.
+func ParseAlign(name string) (Align, error) {
+	align, ok := alignNames[strings.ToLower(name)]
+	if !ok {
+		return AlignLeft, fmt.Errorf("invalid alignment: %q\nValid options: left, center, right, justify", name)
+	}
+	return align, nil
+}
+
+// VisibleWidth returns the number of columns a rendered row occupies on a
+// terminal. ANSI escape sequences, such as the color codes inserted by the
+// coloring package, take no columns.
+//
+// Parameters:
+//   - row: A rendered row, possibly containing ANSI escape sequences.
+//
+// Returns:
+//   - The number of visible columns.
+func VisibleWidth(row string) int {
+	width := 0
+	for i := 0; i < len(row); {
+		if n, _, _ := ansi.Escape(row[i:]); n > 0 {
+			i += n
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(row[i:])
+		i += size
+		width++
+	}
+	return width
+}
+
+// AlignRows positions the rows of one rendered block within width columns.
+//
+// The rows may already contain ANSI escape sequences; they are not counted as
+// visible columns, and padding is never inserted inside one. Blocks at least
+// width columns wide are returned unchanged, as is every block when align is
+// AlignLeft.
+//
+// Parameters:
+//   - rows: The rendered rows of a single line of text.
+//   - line: The text the block was rendered from.
+//   - widths: The columns each character of line occupies (see Widths).
+//   - width: The target width in columns.
+//   - align: The alignment to apply.
+//
+// Returns:
+//   - The aligned rows.
+func AlignRows(rows []string, line string, widths []int, width int, align Align) []string {
+	if len(rows) == 0 || align == AlignLeft {
+		return rows
+	}
+	extra := width - VisibleWidth(rows[0])
+	if extra <= 0 {
+		return rows
+	}
+
+	aligned := make([]string, len(rows))
+	switch align {
+	case AlignCenter:
+		left := strings.Repeat(" ", extra/2)
+		right := strings.Repeat(" ", extra-extra/2)
+		for i, row := range rows {
+			aligned[i] = left + row + right
+		}
+	case AlignRight:
+		left := strings.Repeat(" ", extra)
+		for i, row := range rows {
+			aligned[i] = left + row
+		}
+	case AlignJustify:
+		gaps := justifyGaps(line, widths, extra)
+		for i, row := range rows {
+			aligned[i] = insertPadding(row, gaps)
+		}
+	default:
+		copy(aligned, rows)
+	}
+	return aligned
+}
+
+// gap is a run of padding inserted after a visible column of a row.
+type gap struct {
+	column int
+	width  int
+}
+
+// justifyGaps distributes extra columns across the spaces between the words of
+// line. Earlier spaces receive one more column when the extra columns do not
+// divide evenly.
+//
+// Parameters:
+//   - line: The text the block was rendered from.
+//   - widths: The columns each character of line occupies.
+//   - extra: The number of columns to distribute.
+//
+// Returns:
+//   - The padding to insert, ordered by column; empty if line has no spaces
+//     between words.
+func justifyGaps(line string, widths []int, extra int) []gap {
+	runes := []rune(line)
+	first, last := 0, len(runes)-1
+	for first <= last && runes[first] == ' ' {
+		first++
+	}
+	for last >= first && runes[last] == ' ' {
+		last--
+	}
+
+	var ends []int
+	column := 0
+	for i, ch := range runes {
+		if i < len(widths) {
+			column += widths[i]
+		}
+		if ch == ' ' && i > first && i < last {
+			ends = append(ends, column)
+		}
+	}
+	if len(ends) == 0 {
+		return nil
+	}
+
+	gaps := make([]gap, len(ends))
+	for i, end := range ends {
+		gaps[i] = gap{column: end, width: extra / len(ends)}
+		if i < extra%len(ends) {
+			gaps[i].width++
+		}
+	}
+	return gaps
+}
+
+// insertPadding inserts the gaps into row, counting only visible columns.
+// Padding at a column goes right after the character that ends it, before any
+// escape sequence that follows.
+func insertPadding(row string, gaps []gap) string {
+	var b strings.Builder
+	column, next := 0, 0
+
+	for i := 0; i < len(row); {
+		for next < len(gaps) && gaps[next].column == column {
+			b.WriteString(strings.Repeat(" ", gaps[next].width))
+			next++
+		}
+		if n, _, _ := ansi.Escape(row[i:]); n > 0 {
+			b.WriteString(row[i : i+n])
+			i += n
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(row[i:])
+		b.WriteString(row[i : i+size])
+		i += size
+		column++
+	}
+	for ; next < len(gaps); next++ {
+		b.WriteString(strings.Repeat(" ", gaps[next].width))
+	}
+	return b.String()
+}
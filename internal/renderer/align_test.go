@@ -0,0 +1,139 @@
+package renderer_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vxanthio/ascii-art-color/internal/renderer"
+)
+
+const red = "\033[38;2;255;0;0m"
+
+func TestParseAlign(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    renderer.Align
+		wantErr bool
+	}{
+		{"left", renderer.AlignLeft, false},
+		{"center", renderer.AlignCenter, false},
+		{"Right", renderer.AlignRight, false},
+		{"justify", renderer.AlignJustify, false},
+		{"middle", renderer.AlignLeft, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderer.ParseAlign(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAlign(%q) error = %v, wantErr %t", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAlign(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVisibleWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		row  string
+		want int
+	}{
+		{"plain", "| |", 3},
+		{"multi-byte", "╔═╗", 3},
+		{"colored", red + "| |" + "\033[0m", 3},
+		{"truncated escape", "ab\033[38;2", 2},
+		{"empty", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderer.VisibleWidth(tt.row); got != tt.want {
+				t.Errorf("VisibleWidth(%q) = %d, want %d", tt.row, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlignRows(t *testing.T) {
+	tests := []struct {
+		name   string
+		rows   []string
+		line   string
+		widths []int
+		width  int
+		align  renderer.Align
+		want   []string
+	}{
+		{"left unchanged", []string{"ab"}, "a", []int{2}, 6, renderer.AlignLeft, []string{"ab"}},
+		{"center", []string{"ab", "cd"}, "a", []int{2}, 7, renderer.AlignCenter, []string{"  ab   ", "  cd   "}},
+		{"right", []string{"ab"}, "a", []int{2}, 5, renderer.AlignRight, []string{"   ab"}},
+		{"wider than target", []string{"abcdef"}, "a", []int{6}, 4, renderer.AlignRight, []string{"abcdef"}},
+		{"justify one gap", []string{"aa_bb"}, "a b", []int{2, 1, 2}, 8, renderer.AlignJustify, []string{"aa_   bb"}},
+		{"justify uneven gaps", []string{"a_b_c"}, "a b c", []int{1, 1, 1, 1, 1}, 8, renderer.AlignJustify, []string{"a_  b_ c"}},
+		{"justify ignores outer spaces", []string{"_a_b_"}, " a b ", []int{1, 1, 1, 1, 1}, 7, renderer.AlignJustify, []string{"_a_  b_"}},
+		{"justify without gaps", []string{"ab"}, "ab", []int{1, 1}, 6, renderer.AlignJustify, []string{"ab"}},
+		{"colored center", []string{red + "ab\033[0m"}, "a", []int{2}, 4, renderer.AlignCenter, []string{" " + red + "ab\033[0m "}},
+		{
+			"colored justify", []string{"aa" + red + "_bb\033[0m"}, "a b", []int{2, 1, 2}, 7, renderer.AlignJustify,
+			[]string{"aa" + red + "_  bb\033[0m"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderer.AlignRows(tt.rows, tt.line, tt.widths, tt.width, tt.align)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestASCIIWithOptions_Align(t *testing.T) {
+	tests := []struct {
+		name string
+		opts renderer.Options
+		want []string
+	}{
+		{"center in width", renderer.Options{Width: 10, Align: renderer.AlignCenter}, []string{"   a|b|   ", "", "    c|    "}},
+		{"right to widest block", renderer.Options{Align: renderer.AlignRight}, []string{"a|b|", "", "  c|"}},
+		{"justify wrapped pieces", renderer.Options{Width: 6, Align: renderer.AlignJustify}, []string{"a|b|", "", "c|"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := renderer.ASCIIWithOptions("ab\n\nc", wrapBanner(), tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			rows := strings.Split(output, "\n")
+			for i, want := range tt.want {
+				row := rows[0]
+				switch i {
+				case 1:
+					row = rows[8]
+				case 2:
+					row = rows[9]
+				}
+				if row != want {
+					t.Errorf("block %d first row = %q, want %q", i, row, want)
+				}
+			}
+		})
+	}
+
+	output, err := renderer.ASCIIWithOptions("ab c", wrapBanner(), renderer.Options{Width: 9, Align: renderer.AlignJustify})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.SplitN(output, "\n", 2)[0]; got != "a|b|   c|" {
+		t.Errorf("justified row = %q, want %q", got, "a|b|   c|")
+	}
+
+	if _, err := renderer.ASCIIWithOptions("aB", wrapBanner(), renderer.Options{Align: renderer.AlignCenter}); err == nil {
+		t.Error("expected error for missing character, got nil")
+	}
+}
@@ -0,0 +1,348 @@
+package renderer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Layout selects how horizontally adjacent glyphs are joined.
+type Layout int
+
+const (
+	// FullWidth places glyphs side by side at their full width.
+	FullWidth Layout = iota
+	// Fitting moves each glyph left until it touches the previous one
+	// (FIGlet "kerning").
+	Fitting
+	// Smushing overlaps touching glyphs by one more column wherever the
+	// boundary characters can be merged by one of the enabled Rules.
+	// With no rules enabled it behaves like Fitting.
+	Smushing
+	// UniversalSmushing overlaps touching glyphs by one more column and
+	// keeps the character of the later glyph. Visible characters always
+	// replace hardblanks.
+	UniversalSmushing
+)
+
+// SmushRule is a bit set of the FIGlet controlled smushing rules.
+// The values match the rule bits of a FIGlet font's layout parameters.
+type SmushRule int
+
+const (
+	// SmushEqual merges two identical characters into one.
+	SmushEqual SmushRule = 1 << iota
+	// SmushUnderscore replaces an underscore with a border character.
+	SmushUnderscore
+	// SmushHierarchy keeps the character from the higher class of
+	// | /\ [] {} () <>.
+	SmushHierarchy
+	// SmushOppositePair merges opposing brackets into a vertical bar.
+	SmushOppositePair
+	// SmushBigX merges /\ into |, \/ into Y and >< into X.
+	SmushBigX
+	// SmushHardblank merges two hardblanks into one.
+	SmushHardblank
+
+	// AllSmushRules enables every controlled smushing rule.
+	AllSmushRules = SmushEqual | SmushUnderscore | SmushHierarchy |
+		SmushOppositePair | SmushBigX | SmushHardblank
+)
+
+// FIGlet full_layout bits selecting horizontal fitting and smushing.
+const (
+	figletKerning  = 64
+	figletSmushing = 128
+)
+
+// underscoreBorders lists the characters that replace an underscore.
+const underscoreBorders = "|/\\[]{}()<>"
+
+// hierarchyClasses lists the classes of the hierarchy rule from lowest to
+// highest; a character from a higher class replaces one from a lower class.
+var hierarchyClasses = []string{"|", "/\\", "[]", "{}", "()", "<>"}
+
+var layoutNames = map[string]Layout{
+	"full":      FullWidth,
+	"fitting":   Fitting,
+	"kerning":   Fitting,
+	"smushing":  Smushing,
+	"universal": UniversalSmushing,
+}
+
+// ParseLayout converts a layout name to a Layout.
+//
+// Valid names are full, fitting (or kerning), smushing, and universal.
+//
+// Parameters:
+//   - name: The layout name to resolve.
+//
+// Returns:
+//   - The matching Layout.
+//   - An error if the name is unknown.
+func ParseLayout(name string) (Layout, error) {
+	layout, ok := layoutNames[strings.ToLower(name)]
+	if !ok {
+		return FullWidth, fmt.Errorf("invalid layout: %q\nValid options: full, fitting, smushing, universal", name)
+	}
+	return layout, nil
+}
+
+// FIGletLayout decodes the horizontal layout of a FIGlet font from its
+// full_layout header parameter.
+//
+// Parameters:
+//   - fullLayout: The full_layout value of the font.
+//
+// Returns:
+//   - The Layout the font asks for.
+//   - The controlled smushing rules the font enables.
+func FIGletLayout(fullLayout int) (Layout, SmushRule) {
+	rules := SmushRule(fullLayout) & AllSmushRules
+	switch {
+	case fullLayout&figletSmushing != 0 && rules == 0:
+		return UniversalSmushing, 0
+	case fullLayout&figletSmushing != 0:
+		return Smushing, rules
+	case fullLayout&figletKerning != 0:
+		return Fitting, rules
+	default:
+		return FullWidth, rules
+	}
+}
+
+// composeLine joins the glyphs of one input line according to opts.
+//
+// It follows the FIGlet algorithm: each glyph is moved left by the largest
+// amount that every row allows, and the overlapping columns are merged.
+//
+// Parameters:
+//   - glyphs: The glyph rows of each character of the line, in order.
+//   - height: The number of rows of every glyph.
+//   - opts: The layout options.
+//
+// Returns:
+//   - The rendered rows, with hardblanks replaced by spaces.
+//   - The number of columns attributed to each character (see Widths).
+func composeLine(glyphs [][]string, height int, opts Options) ([]string, []int) {
+	rows := make([][]rune, height)
+	bounds := make([]int, len(glyphs))
+	prevWidth := 0
+
+	for i, glyph := range glyphs {
+		current, width := glyphRunes(glyph)
+
+		amount := 0
+		if i > 0 {
+			amount = smushAmount(rows, current, prevWidth, width, opts)
+		}
+
+		start := len(rows[0]) - amount
+		if i > 0 {
+			bounds[i] = min(max(start+leadingBlanks(current, width), bounds[i-1]), len(rows[0]))
+		}
+
+		for r := range rows {
+			lineLen := len(rows[r])
+			for k := 0; k < amount; k++ {
+				col := max(lineLen-amount+k, 0)
+				if merged, ok := smush(rows[r][col], current[r][k], prevWidth, width, opts); ok {
+					rows[r][col] = merged
+				} else {
+					rows[r][col] = current[r][k]
+				}
+			}
+			rows[r] = append(rows[r], current[r][amount:]...)
+		}
+		prevWidth = width
+	}
+
+	widths := make([]int, len(glyphs))
+	for i := range bounds {
+		end := len(rows[0])
+		if i+1 < len(bounds) {
+			end = bounds[i+1]
+		}
+		widths[i] = end - bounds[i]
+	}
+
+	result := make([]string, len(rows))
+	for r, row := range rows {
+		result[r] = string(row)
+		if opts.Hardblank != 0 {
+			result[r] = strings.ReplaceAll(result[r], string(opts.Hardblank), " ")
+		}
+	}
+	return result, widths
+}
+
+// leadingBlanks returns the number of blank columns at the left edge of a
+// glyph, or zero if the glyph is blank everywhere.
+func leadingBlanks(glyph [][]rune, width int) int {
+	lead := width
+	for _, row := range glyph {
+		blanks := 0
+		for blanks < width && row[blanks] == ' ' {
+			blanks++
+		}
+		lead = min(lead, blanks)
+	}
+	if lead == width {
+		return 0
+	}
+	return lead
+}
+
+// glyphRunes converts glyph rows to rune slices padded to the widest row.
+func glyphRunes(glyph []string) ([][]rune, int) {
+	rows := make([][]rune, len(glyph))
+	width := 0
+	for i, row := range glyph {
+		rows[i] = []rune(row)
+		width = max(width, len(rows[i]))
+	}
+	for i := range rows {
+		for len(rows[i]) < width {
+			rows[i] = append(rows[i], ' ')
+		}
+	}
+	return rows, width
+}
+
+// smushAmount returns how many columns the next glyph may overlap the rows
+// rendered so far.
+//
+// For every row the amount is the blank space between the last visible
+// character of the row and the first visible character of the glyph, plus one
+// if those two characters can be smushed. The smallest amount over all rows,
+// capped at the glyph width, is used.
+func smushAmount(rows, current [][]rune, prevWidth, width int, opts Options) int {
+	if opts.Layout == FullWidth {
+		return 0
+	}
+
+	amount := width
+	for r, line := range rows {
+		lineBound := len(line) - 1
+		for lineBound >= 0 && line[lineBound] == ' ' {
+			lineBound--
+		}
+
+		charBound := 0
+		for charBound < width && current[r][charBound] == ' ' {
+			charBound++
+		}
+
+		rowAmount := charBound + len(line) - 1 - lineBound
+		if lineBound >= 0 && charBound < width {
+			if _, ok := smush(line[lineBound], current[r][charBound], prevWidth, width, opts); ok {
+				rowAmount++
+			}
+		}
+		amount = min(amount, rowAmount)
+	}
+	return amount
+}
+
+// smush merges two overlapping characters.
+//
+// Parameters:
+//   - left: The character already rendered.
+//   - right: The character of the glyph being added.
+//   - prevWidth: Width of the previous glyph.
+//   - width: Width of the glyph being added.
+//   - opts: The layout options.
+//
+// Returns:
+//   - The merged character.
+//   - false if the characters cannot be merged.
+func smush(left, right rune, prevWidth, width int, opts Options) (rune, bool) {
+	if left == ' ' {
+		return right, true
+	}
+	if right == ' ' {
+		return left, true
+	}
+	// Glyphs narrower than two columns are never smushed into.
+	if prevWidth < 2 || width < 2 {
+		return 0, false
+	}
+
+	hardblank := opts.Hardblank
+	switch opts.Layout {
+	case UniversalSmushing:
+		if right == hardblank {
+			return left, true
+		}
+		return right, true
+	case Smushing:
+		return smushControlled(left, right, hardblank, opts.Rules)
+	default:
+		return 0, false
+	}
+}
+
+// smushControlled applies the enabled controlled smushing rules to a pair of
+// visible characters.
+func smushControlled(left, right, hardblank rune, rules SmushRule) (rune, bool) {
+	if left == hardblank || right == hardblank {
+		if rules&SmushHardblank != 0 && left == right {
+			return left, true
+		}
+		return 0, false
+	}
+
+	if rules&SmushEqual != 0 && left == right {
+		return left, true
+	}
+
+	if rules&SmushUnderscore != 0 {
+		if left == '_' && strings.ContainsRune(underscoreBorders, right) {
+			return right, true
+		}
+		if right == '_' && strings.ContainsRune(underscoreBorders, left) {
+			return left, true
+		}
+	}
+
+	if rules&SmushHierarchy != 0 {
+		leftClass, rightClass := hierarchyClass(left), hierarchyClass(right)
+		if leftClass >= 0 && rightClass >= 0 {
+			if leftClass < rightClass {
+				return right, true
+			}
+			if rightClass < leftClass {
+				return left, true
+			}
+		}
+	}
+
+	if rules&SmushOppositePair != 0 {
+		switch string([]rune{left, right}) {
+		case "[]", "][", "{}", "}{", "()", ")(":
+			return '|', true
+		}
+	}
+
+	if rules&SmushBigX != 0 {
+		switch string([]rune{left, right}) {
+		case "/\\":
+			return '|', true
+		case "\\/":
+			return 'Y', true
+		case "><":
+			return 'X', true
+		}
+	}
+
+	return 0, false
+}
+
+// hierarchyClass returns the hierarchy class of r, or -1 if r has none.
+func hierarchyClass(r rune) int {
+	for class, members := range hierarchyClasses {
+		if strings.ContainsRune(members, r) {
+			return class
+		}
+	}
+	return -1
+}
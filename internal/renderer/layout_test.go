@@ -0,0 +1,295 @@
+package renderer_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/vxanthio/ascii-art-color/internal/parser"
+	"github.com/vxanthio/ascii-art-color/internal/renderer"
+)
+
+// glyph pads the given rows with blank rows of the same width to the eight
+// rows every banner entry must have.
+func glyph(rows ...string) []string {
+	padded := append([]string{}, rows...)
+	for len(padded) < 8 {
+		padded = append(padded, strings.Repeat(" ", utf8.RuneCountInString(rows[0])))
+	}
+	return padded
+}
+
+// eightRows returns a banner of the given glyphs, which have eight rows like
+// the glyphs of the standard banners.
+func eightRows(glyphs map[rune][]string) parser.Banner {
+	return parser.Banner{Glyphs: glyphs, Height: 8}
+}
+
+// firstRow renders input and returns the first output row.
+func firstRow(t *testing.T, input string, banner map[rune][]string, opts renderer.Options) string {
+	t.Helper()
+	output, err := renderer.ASCIIWithOptions(input, eightRows(banner), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return strings.SplitN(output, "\n", 2)[0]
+}
+
+func TestASCIIWithOptions_Layouts(t *testing.T) {
+	banner := map[rune][]string{
+		'A': glyph("A   "),
+		'B': glyph("  B "),
+		'|': glyph(" | "),
+		'_': glyph(" _ "),
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		opts  renderer.Options
+		want  string
+	}{
+		{"full width", "AB", renderer.Options{}, "A     B "},
+		{"fitting removes blank columns", "AB", renderer.Options{Layout: renderer.Fitting}, "A B "},
+		{"fitting keeps visible characters", "||", renderer.Options{Layout: renderer.Fitting}, " || "},
+		{"smushing equal characters", "||", renderer.Options{Layout: renderer.Smushing, Rules: renderer.SmushEqual}, " | "},
+		{"smushing without matching rule fits", "||", renderer.Options{Layout: renderer.Smushing, Rules: renderer.SmushBigX}, " || "},
+		{"smushing underscore", "_|", renderer.Options{Layout: renderer.Smushing, Rules: renderer.SmushUnderscore}, " | "},
+		{"universal keeps later character", "_|", renderer.Options{Layout: renderer.UniversalSmushing}, " | "},
+		{"universal overlaps", "|_", renderer.Options{Layout: renderer.UniversalSmushing}, " _ "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := firstRow(t, tt.input, banner, tt.opts); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestASCIIWithOptions_SmushingRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		left  string
+		right string
+		rules renderer.SmushRule
+		want  string
+	}{
+		{"equal", "x", "x", renderer.SmushEqual, " x "},
+		{"underscore left", "_", "/", renderer.SmushUnderscore, " / "},
+		{"underscore right", "]", "_", renderer.SmushUnderscore, " ] "},
+		{"hierarchy bar and slash", "|", "/", renderer.SmushHierarchy, " / "},
+		{"hierarchy brace and bracket", "{", "[", renderer.SmushHierarchy, " { "},
+		{"hierarchy parens and angle", "(", ">", renderer.SmushHierarchy, " > "},
+		{"hierarchy same class", "/", "\\", renderer.SmushHierarchy, " /\\ "},
+		{"opposite brackets", "[", "]", renderer.SmushOppositePair, " | "},
+		{"opposite parens", ")", "(", renderer.SmushOppositePair, " | "},
+		{"big x slash backslash", "/", "\\", renderer.SmushBigX, " | "},
+		{"big x backslash slash", "\\", "/", renderer.SmushBigX, " Y "},
+		{"big x angles", ">", "<", renderer.SmushBigX, " X "},
+		{"rule not enabled", "x", "x", renderer.SmushBigX, " xx "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			banner := map[rune][]string{
+				'L': glyph(" " + tt.left + " "),
+				'R': glyph(" " + tt.right + " "),
+			}
+			opts := renderer.Options{Layout: renderer.Smushing, Rules: tt.rules}
+			if got := firstRow(t, "LR", banner, opts); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestASCIIWithOptions_Hardblank(t *testing.T) {
+	banner := map[rune][]string{
+		'A': glyph("A$"),
+		'B': glyph("$B"),
+		' ': glyph("$$"),
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		opts  renderer.Options
+		want  string
+	}{
+		{"full width prints spaces", "A B", renderer.Options{Hardblank: '$'}, "A    B"},
+		{"hardblanks are not fitted away", "AB", renderer.Options{Layout: renderer.Fitting, Hardblank: '$'}, "A  B"},
+		{"hardblank rule", "AB", renderer.Options{Layout: renderer.Smushing, Rules: renderer.SmushHardblank, Hardblank: '$'}, "A B"},
+		{"other rules ignore hardblanks", "AB", renderer.Options{Layout: renderer.Smushing, Rules: renderer.SmushEqual, Hardblank: '$'}, "A  B"},
+		{"universal smushes hardblanks", "AB", renderer.Options{Layout: renderer.UniversalSmushing, Hardblank: '$'}, "A B"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := firstRow(t, tt.input, banner, tt.opts); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestASCIIWithOptions_NarrowGlyphsAreNotSmushed(t *testing.T) {
+	banner := map[rune][]string{
+		'|': glyph("|"),
+	}
+	opts := renderer.Options{Layout: renderer.Smushing, Rules: renderer.AllSmushRules}
+
+	if got := firstRow(t, "||", banner, opts); got != "||" {
+		t.Errorf("got %q, want %q", got, "||")
+	}
+}
+
+func TestASCIIWithOptions_Errors(t *testing.T) {
+	banner := map[rune][]string{
+		'A': glyph("A "),
+		'B': {"B"},
+	}
+	opts := renderer.Options{Layout: renderer.Fitting}
+
+	if _, err := renderer.ASCIIWithOptions("AC", eightRows(banner), opts); err == nil {
+		t.Error("expected error for missing character, got nil")
+	}
+	if _, err := renderer.ASCIIWithOptions("AB", eightRows(banner), opts); err == nil {
+		t.Error("expected error for corrupted glyph, got nil")
+	}
+}
+
+func TestWidths(t *testing.T) {
+	banner := map[rune][]string{
+		'A': glyph("A   "),
+		'B': glyph("  B "),
+	}
+
+	tests := []struct {
+		name string
+		line string
+		opts renderer.Options
+		want []int
+	}{
+		{"empty line", "", renderer.Options{}, []int{}},
+		{"full width", "AB", renderer.Options{}, []int{4, 4}},
+		{"fitting", "AB", renderer.Options{Layout: renderer.Fitting}, []int{2, 2}},
+		{"fitting three glyphs", "ABA", renderer.Options{Layout: renderer.Fitting}, []int{2, 1, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderer.Widths(tt.line, eightRows(banner), tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("got %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+
+	if _, err := renderer.Widths("A\tB", eightRows(banner), renderer.Options{}); err == nil {
+		t.Error("expected error for invalid character, got nil")
+	}
+	if _, err := renderer.Widths("AC", eightRows(banner), renderer.Options{}); err == nil {
+		t.Error("expected error for missing character, got nil")
+	}
+}
+
+func TestParseLayout(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    renderer.Layout
+		wantErr bool
+	}{
+		{"full", renderer.FullWidth, false},
+		{"fitting", renderer.Fitting, false},
+		{"kerning", renderer.Fitting, false},
+		{"Smushing", renderer.Smushing, false},
+		{"universal", renderer.UniversalSmushing, false},
+		{"squash", renderer.FullWidth, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderer.ParseLayout(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLayout(%q) error = %v, wantErr %t", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLayout(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFIGletLayout(t *testing.T) {
+	tests := []struct {
+		name       string
+		fullLayout int
+		wantLayout renderer.Layout
+		wantRules  renderer.SmushRule
+	}{
+		{"full width", 0, renderer.FullWidth, 0},
+		{"kerning", 64, renderer.Fitting, 0},
+		{"controlled smushing", 128 | 15, renderer.Smushing, 15},
+		{"universal smushing", 128, renderer.UniversalSmushing, 0},
+		{"vertical bits ignored", 128 | 24463&^255 | 1, renderer.Smushing, renderer.SmushEqual},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, rules := renderer.FIGletLayout(tt.fullLayout)
+			if layout != tt.wantLayout || rules != tt.wantRules {
+				t.Errorf("FIGletLayout(%d) = %v, %v, want %v, %v",
+					tt.fullLayout, layout, rules, tt.wantLayout, tt.wantRules)
+			}
+		})
+	}
+}
+
+func TestASCIIWithOptions_Height(t *testing.T) {
+	banner := parser.Banner{Height: 3, Glyphs: map[rune][]string{
+		'A': {"/\\", "||", "  "},
+		'B': {"|)", "|)", "  "},
+	}}
+
+	tests := []struct {
+		name string
+		opts renderer.Options
+	}{
+		{"full width", renderer.Options{}},
+		{"fitting", renderer.Options{Layout: renderer.Fitting}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderer.ASCIIWithOptions("AB\nA", banner, tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want := "/\\|)\n|||)\n    \n/\\\n||\n  \n"
+			if got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		})
+	}
+
+	for _, height := range []int{0, 4} {
+		wrong := parser.Banner{Glyphs: banner.Glyphs, Height: height}
+		if _, err := renderer.ASCIIWithOptions("A", wrong, renderer.Options{}); err == nil {
+			t.Errorf("expected error for glyphs of 3 rows in a banner of height %d, got nil", height)
+		}
+		if _, err := renderer.Widths("A", wrong, renderer.Options{}); err == nil {
+			t.Errorf("Widths: expected error for glyphs of 3 rows in a banner of height %d, got nil", height)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package renderer
+
+// Options controls how ASCIIWithOptions lays out glyphs.
+//
+// The zero value renders glyphs at full width, which is the behavior of ASCII.
+type Options struct {
+	// Layout selects full width, fitting, or smushing.
+	Layout Layout
+	// Rules lists the controlled smushing rules used by the Smushing layout.
+	Rules SmushRule
+	// Hardblank is the character a FIGlet font uses for spaces that must not
+	// be fitted or smushed away. It is rendered as a space. Zero means the
+	// banner has no hardblank.
+	Hardblank rune
+	// Fallback is the banner character drawn in place of characters the
+	// banner does not define, such as '?'. Zero means missing characters are
+	// an error.
+	Fallback rune
+	// Width is the maximum number of columns of a rendered row. Longer lines
+	// are wrapped at spaces, and words wider than Width are hard-broken. Zero
+	// disables wrapping.
+	Width int
+	// Align positions each rendered block within Width columns, or within the
+	// width of the widest block when Width is zero. The zero value, AlignLeft,
+	// leaves blocks unpadded.
+	Align Align
+}
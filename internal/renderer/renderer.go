@@ -1,14 +1,28 @@
 // Package renderer provides functionality for converting input text into ASCII art
 // using predefined banner character definitions.
 //
-// The renderer processes printable ASCII characters (range 32–126) and renders each
-// character as an ASCII-art block with a fixed height (bannerHeight).
+// The renderer processes printable Unicode characters, including multi-byte runes
+// such as accented letters, and renders each character as an ASCII-art block whose
+// height is given by the banner (parser.Banner.Height).
 // Newline characters ('\n') are treated as line separators and produce empty output lines.
 //
+// Characters a banner does not define are an error unless Options.Fallback names
+// a banner character to draw in their place.
+//
+// Lines can be wrapped at word boundaries to fit a column limit (Options.Width);
+// words wider than the limit are hard-broken.
+//
+// Glyphs are joined at full width by default. ASCIIWithOptions also supports the
+// FIGlet layouts: fitting (kerning), controlled smushing with the six FIGlet
+// smushing rules, and universal smushing.
+//
 // Responsibilities of this package:
 //   - Validate input characters
 //   - Validate banner integrity
 //   - Render ASCII-art output
+//   - Fit and smush adjacent glyphs
+//   - Wrap lines to a column limit
+//   - Align blocks left, center, right, or justified
 //
 // Any invalid input or malformed banner data results in an error.
 package renderer
@@ -16,36 +30,61 @@ package renderer
 import (
 	"fmt"
 	"strings"
-)
+	"unicode"
+	"unicode/utf8"
 
-const bannerHeight = 8
+	"github.com/vxanthio/ascii-art-color/internal/parser"
+)
 
-// ASCII converts an input string into ASCII art using the provided banner map.
+// ASCII converts an input string into ASCII art using the provided banner.
 //
-// The input may contain printable ASCII characters (codes 32–126) and newline
-// characters ('\n'). Newlines are treated as line separators and are not rendered
-// as visible characters.
+// The input may contain printable characters and newline characters ('\n').
+// Newlines are treated as line separators and are not rendered as visible
+// characters.
 //
 // Rendering rules:
 //   - Empty input or input consisting only of a single newline returns an empty result.
 //   - Consecutive newline characters produce empty output lines.
-//   - Each non-empty input line is rendered as a block of bannerHeight ASCII-art rows.
+//   - Each non-empty input line is rendered as a block of banner.Height ASCII-art rows.
 //   - A trailing newline does not produce an extra ASCII-art block.
 //
 // Validation rules:
-//   - Input must contain only printable ASCII characters (excluding '\n').
-//   - Banner map must not be empty.
-//   - Every character used in input must exist in the banner map.
-//   - Each banner entry must contain exactly bannerHeight rows.
+//   - Input must be valid UTF-8 and contain only printable characters (excluding '\n').
+//   - Banner glyphs must not be empty and the banner height must be positive.
+//   - Every character used in input must exist in the banner.
+//   - Each banner entry must contain exactly banner.Height rows.
 //
 // Parameters:
 //   - input: The text to render as ASCII art.
-//   - banner: A map associating each rune with its ASCII-art representation.
+//   - banner: The banner glyphs and their height.
 //
 // Returns:
 //   - The rendered ASCII-art string.
 //   - An error if input validation or banner validation fails.
-func ASCII(input string, banner map[rune][]string) (string, error) {
+func ASCII(input string, banner parser.Banner) (string, error) {
+	return ASCIIWithOptions(input, banner, Options{})
+}
+
+// ASCIIWithOptions converts an input string into ASCII art using the provided
+// banner and layout options.
+//
+// It follows the same rendering and validation rules as ASCII, except that
+// characters missing from the banner are drawn with the opts.Fallback glyph
+// when set. When opts.Width is positive, each line is first wrapped with
+// WrapLine and every piece is rendered as its own block. Blocks are then
+// positioned with AlignRows according to opts.Align, within opts.Width
+// columns or, when no width is set, within the width of the widest block.
+// With the zero Options the output is identical to ASCII.
+//
+// Parameters:
+//   - input: The text to render as ASCII art.
+//   - banner: The banner glyphs and their height.
+//   - opts: The layout options.
+//
+// Returns:
+//   - The rendered ASCII-art string.
+//   - An error if input validation or banner validation fails.
+func ASCIIWithOptions(input string, banner parser.Banner, opts Options) (string, error) {
 	var result strings.Builder
 
 	if err := validateInput(input); err != nil {
@@ -62,30 +101,208 @@ func ASCII(input string, banner map[rune][]string) (string, error) {
 		return "", nil
 	}
 
-	if len(banner) == 0 {
-		return "", fmt.Errorf("banner is empty")
+	if err := validateBanner(banner); err != nil {
+		return "", err
+	}
+
+	if opts.Align != AlignLeft {
+		return renderAligned(parts, banner, opts)
 	}
 
 	for _, line := range parts {
-		// Handle empty lines produced by consecutive newline characters
-		if line == "" {
+		pieces := []string{line}
+		if opts.Width > 0 {
+			var err error
+			if pieces, err = WrapLine(line, banner, opts); err != nil {
+				return "", err
+			}
+		}
+		for _, piece := range pieces {
+			if err := writeBlock(&result, piece, banner, opts); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return result.String(), nil
+}
+
+// renderAligned renders the lines of the input and aligns every block.
+//
+// Parameters:
+//   - parts: The input lines, without newline characters.
+//   - banner: The banner glyphs and their height.
+//   - opts: The layout options, including the alignment.
+//
+// Returns:
+//   - The rendered ASCII-art string.
+//   - An error if a character is missing or malformed in the banner.
+func renderAligned(parts []string, banner parser.Banner, opts Options) (string, error) {
+	type block struct {
+		line   string
+		rows   []string
+		widths []int
+	}
+
+	var blocks []block
+	target := opts.Width
+	for _, line := range parts {
+		pieces, err := WrapLine(line, banner, opts)
+		if err != nil {
+			return "", err
+		}
+		for _, piece := range pieces {
+			if piece == "" {
+				blocks = append(blocks, block{})
+				continue
+			}
+			rows, widths, err := renderLine(piece, banner, opts)
+			if err != nil {
+				return "", err
+			}
+			blocks = append(blocks, block{line: piece, rows: rows, widths: widths})
+			if opts.Width <= 0 {
+				target = max(target, VisibleWidth(rows[0]))
+			}
+		}
+	}
+
+	var result strings.Builder
+	for _, b := range blocks {
+		if b.rows == nil {
 			result.WriteString("\n")
 			continue
 		}
+		for _, row := range AlignRows(b.rows, b.line, b.widths, target, opts.Align) {
+			result.WriteString(row)
+			result.WriteString("\n")
+		}
+	}
+	return result.String(), nil
+}
 
-		for i := 0; i < bannerHeight; i++ {
+// writeBlock renders a single line of text as a block of rows and writes it to
+// result.
+//
+// Parameters:
+//   - result: The builder receiving the rendered rows.
+//   - line: A single line of text without newline characters.
+//   - banner: The banner glyphs and their height.
+//   - opts: The layout options.
+//
+// Returns:
+//   - An error if a character is missing or malformed in the banner.
+func writeBlock(result *strings.Builder, line string, banner parser.Banner, opts Options) error {
+	// Handle empty lines produced by consecutive newline characters
+	if line == "" {
+		result.WriteString("\n")
+		return nil
+	}
+
+	if opts.Layout == FullWidth && opts.Hardblank == 0 {
+		for i := 0; i < banner.Height; i++ {
 			for _, ch := range line {
-				value, err := validateBannerCharacters(ch, banner)
+				value, err := lookupGlyph(ch, banner, opts)
 				if err != nil {
-					return "", err
+					return err
 				}
 				result.WriteString(value[i])
 			}
 			result.WriteString("\n")
 		}
+		return nil
 	}
 
-	return result.String(), nil
+	rows, _, err := renderLine(line, banner, opts)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		result.WriteString(row)
+		result.WriteString("\n")
+	}
+	return nil
+}
+
+// Widths returns the number of columns each character of a single input line
+// occupies in the output of ASCIIWithOptions.
+//
+// With the FullWidth layout these are the glyph widths. When glyphs are fitted
+// or smushed, the boundary between two characters is the first column in which
+// the later glyph draws anything, so the widths always add up to the width of
+// the rendered rows.
+//
+// Parameters:
+//   - line: A single line of text without newline characters.
+//   - banner: The banner glyphs and their height.
+//   - opts: The layout options.
+//
+// Returns:
+//   - A slice with one width per character in line.
+//   - An error if input validation or banner validation fails.
+func Widths(line string, banner parser.Banner, opts Options) ([]int, error) {
+	if err := validateInput(line); err != nil {
+		return nil, err
+	}
+	if line == "" {
+		return []int{}, nil
+	}
+	if err := validateBanner(banner); err != nil {
+		return nil, err
+	}
+	_, widths, err := renderLine(line, banner, opts)
+	if err != nil {
+		return nil, err
+	}
+	return widths, nil
+}
+
+// renderLine validates the glyphs of a single non-empty line and joins them
+// according to opts.
+//
+// Parameters:
+//   - line: A single non-empty line of text.
+//   - banner: The banner glyphs and their height.
+//   - opts: The layout options.
+//
+// Returns:
+//   - The rendered rows of the line.
+//   - The number of columns attributed to each character.
+//   - An error if a character is missing or malformed in the banner.
+func renderLine(line string, banner parser.Banner, opts Options) ([]string, []int, error) {
+	glyphs := make([][]string, 0, utf8.RuneCountInString(line))
+	for _, ch := range line {
+		value, err := lookupGlyph(ch, banner, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		glyphs = append(glyphs, value)
+	}
+
+	rows, widths := composeLine(glyphs, banner.Height, opts)
+	return rows, widths, nil
+}
+
+// lookupGlyph returns the glyph used to draw ch, substituting the fallback
+// glyph when the banner does not define ch and opts.Fallback is set.
+//
+// Parameters:
+//   - ch: The character to draw.
+//   - banner: The banner glyphs and their height.
+//   - opts: The layout options holding the fallback.
+//
+// Returns:
+//   - The ASCII-art rows used for the character.
+//   - An error if neither the character nor the fallback is in the banner,
+//     or if the glyph does not contain exactly the expected number of rows.
+func lookupGlyph(ch rune, banner parser.Banner, opts Options) ([]string, error) {
+	if _, exists := banner.Glyphs[ch]; !exists && opts.Fallback != 0 {
+		if _, exists := banner.Glyphs[opts.Fallback]; !exists {
+			return []string{}, fmt.Errorf("fallback character %q not found in banner", opts.Fallback)
+		}
+		ch = opts.Fallback
+	}
+	return validateBannerCharacters(ch, banner.Glyphs, banner.Height)
 }
 
 // validateBannerCharacters validates that a character exists in the banner map
@@ -94,30 +311,48 @@ func ASCII(input string, banner map[rune][]string) (string, error) {
 // Parameters:
 //   - ch: The character to validate.
 //   - banner: The banner map containing ASCII-art definitions.
+//   - height: The number of rows every glyph must have.
 //
 // Returns:
 //   - The ASCII-art rows corresponding to the character.
 //   - An error if the character does not exist in the banner
-//     or if it does not contain exactly bannerHeight rows.
-func validateBannerCharacters(ch rune, banner map[rune][]string) ([]string, error) {
+//     or if it does not contain exactly height rows.
+func validateBannerCharacters(ch rune, banner map[rune][]string, height int) ([]string, error) {
 	value, exists := banner[ch]
 	if !exists {
-		return []string{}, fmt.Errorf("character %c (ASCII %d) not found in banner", ch, ch)
+		return []string{}, fmt.Errorf("character %c (U+%04X) not found in banner", ch, ch)
 	}
-	if len(value) != bannerHeight {
+	if len(value) != height {
 		return []string{}, fmt.Errorf(
-			"banner entry for %c (ASCII %d) has %d lines, expected %d",
-			ch, ch, len(value), bannerHeight,
+			"banner entry for %c (U+%04X) has %d lines, expected %d",
+			ch, ch, len(value), height,
 		)
 	}
 	return value, nil
 }
 
+// validateBanner checks that a banner has glyphs and a positive height.
+//
+// Parameters:
+//   - banner: The banner to validate.
+//
+// Returns:
+//   - An error if the banner has no glyphs or its height is not positive.
+func validateBanner(banner parser.Banner) error {
+	if len(banner.Glyphs) == 0 {
+		return fmt.Errorf("banner is empty")
+	}
+	if banner.Height <= 0 {
+		return fmt.Errorf("invalid banner height: %d", banner.Height)
+	}
+	return nil
+}
+
 // validateInput checks whether the input string contains only valid characters.
 //
-// Valid characters are printable ASCII characters (codes 32–126) and newline
-// characters ('\n'). The function returns an error as soon as an invalid character
-// is encountered.
+// Valid input is UTF-8 text made of printable characters (letters, marks,
+// numbers, punctuation, symbols and spaces) and newline characters ('\n').
+// The function returns an error as soon as an invalid character is encountered.
 //
 // Parameters:
 //   - input: The string to validate.
@@ -125,12 +360,15 @@ func validateBannerCharacters(ch rune, banner map[rune][]string) ([]string, erro
 // Returns:
 //   - An error if invalid characters are found, nil otherwise.
 func validateInput(input string) error {
+	if !utf8.ValidString(input) {
+		return fmt.Errorf("invalid input: not valid UTF-8")
+	}
 	for _, ch := range input {
 		if ch == '\n' {
 			continue
 		}
-		if ch < 32 || ch > 126 {
-			return fmt.Errorf("invalid character %q (ASCII %d) - must be printable ASCII (32-126)", ch, ch)
+		if !unicode.IsGraphic(ch) {
+			return fmt.Errorf("invalid character %q (U+%04X) - must be a printable character", ch, ch)
 		}
 	}
 	return nil
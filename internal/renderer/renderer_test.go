@@ -4,13 +4,13 @@ import (
 	"strings"
 	"testing"
 
-	"ascii-art-color/internal/renderer"
+	"github.com/vxanthio/ascii-art-color/internal/renderer"
 )
 
 func TestEmptyInput(t *testing.T) {
 	input := ""
 	banner := map[rune][]string{}
-	output, err := renderer.ASCII(input, banner)
+	output, err := renderer.ASCII(input, eightRows(banner))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -33,7 +33,7 @@ A8
 	banner := map[rune][]string{
 		'A': {"A1", "A2", "A3", "A4", "A5", "A6", "A7", "A8"},
 	}
-	output, err := renderer.ASCII(input, banner)
+	output, err := renderer.ASCII(input, eightRows(banner))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -57,7 +57,7 @@ A8B8
 		'A': {"A1", "A2", "A3", "A4", "A5", "A6", "A7", "A8"},
 		'B': {"B1", "B2", "B3", "B4", "B5", "B6", "B7", "B8"},
 	}
-	output, err := renderer.ASCII(input, banner)
+	output, err := renderer.ASCII(input, eightRows(banner))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -81,7 +81,7 @@ A8  A8
 		'A': {"A1", "A2", "A3", "A4", "A5", "A6", "A7", "A8"},
 		' ': {"  ", "  ", "  ", "  ", "  ", "  ", "  ", "  "},
 	}
-	output, err := renderer.ASCII(input, banner)
+	output, err := renderer.ASCII(input, eightRows(banner))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -105,7 +105,7 @@ A81A8
 		'A': {"A1", "A2", "A3", "A4", "A5", "A6", "A7", "A8"},
 		'1': {"1", "1", "1", "1", "1", "1", "1", "1"},
 	}
-	output, err := renderer.ASCII(input, banner)
+	output, err := renderer.ASCII(input, eightRows(banner))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -125,7 +125,7 @@ func TestAllSpecialCharacters(t *testing.T) {
 		}
 	}
 
-	output, err := renderer.ASCII(specials, banner)
+	output, err := renderer.ASCII(specials, eightRows(banner))
 	if err != nil {
 		t.Fatalf("ASCII failed for special characters: %v", err)
 	}
@@ -165,7 +165,7 @@ B8
 		'A': {"A1", "A2", "A3", "A4", "A5", "A6", "A7", "A8"},
 		'B': {"B1", "B2", "B3", "B4", "B5", "B6", "B7", "B8"},
 	}
-	output, err := renderer.ASCII(input, banner)
+	output, err := renderer.ASCII(input, eightRows(banner))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -188,7 +188,7 @@ A8
 	banner := map[rune][]string{
 		'A': {"A1", "A2", "A3", "A4", "A5", "A6", "A7", "A8"},
 	}
-	output, err := renderer.ASCII(input, banner)
+	output, err := renderer.ASCII(input, eightRows(banner))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -221,7 +221,7 @@ B8
 		'A': {"A1", "A2", "A3", "A4", "A5", "A6", "A7", "A8"},
 		'B': {"B1", "B2", "B3", "B4", "B5", "B6", "B7", "B8"},
 	}
-	output, err := renderer.ASCII(input, banner)
+	output, err := renderer.ASCII(input, eightRows(banner))
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -235,7 +235,7 @@ func TestMissingCharacter(t *testing.T) {
 	banner := map[rune][]string{
 		'A': {"A1", "A2", "A3", "A4", "A5", "A6", "A7", "A8"},
 	}
-	output, err := renderer.ASCII(input, banner)
+	output, err := renderer.ASCII(input, eightRows(banner))
 	if err == nil {
 		t.Error("expected error for missing character 'B', got nil")
 	}
@@ -253,7 +253,7 @@ func TestCorruptedBanner(t *testing.T) {
 	banner := map[rune][]string{
 		'A': {"A1", "A2", "A3", "A4", "A6", "A7", "A8"},
 	}
-	output, err := renderer.ASCII(input, banner)
+	output, err := renderer.ASCII(input, eightRows(banner))
 	if err == nil {
 		t.Error("expected error for corrupted banner, got nil")
 	}
@@ -268,7 +268,7 @@ func TestInvalidCharacters(t *testing.T) {
 		'A': {"A1", "A2", "A3", "A4", "A5", "A6", "A7", "A8"},
 		'B': {"B1", "B2", "B3", "B4", "B5", "B6", "B7", "B8"},
 	}
-	output, err := renderer.ASCII(input, banner)
+	output, err := renderer.ASCII(input, eightRows(banner))
 	if err == nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -291,7 +291,7 @@ func TestCompleteASCIIRange(t *testing.T) {
 		input.WriteRune(ch)
 	}
 
-	output, err := renderer.ASCII(input.String(), banner)
+	output, err := renderer.ASCII(input.String(), eightRows(banner))
 	if err != nil {
 		t.Fatalf("ASCII failed for ASCII range: %v", err)
 	}
@@ -307,3 +307,84 @@ func TestCompleteASCIIRange(t *testing.T) {
 		}
 	}
 }
+
+func TestASCIIWithOptions_MultiByteRunes(t *testing.T) {
+	banner := map[rune][]string{
+		'C': {"C1", "C2", "C3", "C4", "C5", "C6", "C7", "C8"},
+		'é': {"é1", "é2", "é3", "é4", "é5", "é6", "é7", "é8"},
+		'€': {"€€1", "€€2", "€€3", "€€4", "€€5", "€€6", "€€7", "€€8"},
+	}
+
+	output, err := renderer.ASCII("Cé€", eightRows(banner))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Split(output, "\n")[0]; got != "C1é1€€1" {
+		t.Errorf("first row = %q, want %q", got, "C1é1€€1")
+	}
+
+	widths, err := renderer.Widths("é€C", eightRows(banner), renderer.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(widths) != 3 || widths[0] != 2 || widths[1] != 3 || widths[2] != 2 {
+		t.Errorf("widths = %v, want [2 3 2]", widths)
+	}
+}
+
+func TestASCIIWithOptions_Fallback(t *testing.T) {
+	banner := map[rune][]string{
+		'A': {"A1", "A2", "A3", "A4", "A5", "A6", "A7", "A8"},
+		'?': {"?1", "?2", "?3", "?4", "?5", "?6", "?7", "?8"},
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		opts    renderer.Options
+		want    string
+		wantErr bool
+	}{
+		{"missing without fallback", "Añ", renderer.Options{}, "", true},
+		{"missing with fallback", "Añ", renderer.Options{Fallback: '?'}, "A1?1", false},
+		{"fallback with fitting", "ñA", renderer.Options{Layout: renderer.Fitting, Fallback: '?'}, "?1A1", false},
+		{"defined character ignores fallback", "A", renderer.Options{Fallback: '?'}, "A1", false},
+		{"fallback not in banner", "Añ", renderer.Options{Fallback: '#'}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := renderer.ASCIIWithOptions(tt.input, eightRows(banner), tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if got := strings.Split(output, "\n")[0]; got != tt.want {
+				t.Errorf("first row = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvalidUnicodeInput(t *testing.T) {
+	banner := map[rune][]string{
+		'A': {"A1", "A2", "A3", "A4", "A5", "A6", "A7", "A8"},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"invalid UTF-8", "A\xffA"},
+		{"control character", "A\u0007A"},
+		{"unicode control character", "A\u0085A"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := renderer.Options{Fallback: 'A'}
+			if _, err := renderer.ASCIIWithOptions(tt.input, eightRows(banner), opts); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package renderer
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/vxanthio/ascii-art-color/internal/parser"
+)
+
+// WrapLine splits a single line of text into the pieces that fit within
+// opts.Width columns once rendered.
+//
+// The line is broken at spaces, and the spaces at each break are dropped.
+// Widths are measured on the rendered glyphs, so fitting and smushing are taken
+// into account. A word that is wider than the limit on its own is hard-broken
+// between characters; a single glyph wider than the limit is kept on its own
+// piece. With opts.Width zero or negative the line is returned unchanged.
+//
+// Parameters:
+//   - line: A single line of text without newline characters.
+//   - banner: The banner glyphs and their height.
+//   - opts: The layout options, including the column limit.
+//
+// Returns:
+//   - The pieces of line, in order, each rendered on its own block of rows.
+//   - An error if input validation or banner validation fails.
+func WrapLine(line string, banner parser.Banner, opts Options) ([]string, error) {
+	if err := validateInput(line); err != nil {
+		return nil, err
+	}
+	if opts.Width <= 0 || line == "" {
+		return []string{line}, nil
+	}
+	if err := validateBanner(banner); err != nil {
+		return nil, err
+	}
+
+	w := wrapper{banner: banner, opts: opts}
+	for _, word := range strings.Split(line, " ") {
+		if err := w.add(word); err != nil {
+			return nil, err
+		}
+	}
+	if w.started {
+		w.pieces = append(w.pieces, w.current)
+	}
+	if len(w.pieces) == 0 {
+		return []string{""}, nil
+	}
+	return w.pieces, nil
+}
+
+// wrapper accumulates the pieces of a line being wrapped.
+type wrapper struct {
+	banner  parser.Banner
+	opts    Options
+	pieces  []string
+	current string
+	// started reports whether current holds the beginning of a piece; an
+	// empty current piece can still be started by a leading space.
+	started bool
+	// broken reports whether the last piece ended at a break, in which case
+	// the spaces that follow are dropped.
+	broken bool
+}
+
+// add appends the next space-separated word, starting a new piece when the
+// word does not fit on the current one.
+func (w *wrapper) add(word string) error {
+	if w.broken && word == "" {
+		return nil
+	}
+	w.broken = false
+
+	candidate := word
+	if w.started {
+		candidate = w.current + " " + word
+	}
+	fits, err := w.fits(candidate)
+	if err != nil {
+		return err
+	}
+	if fits {
+		w.current, w.started = candidate, true
+		return nil
+	}
+
+	if w.started {
+		w.pieces = append(w.pieces, strings.TrimRight(w.current, " "))
+		w.current, w.started, w.broken = "", false, true
+		if word == "" {
+			return nil
+		}
+	}
+	return w.breakWord(word)
+}
+
+// breakWord starts a new piece with word, hard-breaking it between characters
+// wherever it does not fit.
+func (w *wrapper) breakWord(word string) error {
+	piece := ""
+	for _, ch := range word {
+		candidate := piece + string(ch)
+		fits, err := w.fits(candidate)
+		if err != nil {
+			return err
+		}
+		if !fits && piece != "" {
+			w.pieces = append(w.pieces, piece)
+			candidate = string(ch)
+		}
+		piece = candidate
+	}
+	w.current, w.started = piece, true
+	return nil
+}
+
+// fits reports whether text renders within the column limit.
+func (w *wrapper) fits(text string) (bool, error) {
+	if text == "" {
+		return true, nil
+	}
+	rows, _, err := renderLine(text, w.banner, w.opts)
+	if err != nil {
+		return false, err
+	}
+	return utf8.RuneCountInString(rows[0]) <= w.opts.Width, nil
+}
@@ -0,0 +1,113 @@
+package renderer_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vxanthio/ascii-art-color/internal/parser"
+	"github.com/vxanthio/ascii-art-color/internal/renderer"
+)
+
+// wrapBanner returns a banner whose letters are two columns wide and whose
+// space is one column wide.
+func wrapBanner() parser.Banner {
+	glyphs := map[rune][]string{' ': glyph(" ")}
+	for ch := 'a'; ch <= 'z'; ch++ {
+		glyphs[ch] = glyph(string(ch) + "|")
+	}
+	glyphs['é'] = glyph("é|")
+	return eightRows(glyphs)
+}
+
+func TestWrapLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		width int
+		want  []string
+	}{
+		{"no limit", "ab cd", 0, []string{"ab cd"}},
+		{"fits", "ab cd", 9, []string{"ab cd"}},
+		{"breaks at space", "ab cd", 8, []string{"ab", "cd"}},
+		{"several words per piece", "ab cd ef gh", 9, []string{"ab cd", "ef gh"}},
+		{"drops spaces at breaks", "ab   cd", 5, []string{"ab", "cd"}},
+		{"keeps inner spaces", "a  b", 6, []string{"a  b"}},
+		{"hard-breaks long word", "abcdefg", 6, []string{"abc", "def", "g"}},
+		{"hard-breaks after short word", "ab cdefgh", 6, []string{"ab", "cde", "fgh"}},
+		{"glyph wider than limit", "ab", 1, []string{"a", "b"}},
+		{"multi-byte characters", "éé éé", 4, []string{"éé", "éé"}},
+		{"empty line", "", 4, []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderer.WrapLine(tt.line, wrapBanner(), renderer.Options{Width: tt.width})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(got, "/") != strings.Join(tt.want, "/") {
+				t.Errorf("WrapLine(%q, %d) = %q, want %q", tt.line, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapLine_MeasuresLayout(t *testing.T) {
+	banner := map[rune][]string{
+		'A': glyph("A   "),
+		' ': glyph(" "),
+	}
+
+	full, err := renderer.WrapLine("AA AA", eightRows(banner), renderer.Options{Width: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(full) != 2 {
+		t.Errorf("full width: got %q, want two pieces", full)
+	}
+
+	fitted, err := renderer.WrapLine("AA AA", eightRows(banner), renderer.Options{Width: 10, Layout: renderer.Fitting})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fitted) != 1 {
+		t.Errorf("fitting: got %q, want one piece", fitted)
+	}
+}
+
+func TestWrapLine_Errors(t *testing.T) {
+	opts := renderer.Options{Width: 4}
+	if _, err := renderer.WrapLine("a\tb", wrapBanner(), opts); err == nil {
+		t.Error("expected error for invalid character, got nil")
+	}
+	if _, err := renderer.WrapLine("aB", wrapBanner(), opts); err == nil {
+		t.Error("expected error for missing character, got nil")
+	}
+}
+
+func TestASCIIWithOptions_Width(t *testing.T) {
+	output, err := renderer.ASCIIWithOptions("ab cd\n\nef", wrapBanner(), renderer.Options{Width: 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	blocks := strings.Split(output, "\n")
+	want := []string{"a|b|", "c|d|", "", "e|f|"}
+	for i, row := range want {
+		block := i * 8
+		if i == 3 {
+			block = 17
+		}
+		if blocks[block] != row {
+			t.Errorf("block %d first row = %q, want %q", i, blocks[block], row)
+		}
+	}
+	if got := strings.Count(output, "\n"); got != 25 {
+		t.Errorf("expected 25 rows, got %d", got)
+	}
+	for _, row := range blocks {
+		if len(row) > 4 {
+			t.Errorf("row %q wider than the limit", row)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package terminal
+
+import "fmt"
+
+// Escape sequences that control the cursor of a terminal.
+const (
+	// HideCursor makes the cursor invisible.
+	HideCursor = "\033[?25l"
+	// ShowCursor makes the cursor visible again.
+	ShowCursor = "\033[?25h"
+	// ClearToEnd erases the line from the cursor to its end.
+	ClearToEnd = "\033[K"
+)
+
+// CursorUp returns the escape sequence that moves the cursor up n lines, to
+// the start of the line.
+//
+// Parameters:
+//   - n: The number of lines.
+//
+// Returns:
+//   - The escape sequence; empty when n is not positive.
+func CursorUp(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("\033[%dF", n)
+}
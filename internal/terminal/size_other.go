@@ -0,0 +1,25 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package terminal
+
+import "os"
+
+// isTerminal reports whether f is a character device, the closest check to
+// a terminal without the TIOCGWINSZ ioctl.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// windowWidth reports a terminal of unknown width on platforms without the
+// TIOCGWINSZ ioctl, so that Width falls back to COLUMNS.
+//
+// Parameters:
+//   - f: The file to inspect.
+//
+// Returns:
+//   - Zero columns.
+//   - false if f is not a character device.
+func windowWidth(f *os.File) (int, bool) {
+	return 0, isTerminal(f)
+}
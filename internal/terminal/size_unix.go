@@ -0,0 +1,46 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package terminal
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// winsize mirrors the struct filled in by the TIOCGWINSZ ioctl.
+type winsize struct {
+	rows    uint16
+	columns uint16
+	xPixels uint16
+	yPixels uint16
+}
+
+// isTerminal reports whether f is a terminal: whether it answers the
+// TIOCGWINSZ ioctl.
+func isTerminal(f *os.File) bool {
+	_, ok := windowWidth(f)
+	return ok
+}
+
+// windowWidth queries the window size of the terminal attached to f.
+//
+// Parameters:
+//   - f: The file to inspect.
+//
+// Returns:
+//   - The number of columns, or zero if the terminal reports none.
+//   - false if f is not a terminal.
+func windowWidth(f *os.File) (int, bool) {
+	var ws winsize
+	_, _, errno := syscall.Syscall(
+		syscall.SYS_IOCTL,
+		f.Fd(),
+		uintptr(syscall.TIOCGWINSZ),
+		uintptr(unsafe.Pointer(&ws)), // #nosec G103 -- required by the ioctl interface
+	)
+	if errno != 0 {
+		return 0, false
+	}
+	return int(ws.columns), true
+}
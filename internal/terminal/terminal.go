@@ -0,0 +1,63 @@
+// Package terminal provides information about the terminal the program writes
+// to.
+//
+// The package uses only the standard library. On Unix systems the size of a
+// terminal is read with the TIOCGWINSZ ioctl; elsewhere, where any character
+// device counts as a terminal, and when the ioctl reports no size, the COLUMNS
+// environment variable is used.
+//
+// Responsibilities of this package:
+//   - Detect the width of the terminal attached to a file
+//   - Detect whether a file is a terminal
+//   - Provide the escape sequences that move, hide and show the cursor
+package terminal
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Width returns the number of columns of the terminal attached to f.
+//
+// Parameters:
+//   - f: The file to inspect, usually os.Stdout.
+//
+// Returns:
+//   - The number of columns.
+//   - false if f is not a terminal or its width cannot be determined.
+func Width(f *os.File) (int, bool) {
+	columns, isTerminal := windowWidth(f)
+	if !isTerminal {
+		return 0, false
+	}
+	if columns > 0 {
+		return columns, true
+	}
+	return columnsFromEnv()
+}
+
+// IsTerminal reports whether f is attached to a terminal.
+//
+// Parameters:
+//   - f: The file to inspect, usually os.Stdout.
+//
+// Returns:
+//   - true if f is a terminal, false otherwise.
+func IsTerminal(f *os.File) bool {
+	return f != nil && isTerminal(f)
+}
+
+// columnsFromEnv reads the terminal width from the COLUMNS environment
+// variable.
+//
+// Returns:
+//   - The number of columns.
+//   - false if COLUMNS is unset or not a positive integer.
+func columnsFromEnv() (int, bool) {
+	columns, err := strconv.Atoi(strings.TrimSpace(os.Getenv("COLUMNS")))
+	if err != nil || columns <= 0 {
+		return 0, false
+	}
+	return columns, true
+}
@@ -0,0 +1,78 @@
+package terminal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWidth_RegularFileIsNotATerminal(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	defer f.Close()
+
+	t.Setenv("COLUMNS", "120")
+	if width, ok := Width(f); ok {
+		t.Errorf("Width(regular file) = %d, true; want false", width)
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	defer f.Close()
+
+	if IsTerminal(f) {
+		t.Error("IsTerminal(regular file) = true, want false")
+	}
+	if IsTerminal(nil) {
+		t.Error("IsTerminal(nil) = true, want false")
+	}
+}
+
+func TestColumnsFromEnv(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   int
+		wantOK bool
+	}{
+		{"unset", "", 0, false},
+		{"valid", "132", 132, true},
+		{"surrounding spaces", " 80 ", 80, true},
+		{"zero", "0", 0, false},
+		{"negative", "-5", 0, false},
+		{"not a number", "wide", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("COLUMNS", tt.value)
+			got, ok := columnsFromEnv()
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("columnsFromEnv() = %d, %t; want %d, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCursorUp(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{3, "\033[3F"},
+		{1, "\033[1F"},
+		{0, ""},
+		{-2, ""},
+	}
+	for _, tt := range tests {
+		if got := CursorUp(tt.n); got != tt.want {
+			t.Errorf("CursorUp(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
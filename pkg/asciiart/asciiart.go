@@ -0,0 +1,263 @@
+// Package asciiart renders text as ASCII-art banners, optionally colored with
+// ANSI escape sequences.
+//
+// It is the public, importable counterpart of the ascii-art command, imported
+// as github.com/vxanthio/ascii-art-color/pkg/asciiart. A Renderer is built
+// once from Options and can then render any number of texts:
+//
+//	r, err := asciiart.New(asciiart.Options{
+//		Font:   "shadow",
+//		Colors: []asciiart.ColorRule{{Color: "orange", Substring: "GuYs"}},
+//		Align:  asciiart.AlignCenter,
+//		Width:  80,
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	art, err := r.Render("HeY GuYs")
+//
+// The standard, shadow and thinkertoy fonts are embedded in the package, so
+// programs using it need no data files. Other fonts, in the classic banner
+// format or the FIGlet .flf format, can be loaded from disk with
+// Options.FontFile.
+//
+// # Compatibility
+//
+// Releases are the semantic version tags of the repository (vMAJOR.MINOR.PATCH).
+// Within a major version:
+//   - Exported identifiers are not removed or renamed, and function and method
+//     signatures do not change.
+//   - New fields may be added to Options and ColorRule, and new constants to
+//     Align and Layout; their zero values keep the previous behavior.
+//   - Output for the same text and Options stays the same, except for fixes to
+//     output that contradicts this documentation.
+//
+// Error messages are not part of the API and may change at any time.
+package asciiart
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vxanthio/ascii-art-color/internal/color"
+	"github.com/vxanthio/ascii-art-color/internal/coloring"
+	"github.com/vxanthio/ascii-art-color/internal/parser"
+	"github.com/vxanthio/ascii-art-color/internal/renderer"
+)
+
+// DefaultFont is the embedded font used when Options names none.
+const DefaultFont = "standard"
+
+// Align selects how rendered blocks are positioned within the output width.
+type Align int
+
+const (
+	// AlignLeft leaves blocks against the left edge, unpadded.
+	AlignLeft Align = iota
+	// AlignCenter pads blocks equally on both sides.
+	AlignCenter
+	// AlignRight pads blocks on the left.
+	AlignRight
+	// AlignJustify spreads the extra columns across the spaces between words.
+	AlignJustify
+)
+
+// Layout selects how horizontally adjacent glyphs are joined.
+type Layout int
+
+const (
+	// LayoutDefault uses the layout declared by a FIGlet font, and full width
+	// for every other font.
+	LayoutDefault Layout = iota
+	// LayoutFull places glyphs side by side at their full width.
+	LayoutFull
+	// LayoutFitting moves each glyph left until it touches the previous one.
+	LayoutFitting
+	// LayoutSmushing overlaps touching glyphs where the FIGlet smushing rules
+	// allow it.
+	LayoutSmushing
+	// LayoutUniversal overlaps touching glyphs by one column, keeping the
+	// character of the later glyph.
+	LayoutUniversal
+)
+
+// ColorRule styles the occurrences of a substring.
+//
+// When rules overlap, the rule listed later in Options.Colors wins, with its
+// whole style: colors and attributes are not mixed between rules.
+type ColorRule struct {
+	// Color is a color specification: a CSS name such as "red", a hex value
+	// such as "#ff0000" or "#f00", "rgb(255,0,0)", "hsl(0,100%,50%)",
+	// "hsv(0,100%,100%)", or an ANSI index such as "ansi:196". It may be
+	// empty when Background or Attributes is set, to keep the terminal's text
+	// color.
+	Color string
+	// Substring is the text to color. Empty colors the whole text.
+	Substring string
+	// Background is the color behind the text, in the same forms as Color.
+	// Empty keeps the terminal's background.
+	Background string
+	// Attributes lists text attributes separated by commas: bold, dim,
+	// italic, underline, blink, or reverse. Empty sets none.
+	Attributes string
+}
+
+// Options configures a Renderer. The zero value renders uncolored text with
+// the standard font, left aligned, without wrapping.
+type Options struct {
+	// Font names an embedded font: standard, shadow, or thinkertoy. Empty
+	// means DefaultFont. It is ignored when FontFile is set.
+	Font string
+	// FontFile is the path of a font file on disk. Files with the .flf
+	// extension are read as FIGlet fonts, all others as classic banner files.
+	FontFile string
+	// Layout selects how adjacent glyphs are joined.
+	Layout Layout
+	// Colors lists the color rules applied to the rendered text.
+	Colors []ColorRule
+	// Width is the maximum number of columns of a rendered row. Longer lines
+	// are wrapped at spaces. Zero disables wrapping.
+	Width int
+	// Align positions each rendered block within Width columns, or within the
+	// widest block of the rendered text when Width is zero.
+	Align Align
+	// Fallback is drawn in place of characters the font does not define.
+	// Zero makes such characters an error.
+	Fallback rune
+}
+
+// Renderer renders text with a fixed font, layout and color configuration.
+// A Renderer is safe for concurrent use.
+type Renderer struct {
+	banner parser.Banner
+	opts   renderer.Options
+	rules  []coloring.Rule
+}
+
+// New builds a Renderer from opts.
+//
+// Parameters:
+//   - opts: The font, layout, color, width and alignment settings.
+//
+// Returns:
+//   - The Renderer.
+//   - An error if the font cannot be loaded or an option is invalid.
+func New(opts Options) (*Renderer, error) {
+	if opts.Width < 0 {
+		return nil, fmt.Errorf("invalid width %d: must not be negative", opts.Width)
+	}
+	align, ok := alignments[opts.Align]
+	if !ok {
+		return nil, fmt.Errorf("invalid alignment %d", opts.Align)
+	}
+
+	banner, renderOpts, err := loadFont(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyLayout(&renderOpts, opts.Layout); err != nil {
+		return nil, err
+	}
+	renderOpts.Width = opts.Width
+	renderOpts.Align = align
+	renderOpts.Fallback = opts.Fallback
+
+	rules := make([]coloring.Rule, 0, len(opts.Colors))
+	for _, rule := range opts.Colors {
+		style, err := ruleStyle(rule)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, coloring.Rule{Substring: rule.Substring, Style: style})
+	}
+
+	return &Renderer{banner: banner, opts: renderOpts, rules: rules}, nil
+}
+
+// ruleStyle parses the colors and attributes of a ColorRule.
+func ruleStyle(rule ColorRule) (color.Style, error) {
+	var style color.Style
+	if rule.Color != "" || (rule.Background == "" && rule.Attributes == "") {
+		rgb, err := color.Parse(rule.Color)
+		if err != nil {
+			return style, err
+		}
+		style.Foreground = &rgb
+	}
+	if rule.Background != "" {
+		rgb, err := color.Parse(rule.Background)
+		if err != nil {
+			return style, fmt.Errorf("invalid background: %w", err)
+		}
+		style.Background = &rgb
+	}
+	if rule.Attributes != "" {
+		attrs, err := color.ParseAttrs(rule.Attributes)
+		if err != nil {
+			return style, err
+		}
+		style.Attrs = attrs
+	}
+	return style, nil
+}
+
+var alignments = map[Align]renderer.Align{
+	AlignLeft:    renderer.AlignLeft,
+	AlignCenter:  renderer.AlignCenter,
+	AlignRight:   renderer.AlignRight,
+	AlignJustify: renderer.AlignJustify,
+}
+
+var layouts = map[Layout]renderer.Layout{
+	LayoutFull:      renderer.FullWidth,
+	LayoutFitting:   renderer.Fitting,
+	LayoutSmushing:  renderer.Smushing,
+	LayoutUniversal: renderer.UniversalSmushing,
+}
+
+// loadFont loads the font selected by opts together with the renderer options
+// it declares.
+func loadFont(opts Options) (parser.Banner, renderer.Options, error) {
+	if opts.FontFile != "" {
+		fsys, name := os.DirFS(filepath.Dir(opts.FontFile)), filepath.Base(opts.FontFile)
+		if !strings.EqualFold(filepath.Ext(name), ".flf") {
+			banner, err := parser.LoadBanner(fsys, name)
+			return banner, renderer.Options{Rules: renderer.AllSmushRules}, err
+		}
+
+		font, err := parser.LoadFIGletFont(fsys, name)
+		if err != nil {
+			return parser.Banner{}, renderer.Options{}, err
+		}
+		banner := font.RawBanner()
+		renderOpts := renderer.Options{Hardblank: font.Header.Hardblank}
+		renderOpts.Layout, renderOpts.Rules = renderer.FIGletLayout(font.Header.FullLayout)
+		if renderOpts.Rules == 0 {
+			renderOpts.Rules = renderer.AllSmushRules
+		}
+		return banner, renderOpts, nil
+	}
+
+	name := opts.Font
+	if name == "" {
+		name = DefaultFont
+	}
+	banner, err := loadEmbeddedFont(name)
+	return banner, renderer.Options{Rules: renderer.AllSmushRules}, err
+}
+
+// applyLayout overrides the layout of renderOpts unless layout is
+// LayoutDefault.
+func applyLayout(renderOpts *renderer.Options, layout Layout) error {
+	if layout == LayoutDefault {
+		return nil
+	}
+	mapped, ok := layouts[layout]
+	if !ok {
+		return fmt.Errorf("invalid layout %d", layout)
+	}
+	renderOpts.Layout = mapped
+	return nil
+}
@@ -0,0 +1,249 @@
+package asciiart_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/vxanthio/ascii-art-color/internal/parser"
+	"github.com/vxanthio/ascii-art-color/internal/renderer"
+	"github.com/vxanthio/ascii-art-color/pkg/asciiart"
+)
+
+const (
+	red   = "\033[38;2;255;0;0m"
+	green = "\033[38;2;0;255;0m"
+	reset = "\033[0m"
+)
+
+func mustNew(t *testing.T, opts asciiart.Options) *asciiart.Renderer {
+	t.Helper()
+	r, err := asciiart.New(opts)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return r
+}
+
+func TestRender_MatchesRenderer(t *testing.T) {
+	banner, err := parser.LoadBanner(os.DirFS("../../internal/banners"), "standard.txt")
+	if err != nil {
+		t.Fatalf("LoadBanner failed: %v", err)
+	}
+	r := mustNew(t, asciiart.Options{})
+
+	for _, text := range []string{"", "Hello", "Hello\nWorld", "a\n\nb", "\n", "Hi\n"} {
+		want, err := renderer.ASCII(text, banner)
+		if err != nil {
+			t.Fatalf("ASCII(%q) failed: %v", text, err)
+		}
+		got, err := r.Render(text)
+		if err != nil {
+			t.Fatalf("Render(%q) failed: %v", text, err)
+		}
+		if got != want {
+			t.Errorf("Render(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestNew_Fonts(t *testing.T) {
+	for _, name := range asciiart.Fonts() {
+		t.Run(name, func(t *testing.T) {
+			out, err := mustNew(t, asciiart.Options{Font: name}).Render("Go")
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			if strings.Count(out, "\n") != 8 {
+				t.Errorf("expected 8 rows, got %d", strings.Count(out, "\n"))
+			}
+		})
+	}
+
+	if got := strings.Join(asciiart.Fonts(), ","); got != "shadow,standard,thinkertoy" {
+		t.Errorf("Fonts() = %q", got)
+	}
+}
+
+func TestNew_FontFile(t *testing.T) {
+	out, err := mustNew(t, asciiart.Options{FontFile: "../../cmd/ascii-art/testdata/boxed.flf"}).Render("Hé")
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if out != "+-++'+\n|H||e|\n+-++-+\n" {
+		t.Errorf("got %q", out)
+	}
+
+	out, err = mustNew(t, asciiart.Options{FontFile: "../../internal/banners/shadow.txt"}).Render("A")
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if strings.Count(out, "\n") != 8 {
+		t.Errorf("expected 8 rows from classic banner file, got %q", out)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts asciiart.Options
+	}{
+		{"unknown font", asciiart.Options{Font: "gothic"}},
+		{"missing font file", asciiart.Options{FontFile: "testdata/nope.flf"}},
+		{"missing banner file", asciiart.Options{FontFile: "testdata/nope.txt"}},
+		{"invalid color", asciiart.Options{Colors: []asciiart.ColorRule{{Color: "notacolor"}}}},
+		{"empty color", asciiart.Options{Colors: []asciiart.ColorRule{{Substring: "a"}}}},
+		{"invalid background", asciiart.Options{Colors: []asciiart.ColorRule{{Background: "notacolor"}}}},
+		{"invalid attribute", asciiart.Options{Colors: []asciiart.ColorRule{{Color: "red", Attributes: "shiny"}}}},
+		{"negative width", asciiart.Options{Width: -1}},
+		{"invalid alignment", asciiart.Options{Align: asciiart.Align(42)}},
+		{"invalid layout", asciiart.Options{Layout: asciiart.Layout(42)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := asciiart.New(tt.opts); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestRender_Colors(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []asciiart.ColorRule
+		text  string
+		want  string
+	}{
+		{
+			name:  "whole text",
+			rules: []asciiart.ColorRule{{Color: "red"}},
+			text:  "Hi",
+			want:  red + "| |  | | | | " + reset,
+		},
+		{
+			name:  "substring",
+			rules: []asciiart.ColorRule{{Color: "red", Substring: "i"}},
+			text:  "Hi",
+			want:  "| |  | | " + red + "| | " + reset,
+		},
+		{
+			name:  "later rule wins",
+			rules: []asciiart.ColorRule{{Color: "red"}, {Color: "#00ff00", Substring: "i"}},
+			text:  "Hi",
+			want:  red + "| |  | | " + reset + green + "| | " + reset,
+		},
+		{
+			name:  "background and attributes",
+			rules: []asciiart.ColorRule{{Color: "red", Background: "blue", Attributes: "bold,underline", Substring: "i"}},
+			text:  "Hi",
+			want:  "| |  | | " + "\033[1;4;38;2;255;0;0;48;2;0;0;255m" + "| | " + reset,
+		},
+		{
+			name:  "background only",
+			rules: []asciiart.ColorRule{{Background: "blue"}},
+			text:  "Hi",
+			want:  "\033[48;2;0;0;255m" + "| |  | | | | " + reset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := mustNew(t, asciiart.Options{Colors: tt.rules}).Render(tt.text)
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			if row := strings.Split(out, "\n")[4]; row != tt.want {
+				t.Errorf("row 4 = %q, want %q", row, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender_WidthAndAlign(t *testing.T) {
+	out, err := mustNew(t, asciiart.Options{Width: 40, Align: asciiart.AlignRight}).Render("Hello World")
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	rows := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(rows) != 24 {
+		t.Fatalf("expected 24 rows, got %d", len(rows))
+	}
+	for _, row := range rows {
+		if len(row) != 40 {
+			t.Errorf("row %q is %d columns, want 40", row, len(row))
+		}
+	}
+
+	out, err = mustNew(t, asciiart.Options{Align: asciiart.AlignCenter, Colors: []asciiart.ColorRule{{Color: "red"}}}).Render("Hi\ni")
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if row := strings.Split(out, "\n")[9]; row != "    "+red+"(_) "+reset+"     " {
+		t.Errorf("centered colored row = %q", row)
+	}
+}
+
+func TestRender_Layout(t *testing.T) {
+	out, err := mustNew(t, asciiart.Options{Layout: asciiart.LayoutSmushing}).Render("HH")
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !strings.HasPrefix(out, " _    _ _    _  \n") {
+		t.Errorf("unexpected smushed output:\n%s", out)
+	}
+}
+
+func TestRender_Fallback(t *testing.T) {
+	r := mustNew(t, asciiart.Options{})
+	if _, err := r.Render("Café"); err == nil {
+		t.Error("expected error for missing character, got nil")
+	}
+
+	r = mustNew(t, asciiart.Options{Fallback: '?'})
+	withFallback, err := r.Render("Café")
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	plain, _ := r.Render("Caf?")
+	if withFallback != plain {
+		t.Errorf("fallback output differs from rendering the fallback character")
+	}
+}
+
+// failingWriter fails every write after the first n.
+type failingWriter struct {
+	n int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.n == 0 {
+		return 0, errors.New("disk full")
+	}
+	w.n--
+	return len(p), nil
+}
+
+func TestRenderTo(t *testing.T) {
+	var b strings.Builder
+	r := mustNew(t, asciiart.Options{})
+	if err := r.RenderTo(&b, "A\nB"); err != nil {
+		t.Fatalf("RenderTo failed: %v", err)
+	}
+	want, _ := r.Render("A\nB")
+	if b.String() != want {
+		t.Errorf("RenderTo output differs from Render")
+	}
+
+	if err := r.RenderTo(&failingWriter{n: 1}, "A\nB"); err == nil {
+		t.Error("expected write error, got nil")
+	}
+	if err := r.RenderTo(&failingWriter{}, "A\n\nB"); err == nil {
+		t.Error("expected write error, got nil")
+	}
+	if err := r.RenderTo(&b, "A\tB"); err == nil {
+		t.Error("expected error for invalid character, got nil")
+	}
+}
@@ -0,0 +1,68 @@
+package asciiart_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vxanthio/ascii-art-color/pkg/asciiart"
+)
+
+func ExampleRenderer_Render() {
+	r, err := asciiart.New(asciiart.Options{Font: "standard"})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	art, err := r.Render("Hi")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	// Trailing spaces are trimmed only to keep the example output readable.
+	for _, row := range strings.Split(strings.TrimSuffix(art, "\n"), "\n") {
+		fmt.Println(strings.TrimRight(row, " "))
+	}
+	// Output:
+	//  _    _   _
+	// | |  | | (_)
+	// | |__| |  _
+	// |  __  | | |
+	// | |  | | | |
+	// |_|  |_| |_|
+}
+
+func ExampleRenderer_RenderTo() {
+	r, err := asciiart.New(asciiart.Options{Font: "thinkertoy", Align: asciiart.AlignRight})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	var b strings.Builder
+	if err := r.RenderTo(&b, "1\n11"); err != nil {
+		fmt.Println(err)
+		return
+	}
+	// A bar marks the right edge of every row, which keeps its trailing spaces.
+	for _, row := range strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n") {
+		fmt.Println(row + "|")
+	}
+	// Output:
+	//             |
+	//         0   |
+	//        /|   |
+	//       o |   |
+	//         |   |
+	//       o-o-o |
+	//             |
+	//             |
+	//             |
+	//   0     0   |
+	//  /|    /|   |
+	// o |   o |   |
+	//   |     |   |
+	// o-o-o o-o-o |
+	//             |
+	//             |
+}
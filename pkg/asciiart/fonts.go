@@ -0,0 +1,37 @@
+package asciiart
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/vxanthio/ascii-art-color/internal/banners"
+	"github.com/vxanthio/ascii-art-color/internal/parser"
+)
+
+// Fonts returns the names of the embedded fonts in alphabetical order.
+//
+// The fonts are the built-in banners of the ascii-art command, embedded into
+// every program using the package.
+//
+// Returns:
+//   - The font names accepted by Options.Font.
+func Fonts() []string {
+	names := slices.Clone(banners.Names)
+	slices.Sort(names)
+	return names
+}
+
+// loadEmbeddedFont parses the embedded font with the given name.
+//
+// Parameters:
+//   - name: The font name.
+//
+// Returns:
+//   - The parsed font.
+//   - An error if no embedded font has that name.
+func loadEmbeddedFont(name string) (parser.Banner, error) {
+	if !slices.Contains(banners.Names, name) {
+		return parser.Banner{}, fmt.Errorf("unknown font %q: valid fonts are %v", name, Fonts())
+	}
+	return parser.LoadBanner(banners.FS, name+".txt")
+}
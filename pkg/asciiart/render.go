@@ -0,0 +1,138 @@
+package asciiart
+
+import (
+	"io"
+	"strings"
+
+	"github.com/vxanthio/ascii-art-color/internal/coloring"
+	"github.com/vxanthio/ascii-art-color/internal/renderer"
+)
+
+// block is one rendered line of text, colored and ready to be aligned.
+type block struct {
+	line   string
+	rows   []string
+	widths []int
+}
+
+// Render converts text to ASCII art.
+//
+// Each line of text, separated by '\n', is rendered as its own block of rows,
+// and every row ends with a newline. Empty lines produce empty output lines.
+//
+// Parameters:
+//   - text: The text to render.
+//
+// Returns:
+//   - The rendered ASCII art.
+//   - An error if the text contains characters the font cannot draw.
+func (r *Renderer) Render(text string) (string, error) {
+	var b strings.Builder
+	if err := r.RenderTo(&b, text); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// RenderTo converts text to ASCII art and writes it to w.
+//
+// Output is written one block at a time as each line is rendered. When the
+// Renderer aligns blocks without a fixed Width, the widest block must be known
+// first, so the output is written once every line has been rendered.
+//
+// Parameters:
+//   - w: The destination of the rendered rows.
+//   - text: The text to render.
+//
+// Returns:
+//   - An error if the text contains characters the font cannot draw, or if
+//     writing to w fails.
+func (r *Renderer) RenderTo(w io.Writer, text string) error {
+	lines := strings.Split(text, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	buffered := r.opts.Align != renderer.AlignLeft && r.opts.Width <= 0
+	var pending []block
+	target := r.opts.Width
+
+	for _, line := range lines {
+		pieces, err := renderer.WrapLine(line, r.banner, r.opts)
+		if err != nil {
+			return err
+		}
+		for _, piece := range pieces {
+			b, err := r.renderBlock(piece)
+			if err != nil {
+				return err
+			}
+			if !buffered {
+				if err := r.writeBlock(w, b, target); err != nil {
+					return err
+				}
+				continue
+			}
+			pending = append(pending, b)
+			if len(b.rows) > 0 {
+				target = max(target, renderer.VisibleWidth(b.rows[0]))
+			}
+		}
+	}
+
+	for _, b := range pending {
+		if err := r.writeBlock(w, b, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderBlock renders and colors a single line of text that already fits the
+// output width.
+func (r *Renderer) renderBlock(line string) (block, error) {
+	if line == "" {
+		return block{}, nil
+	}
+
+	opts := r.opts
+	opts.Width, opts.Align = 0, renderer.AlignLeft
+	art, err := renderer.ASCIIWithOptions(line, r.banner, opts)
+	if err != nil {
+		return block{}, err
+	}
+	rows := strings.Split(strings.TrimSuffix(art, "\n"), "\n")
+	if len(r.rules) == 0 {
+		return block{line: line, rows: rows}, nil
+	}
+
+	widths, err := renderer.Widths(line, r.banner, opts)
+	if err != nil {
+		return block{}, err
+	}
+	return block{line: line, rows: coloring.ApplyRules(rows, line, r.rules, widths), widths: widths}, nil
+}
+
+// writeBlock aligns a block within target columns and writes its rows to w.
+func (r *Renderer) writeBlock(w io.Writer, b block, target int) error {
+	if len(b.rows) == 0 {
+		_, err := io.WriteString(w, "\n")
+		return err
+	}
+
+	widths := b.widths
+	if widths == nil && r.opts.Align == renderer.AlignJustify {
+		var err error
+		if widths, err = renderer.Widths(b.line, r.banner, r.opts); err != nil {
+			return err
+		}
+	}
+
+	var out strings.Builder
+	for _, row := range renderer.AlignRows(b.rows, b.line, widths, target, r.opts.Align) {
+		out.WriteString(row)
+		out.WriteString("\n")
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}